@@ -1,18 +1,27 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/configs"
 	"github.com/surahj/ai-mentor-backend/app/library"
 	"github.com/surahj/ai-mentor-backend/app/models"
 )
 
+// jwtSecret verifies access tokens. It is set by Configure at startup.
+var jwtSecret configs.Secret
+
+// Configure sets the secret access tokens are verified with.
+func Configure(config configs.JWTConfig) {
+	jwtSecret = config.Secret
+}
+
 func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		authHeader := c.Request().Header.Get("Authorization")
@@ -27,9 +36,19 @@ func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
+			if jwtSecret == "" {
+				return nil, errors.New("JWT secret is not configured")
+			}
+			return []byte(jwtSecret), nil
 		})
 
+		if err != nil || !token.Valid {
+			return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				ErrorCode:    http.StatusUnauthorized,
+				ErrorMessage: "Invalid token",
+			})
+		}
+
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
 			return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
@@ -52,11 +71,22 @@ func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 				ErrorMessage: "Invalid user_id in token",
 			})
 		}
+
+		// tokens are bound to a session so they stop working once it is revoked
+		sessionIDFloat, ok := claims["sid"].(float64)
+		if !ok || !library.SessionActive(int64(sessionIDFloat), userID) {
+			return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				ErrorCode:    http.StatusUnauthorized,
+				ErrorMessage: "Session expired or revoked",
+			})
+		}
+
 		c.Set("user_id", userID)
+		c.Set("session_id", int64(sessionIDFloat))
 
 		log.Printf("User ID: %d", userID)
 
-		_, err = library.GetUserByID(userID)
+		user, err := library.GetUserByID(userID)
 
 		if err != nil {
 			return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
@@ -65,6 +95,45 @@ func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			})
 		}
 
+		if user.Suspended() {
+			return c.JSON(http.StatusForbidden, models.ErrorResponse{
+				ErrorCode:    http.StatusForbidden,
+				ErrorMessage: "Account suspended",
+			})
+		}
+		c.Set("user_role", user.Role)
+
 		return next(c)
 	}
 }
+
+// RequireRole only lets through users with one of the given roles. It must
+// run after Authenticate.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, ok := c.Get("user_role").(string)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+					ErrorCode:    http.StatusUnauthorized,
+					ErrorMessage: "Unauthorized",
+				})
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, models.ErrorResponse{
+				ErrorCode:    http.StatusForbidden,
+				ErrorMessage: "Insufficient role",
+			})
+		}
+	}
+}
+
+// RequireAdmin only lets through admins. It must run after Authenticate.
+func RequireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return RequireRole(models.RoleAdmin)(next)
+}
@@ -1,37 +1,468 @@
+// Package configs loads the service configuration. Every setting has a
+// default, can be set in a YAML file and is overridden by its environment
+// variable.
 package configs
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	DB struct {
-		Host     string
-		Port     int
-		User     string
-		Password string
-		Name     string
-		SSLMode  string
+	// Env is the deployment environment, e.g. dev or production
+	Env       string          `mapstructure:"env" json:"env"`
+	Server    ServerConfig    `mapstructure:"server" json:"server"`
+	CORS      CORSConfig      `mapstructure:"cors" json:"cors"`
+	DB        DBConfig        `mapstructure:"db" json:"db"`
+	JWT       JWTConfig       `mapstructure:"jwt" json:"jwt"`
+	OTP       OTPConfig       `mapstructure:"otp" json:"otp"`
+	Email     EmailConfig     `mapstructure:"email" json:"email"`
+	Google    GoogleConfig    `mapstructure:"google" json:"google"`
+	LLM       LLMConfig       `mapstructure:"llm" json:"llm"`
+	Jobs      JobsConfig      `mapstructure:"jobs" json:"jobs"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit" json:"rate_limit"`
+	// Quotas overrides the AI generation limits of user tiers
+	Quotas map[string]QuotaLimits `mapstructure:"quotas" json:"quotas"`
+	// AdminEmails are given the admin role at startup while no user has it
+	AdminEmails []string `mapstructure:"admin_emails" json:"admin_emails"`
+}
+
+type ServerConfig struct {
+	Host string `mapstructure:"host" json:"host"`
+	Port int    `mapstructure:"port" json:"port"`
+	// BaseURL is the public host shown in the API docs
+	BaseURL      string        `mapstructure:"base_url" json:"base_url"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout" json:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout" json:"write_timeout"`
+	// RequestTimeout bounds the time a handler may run
+	RequestTimeout time.Duration `mapstructure:"request_timeout" json:"request_timeout"`
+}
+
+type CORSConfig struct {
+	AllowOrigins []string `mapstructure:"allow_origins" json:"allow_origins"`
+}
+
+type DBConfig struct {
+	Host     string `mapstructure:"host" json:"host"`
+	Port     int    `mapstructure:"port" json:"port"`
+	User     string `mapstructure:"user" json:"user"`
+	Password Secret `mapstructure:"password" json:"password"`
+	Name     string `mapstructure:"name" json:"name"`
+	SSLMode  string `mapstructure:"ssl_mode" json:"ssl_mode"`
+	// AutoMigrate applies pending migrations at startup
+	AutoMigrate bool `mapstructure:"auto_migrate" json:"auto_migrate"`
+}
+
+type JWTConfig struct {
+	Secret     Secret        `mapstructure:"secret" json:"secret"`
+	AccessTTL  time.Duration `mapstructure:"access_ttl" json:"access_ttl"`
+	RefreshTTL time.Duration `mapstructure:"refresh_ttl" json:"refresh_ttl"`
+}
+
+type OTPConfig struct {
+	// Secret hashes stored codes; it defaults to the JWT secret
+	Secret         Secret        `mapstructure:"secret" json:"secret"`
+	TTL            time.Duration `mapstructure:"ttl" json:"ttl"`
+	MaxAttempts    int           `mapstructure:"max_attempts" json:"max_attempts"`
+	ResendCooldown time.Duration `mapstructure:"resend_cooldown" json:"resend_cooldown"`
+	Lockout        time.Duration `mapstructure:"lockout" json:"lockout"`
+}
+
+type EmailConfig struct {
+	// Provider is gmail; anything else logs emails instead of sending them
+	Provider         string `mapstructure:"provider" json:"provider"`
+	GmailSenderEmail string `mapstructure:"gmail_sender_email" json:"gmail_sender_email"`
+	GmailAppPassword Secret `mapstructure:"gmail_app_password" json:"gmail_app_password"`
+}
+
+type GoogleConfig struct {
+	ClientID     string `mapstructure:"client_id" json:"client_id"`
+	ClientSecret Secret `mapstructure:"client_secret" json:"client_secret"`
+	RedirectURL  string `mapstructure:"redirect_url" json:"redirect_url"`
+}
+
+type LLMConfig struct {
+	// Provider is openai, http or fake
+	Provider     string `mapstructure:"provider" json:"provider"`
+	OpenAIAPIKey Secret `mapstructure:"openai_api_key" json:"openai_api_key"`
+	// BaseURL, APIKey and Model configure the http provider
+	BaseURL string `mapstructure:"base_url" json:"base_url"`
+	APIKey  Secret `mapstructure:"api_key" json:"api_key"`
+	Model   string `mapstructure:"model" json:"model"`
+	// FixturesDir holds recorded responses for the fake provider
+	FixturesDir string `mapstructure:"fixtures_dir" json:"fixtures_dir"`
+	// JSONMaxRetries is how often invalid JSON output is sent back for repair
+	JSONMaxRetries int `mapstructure:"json_max_retries" json:"json_max_retries"`
+	// GradingMode grades free text answers: normalized, exact or llm
+	GradingMode string `mapstructure:"grading_mode" json:"grading_mode"`
+	// Prices overrides the USD cost per 1,000 tokens of models
+	Prices map[string]ModelPrice `mapstructure:"prices" json:"prices"`
+}
+
+type ModelPrice struct {
+	Prompt     float64 `mapstructure:"prompt" json:"prompt"`
+	Completion float64 `mapstructure:"completion" json:"completion"`
+}
+
+type JobsConfig struct {
+	Workers      int           `mapstructure:"workers" json:"workers"`
+	PollInterval time.Duration `mapstructure:"poll_interval" json:"poll_interval"`
+	Lease        time.Duration `mapstructure:"lease" json:"lease"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff" json:"retry_backoff"`
+	MaxAttempts  int           `mapstructure:"max_attempts" json:"max_attempts"`
+}
+
+type RateLimitConfig struct {
+	// Store keeps request limit counters: memory or postgres, which shares
+	// them between replicas. Generation quotas are always kept in postgres.
+	Store      string   `mapstructure:"store" json:"store"`
+	Auth       RateRule `mapstructure:"auth" json:"auth"`
+	Generation RateRule `mapstructure:"generation" json:"generation"`
+}
+
+// RateRule allows Limit requests per Window. It is written as
+// "<limit>/<window>", e.g. "10/1m".
+type RateRule struct {
+	Limit  int           `json:"limit"`
+	Window time.Duration `json:"window"`
+}
+
+// QuotaLimits caps the generations of a tier per day and month. Zero means
+// unlimited.
+type QuotaLimits struct {
+	Daily   int `mapstructure:"daily" json:"daily"`
+	Monthly int `mapstructure:"monthly" json:"monthly"`
+}
+
+// Secret is a setting that must not be logged. It prints and marshals as
+// [REDACTED] when set.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
 	}
+	return redacted
 }
 
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// setting is a configuration key with its default and the environment
+// variables read for it, the first set one winning.
+type setting struct {
+	key      string
+	fallback interface{}
+	env      []string
+}
+
+var settings = []setting{
+	{"env", "dev", []string{"ENV"}},
+
+	{"server.host", "0.0.0.0", []string{"SYSTEM_HOST"}},
+	{"server.port", 80, []string{"PORT", "SYSTEM_PORT"}},
+	{"server.base_url", "", []string{"BASE_URL"}},
+	{"server.read_timeout", "60s", []string{"SERVER_READ_TIMEOUT"}},
+	{"server.write_timeout", "5m", []string{"SERVER_WRITE_TIMEOUT"}},
+	{"server.request_timeout", "240s", []string{"REQUEST_TIMEOUT"}},
+
+	{"cors.allow_origins", "*", []string{"CORS_ALLOW_ORIGINS"}},
+
+	{"db.host", "", []string{"DB_HOST"}},
+	{"db.port", 5432, []string{"DB_PORT"}},
+	{"db.user", "", []string{"DB_USER"}},
+	{"db.password", "", []string{"DB_PASSWORD"}},
+	{"db.name", "", []string{"DB_NAME"}},
+	{"db.ssl_mode", "", []string{"DB_SSLMODE"}},
+	{"db.auto_migrate", true, []string{"DB_AUTO_MIGRATE"}},
+
+	{"jwt.secret", "", []string{"JWT_SECRET"}},
+	{"jwt.access_ttl", "15m", []string{"JWT_EXPIRY"}},
+	{"jwt.refresh_ttl", "720h", []string{"REFRESH_TOKEN_EXPIRY"}},
+
+	{"otp.secret", "", []string{"OTP_SECRET"}},
+	{"otp.ttl", "10m", []string{"OTP_TTL"}},
+	{"otp.max_attempts", 5, []string{"OTP_MAX_ATTEMPTS"}},
+	{"otp.resend_cooldown", "1m", []string{"OTP_RESEND_COOLDOWN"}},
+	{"otp.lockout", "15m", []string{"OTP_LOCKOUT"}},
+
+	{"email.provider", "", []string{"EMAIL_PROVIDER"}},
+	{"email.gmail_sender_email", "", []string{"GMAIL_SENDER_EMAIL"}},
+	{"email.gmail_app_password", "", []string{"GMAIL_APP_PASSWORD"}},
+
+	{"google.client_id", "", []string{"GOOGLE_CLIENT_ID"}},
+	{"google.client_secret", "", []string{"GOOGLE_CLIENT_SECRET"}},
+	{"google.redirect_url", "", []string{"GOOGLE_REDIRECT_URL"}},
+
+	{"llm.provider", "openai", []string{"LLM_PROVIDER"}},
+	{"llm.openai_api_key", "", []string{"OPENAI_API_KEY"}},
+	{"llm.base_url", "", []string{"LLM_BASE_URL"}},
+	{"llm.api_key", "", []string{"LLM_API_KEY"}},
+	{"llm.model", "", []string{"LLM_MODEL"}},
+	{"llm.fixtures_dir", "", []string{"LLM_FIXTURES_DIR"}},
+	{"llm.json_max_retries", 2, []string{"LLM_JSON_MAX_RETRIES"}},
+	{"llm.grading_mode", "normalized", []string{"EXERCISE_GRADING_MODE"}},
+	{"llm.prices", map[string]interface{}{}, []string{"LLM_PRICES"}},
+
+	{"jobs.workers", 2, []string{"JOB_WORKERS"}},
+	{"jobs.poll_interval", "1s", []string{"JOB_POLL_INTERVAL"}},
+	{"jobs.lease", "10m", []string{"JOB_LEASE"}},
+	{"jobs.retry_backoff", "5s", []string{"JOB_RETRY_BACKOFF"}},
+	{"jobs.max_attempts", 3, []string{"JOB_MAX_ATTEMPTS"}},
+
+	{"rate_limit.store", "memory", []string{"RATE_LIMIT_STORE"}},
+	{"rate_limit.auth", "10/1m", []string{"AUTH_RATE_LIMIT"}},
+	{"rate_limit.generation", "30/1m", []string{"GENERATION_RATE_LIMIT"}},
+
+	{"quotas", map[string]interface{}{}, []string{"GENERATION_QUOTAS"}},
+	{"admin_emails", "", []string{"ADMIN_EMAILS"}},
+}
+
+// Load reads the configuration. Defaults are overridden by the YAML file
+// named by CONFIG_FILE, or config.yaml in ./configs or ./app/configs, which
+// is in turn overridden by environment variables. Load does not validate the
+// result; see Validate.
 func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./configs")
-	viper.AutomaticEnv() // Read from environment variables
+	v := viper.New()
+	for _, s := range settings {
+		v.SetDefault(s.key, s.fallback)
+		if err := v.BindEnv(append([]string{s.key}, s.env...)...); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
+	if file := os.Getenv("CONFIG_FILE"); file != "" {
+		v.SetConfigFile(file)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath("./configs")
+		v.AddConfigPath("./app/configs")
+	}
+	if err := v.ReadInConfig(); err != nil {
 		// It's okay if the config file doesn't exist, we can rely on env vars
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, err
 		}
 	}
 
+	// JWT_EXPIRY used to be a number of hours
+	for _, key := range []string{"jwt.access_ttl", "jwt.refresh_ttl"} {
+		if hours, err := strconv.Atoi(strings.TrimSpace(v.GetString(key))); err == nil {
+			v.Set(key, fmt.Sprintf("%dh", hours))
+		}
+	}
+
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
-		return nil, err
+	if err := v.Unmarshal(&config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		jsonHook,
+		rateRuleHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		listHook,
+	))); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if config.OTP.Secret == "" {
+		config.OTP.Secret = config.JWT.Secret
 	}
 
 	return &config, nil
 }
+
+// Validate reports every missing or invalid setting at once.
+func (c *Config) Validate() error {
+	var problems []string
+	problems = append(problems, c.DB.problems()...)
+
+	if c.JWT.Secret == "" {
+		problems = append(problems, "JWT_SECRET (jwt.secret) is required")
+	}
+	problems = append(problems, positive("JWT_EXPIRY (jwt.access_ttl)", c.JWT.AccessTTL)...)
+	problems = append(problems, positive("REFRESH_TOKEN_EXPIRY (jwt.refresh_ttl)", c.JWT.RefreshTTL)...)
+
+	if c.Server.Port <= 0 {
+		problems = append(problems, "PORT (server.port) must be a positive number")
+	}
+	problems = append(problems, positive("REQUEST_TIMEOUT (server.request_timeout)", c.Server.RequestTimeout)...)
+	if len(c.CORS.AllowOrigins) == 0 {
+		problems = append(problems, "CORS_ALLOW_ORIGINS (cors.allow_origins) must list at least one origin")
+	}
+
+	if c.Email.Provider == "gmail" {
+		if c.Email.GmailSenderEmail == "" {
+			problems = append(problems, "GMAIL_SENDER_EMAIL (email.gmail_sender_email) is required for the gmail provider")
+		}
+		if c.Email.GmailAppPassword == "" {
+			problems = append(problems, "GMAIL_APP_PASSWORD (email.gmail_app_password) is required for the gmail provider")
+		}
+	}
+
+	if c.Google.ClientSecret != "" && c.Google.ClientID == "" {
+		problems = append(problems, "GOOGLE_CLIENT_ID (google.client_id) is required when GOOGLE_CLIENT_SECRET is set")
+	}
+
+	switch c.LLM.Provider {
+	case "openai":
+		if c.LLM.OpenAIAPIKey == "" {
+			problems = append(problems, "OPENAI_API_KEY (llm.openai_api_key) is required for the openai provider")
+		}
+	case "fake":
+	case "http":
+		if c.LLM.BaseURL == "" {
+			problems = append(problems, "LLM_BASE_URL (llm.base_url) is required for the http provider")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("LLM_PROVIDER (llm.provider) must be openai, http or fake, not '%s'", c.LLM.Provider))
+	}
+	switch c.LLM.GradingMode {
+	case "normalized", "exact", "llm":
+	default:
+		problems = append(problems, fmt.Sprintf("EXERCISE_GRADING_MODE (llm.grading_mode) must be normalized, exact or llm, not '%s'", c.LLM.GradingMode))
+	}
+	if c.LLM.JSONMaxRetries < 0 {
+		problems = append(problems, "LLM_JSON_MAX_RETRIES (llm.json_max_retries) must not be negative")
+	}
+
+	if c.Jobs.Workers <= 0 || c.Jobs.MaxAttempts <= 0 {
+		problems = append(problems, "JOB_WORKERS (jobs.workers) and JOB_MAX_ATTEMPTS (jobs.max_attempts) must be positive")
+	}
+	problems = append(problems, positive("JOB_POLL_INTERVAL (jobs.poll_interval)", c.Jobs.PollInterval)...)
+	problems = append(problems, positive("JOB_LEASE (jobs.lease)", c.Jobs.Lease)...)
+
+	switch c.RateLimit.Store {
+	case "memory", "postgres":
+	default:
+		problems = append(problems, fmt.Sprintf("RATE_LIMIT_STORE (rate_limit.store) must be memory or postgres, not '%s'", c.RateLimit.Store))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// Validate reports every missing database setting at once.
+func (c DBConfig) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func (c DBConfig) problems() []string {
+	var problems []string
+	if c.Host == "" {
+		problems = append(problems, "DB_HOST (db.host) is required")
+	}
+	if c.User == "" {
+		problems = append(problems, "DB_USER (db.user) is required")
+	}
+	if c.Name == "" {
+		problems = append(problems, "DB_NAME (db.name) is required")
+	}
+	if c.Port <= 0 {
+		problems = append(problems, "DB_PORT (db.port) must be a positive number")
+	}
+	return problems
+}
+
+// DSN is the Postgres connection string.
+func (c DBConfig) DSN() string {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
+		c.Host, c.Port, c.User, string(c.Password), c.Name,
+	)
+	if c.SSLMode != "" {
+		dsn += " sslmode=" + c.SSLMode
+	}
+	return dsn
+}
+
+// String prints the configuration as JSON with secrets redacted.
+func (c Config) String() string {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func positive(name string, d time.Duration) []string {
+	if d <= 0 {
+		return []string{name + " must be a positive duration such as 15m or 24h"}
+	}
+	return nil
+}
+
+// ParseRateRule parses "<limit>/<window>", e.g. "10/1m" or "100/1h".
+func ParseRateRule(s string) (RateRule, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return RateRule{}, fmt.Errorf("rate limit '%s' must look like 10/1m", s)
+	}
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit <= 0 {
+		return RateRule{}, fmt.Errorf("rate limit '%s' has an invalid limit", s)
+	}
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return RateRule{}, fmt.Errorf("rate limit '%s' has an invalid window", s)
+	}
+	return RateRule{Limit: limit, Window: window}, nil
+}
+
+func rateRuleHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(RateRule{}) {
+		return data, nil
+	}
+	return ParseRateRule(data.(string))
+}
+
+// jsonHook decodes maps given as a JSON string, as environment variables
+// such as GENERATION_QUOTAS are.
+func jsonHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+		return data, nil
+	}
+	s := strings.TrimSpace(data.(string))
+	if s == "" {
+		return map[string]interface{}{}, nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil, fmt.Errorf("must be a JSON object: %w", err)
+	}
+	return m, nil
+}
+
+var listSeparator = regexp.MustCompile(`\s*,\s*`)
+
+// listHook splits comma-separated strings into lists, dropping empty items.
+func listHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Slice {
+		return data, nil
+	}
+	var items []string
+	for _, item := range listSeparator.Split(strings.TrimSpace(data.(string)), -1) {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,29 @@
+package configs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOpenAIKey(t *testing.T) {
+	const problem = "OPENAI_API_KEY (llm.openai_api_key) is required for the openai provider"
+
+	tests := []struct {
+		name string
+		llm  LLMConfig
+		want bool
+	}{
+		{name: "openai without a key", llm: LLMConfig{Provider: "openai"}, want: true},
+		{name: "openai with a key", llm: LLMConfig{Provider: "openai", OpenAIAPIKey: "sk-test"}},
+		{name: "fake provider", llm: LLMConfig{Provider: "fake"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{LLM: tt.llm}
+			err := config.Validate()
+			if got := err != nil && strings.Contains(err.Error(), problem); got != tt.want {
+				t.Errorf("Validate() = %v, want the missing key reported: %v", err, tt.want)
+			}
+		})
+	}
+}
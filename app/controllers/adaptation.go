@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/gorm"
+)
+
+// adaptationCooldown is how long a week adapted for a reason is left alone
+// before the same reason may adapt it again. The progress that triggered the
+// adaptation keeps firing the rule until the learner works through the new
+// content, and each flag regenerates the week.
+const adaptationCooldown = 7 * 24 * time.Hour
+
+// GetAdaptations lists the adaptation flags raised for a plan.
+func (c *Controller) GetAdaptations(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, _ := strconv.ParseInt(ctx.Param("plan_id"), 10, 64)
+	if planID == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID is required",
+		})
+	}
+
+	var flags []models.ContentAdaptationFlag
+	if err := c.DB.Where("plan_id = ? AND user_id = ?", planID, userID).Order("created_at DESC").Find(&flags).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch adaptation flags"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Adaptation flags fetched successfully",
+		Data:    flags,
+	})
+}
+
+// evaluateAdaptation runs the plan's adaptive rules against the learner's
+// progress and raises a flag on the next upcoming week when they fire.
+// Failures are logged; adaptation never blocks the learner's request.
+func (c *Controller) evaluateAdaptation(userID, planID int64) {
+	var plan models.LearningPlanStructure
+	if err := c.DB.Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
+		log.Printf("Adaptation: plan %d not found: %v", planID, err)
+		return
+	}
+
+	var structure models.CompleteLearningPlan
+	if err := json.Unmarshal(plan.Structure, &structure); err != nil {
+		log.Printf("Adaptation: failed to parse plan %d structure: %v", planID, err)
+		return
+	}
+
+	progress, err := c.progressSnapshot(userID, planID)
+	if err != nil {
+		log.Printf("Adaptation: failed to build progress snapshot: %v", err)
+		return
+	}
+
+	decision := utils.EvaluateAdaptation(structure, plan.CreatedAt, progress, time.Now())
+	if decision == nil {
+		return
+	}
+
+	currentWeek := progress.LastWeekNumber
+	if progress.RecentWeekNumber > currentWeek {
+		currentWeek = progress.RecentWeekNumber
+	}
+	targetWeek := currentWeek + 1
+	if targetWeek > plan.TotalWeeks {
+		return
+	}
+
+	existing, err := c.Learning.AdaptationFlag(userID, planID, targetWeek)
+	if err != nil {
+		log.Printf("Adaptation: failed to fetch flags: %v", err)
+		return
+	}
+	if existing != nil {
+		if existing.Kind != decision.Kind {
+			existing.Kind = decision.Kind
+			existing.Reason = decision.Reason
+			c.DB.Save(existing)
+		}
+		return
+	}
+
+	var recent int64
+	if err := c.DB.Model(&models.ContentAdaptationFlag{}).
+		Where("plan_id = ? AND user_id = ? AND week_number = ? AND kind = ? AND resolved_at > ?",
+			planID, userID, targetWeek, decision.Kind, time.Now().Add(-adaptationCooldown)).
+		Count(&recent).Error; err != nil {
+		log.Printf("Adaptation: failed to fetch resolved flags: %v", err)
+		return
+	}
+	if recent > 0 {
+		return
+	}
+
+	flag := models.ContentAdaptationFlag{
+		PlanID:            planID,
+		UserID:            userID,
+		WeekNumber:        targetWeek,
+		Kind:              decision.Kind,
+		NeedsRegeneration: true,
+		Reason:            decision.Reason,
+	}
+	if err := c.Learning.FlagWeek(&flag); err != nil {
+		log.Printf("Adaptation: failed to save flag: %v", err)
+		return
+	}
+	log.Printf("Adaptation: flagged plan %d week %d as %s: %s", planID, targetWeek, decision.Kind, decision.Reason)
+
+	// a week that was already generated is replaced in the background and
+	// served, marked stale, until then; one that was not is adapted when it
+	// is first generated
+	if _, err := c.Learning.CurrentWeek(userID, planID, targetWeek); err != nil {
+		return
+	}
+	if _, _, err := c.Learning.GenerateWeek(userID, planID, targetWeek); err != nil {
+		log.Printf("Adaptation: failed to queue the regeneration of plan %d week %d: %v", planID, targetWeek, err)
+	}
+}
+
+func (c *Controller) resolveAdaptationFlag(flag *models.ContentAdaptationFlag) {
+	now := time.Now()
+	flag.NeedsRegeneration = false
+	flag.ResolvedAt = &now
+	if err := c.DB.Save(flag).Error; err != nil {
+		log.Printf("Adaptation: failed to resolve flag %d: %v", flag.ID, err)
+	}
+}
+
+// replaceWeekContent supersedes the previous version of a week with a newly
+// generated one, drops daily content built from the old version and resolves
+// the adaptation flag that triggered the regeneration.
+func (c *Controller) replaceWeekContent(previous models.GeneratedWeeklyContent, next *models.GeneratedWeeklyContent, flag *models.ContentAdaptationFlag) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&models.GeneratedWeeklyContent{}).Where("id = ?", previous.ID).Update("superseded_at", now).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		// days the learner has not started yet are regenerated from the new version
+		if err := tx.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number NOT IN (?)",
+			next.PlanID, next.UserID, next.WeekNumber,
+			tx.Model(&models.LessonProgress{}).Select("day_number").
+				Where("plan_id = ? AND user_id = ? AND week_number = ?", next.PlanID, next.UserID, next.WeekNumber),
+		).Delete(&models.DailyContent{}).Error; err != nil {
+			return err
+		}
+
+		if flag != nil {
+			flag.NeedsRegeneration = false
+			flag.ResolvedAt = &now
+			if err := tx.Save(flag).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
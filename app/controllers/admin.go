@@ -0,0 +1,498 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/gorm"
+)
+
+const (
+	adminPageSize    = 50
+	adminMaxPageSize = 200
+)
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" example:"mentor"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" example:"Abusive content"`
+}
+
+// RegenerateRequest selects what to regenerate. Without a week every
+// generated week of the plan is regenerated; with a week and a day only that
+// day is.
+type RegenerateRequest struct {
+	WeekNumber int    `json:"week_number" example:"2"`
+	DayNumber  int    `json:"day_number" example:"3"`
+	Note       string `json:"note" example:"Content reported as outdated"`
+}
+
+// AdminUser is a user as listed by the admin API.
+type AdminUser struct {
+	ID               int64      `json:"id"`
+	Email            string     `json:"email"`
+	FirstName        *string    `json:"first_name"`
+	LastName         *string    `json:"last_name"`
+	Role             string     `json:"role"`
+	Tier             string     `json:"tier"`
+	IsVerified       bool       `json:"is_verified"`
+	AuthProvider     string     `json:"auth_provider"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason *string    `json:"suspension_reason,omitempty"`
+	PlanCount        int64      `json:"plan_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// AdminPlan is a plan with everything generated and recorded for it.
+type AdminPlan struct {
+	Plan            models.LearningPlanStructure    `json:"plan"`
+	Weeks           []models.GeneratedWeeklyContent `json:"weeks"`
+	Days            []models.DailyContent           `json:"days"`
+	Progress        models.ProgressSnapshot         `json:"progress"`
+	AdaptationFlags []models.ContentAdaptationFlag  `json:"adaptation_flags"`
+}
+
+func adminUser(user models.User, planCount int64) AdminUser {
+	return AdminUser{
+		ID:               user.ID,
+		Email:            user.Email,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		Role:             user.Role,
+		Tier:             user.Tier,
+		IsVerified:       user.IsVerified,
+		AuthProvider:     user.AuthProvider,
+		SuspendedAt:      user.SuspendedAt,
+		SuspensionReason: user.SuspensionReason,
+		PlanCount:        planCount,
+		CreatedAt:        user.CreatedAt,
+	}
+}
+
+// adminPage reads the page and limit query parameters.
+func adminPage(ctx echo.Context) (offset, limit int) {
+	limit, _ = strconv.Atoi(ctx.QueryParam("limit"))
+	if limit <= 0 {
+		limit = adminPageSize
+	}
+	if limit > adminMaxPageSize {
+		limit = adminMaxPageSize
+	}
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+	return (page - 1) * limit, limit
+}
+
+// ListUsers searches users. q matches the email and names, role and status
+// (active or suspended) filter the results, page and limit paginate them.
+// GET /admin/users
+func (c *Controller) ListUsers(ctx echo.Context) error {
+	query := c.DB.Model(&models.User{})
+
+	if q := strings.TrimSpace(ctx.QueryParam("q")); q != "" {
+		like := "%" + q + "%"
+		query = query.Where("email ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ?", like, like, like)
+	}
+	if role := ctx.QueryParam("role"); role != "" {
+		if !models.ValidRole(role) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Role must be learner, mentor or admin"})
+		}
+		query = query.Where("role = ?", role)
+	}
+	switch ctx.QueryParam("status") {
+	case "":
+	case "active":
+		query = query.Where("suspended_at IS NULL")
+	case "suspended":
+		query = query.Where("suspended_at IS NOT NULL")
+	default:
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Status must be active or suspended"})
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch users"})
+	}
+
+	offset, limit := adminPage(ctx)
+	var users []models.User
+	if err := query.Order("id").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch users"})
+	}
+
+	ids := make([]int64, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	var counts []struct {
+		UserID int64
+		Count  int64
+	}
+	if len(ids) > 0 {
+		if err := c.DB.Model(&models.LearningPlanStructure{}).Select("user_id, COUNT(*) AS count").
+			Where("user_id IN ?", ids).Group("user_id").Scan(&counts).Error; err != nil {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch users"})
+		}
+	}
+	planCounts := make(map[int64]int64, len(counts))
+	for _, row := range counts {
+		planCounts[row.UserID] = row.Count
+	}
+
+	result := make([]AdminUser, len(users))
+	for i, u := range users {
+		result[i] = adminUser(u, planCounts[u.ID])
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Users fetched successfully",
+		Data: map[string]interface{}{
+			"users": result,
+			"total": total,
+			"limit": limit,
+		},
+	})
+}
+
+// GET /admin/users/:id
+func (c *Controller) GetUser(ctx echo.Context) error {
+	user, ok := c.adminTargetUser(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	}
+
+	var plans []models.LearningPlanStructure
+	if err := c.DB.Where("user_id = ?", user.ID).Order("created_at DESC").Find(&plans).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch plans"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "User fetched successfully",
+		Data: map[string]interface{}{
+			"user":  adminUser(user, int64(len(plans))),
+			"plans": plans,
+		},
+	})
+}
+
+// PUT /admin/users/:id/role
+func (c *Controller) UpdateUserRole(ctx echo.Context) error {
+	var req UpdateRoleRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if !models.ValidRole(req.Role) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Role must be learner, mentor or admin"})
+	}
+
+	user, ok := c.adminTargetUser(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	}
+	adminID, _ := library.GetUserIDFronContext(ctx)
+	if user.ID == adminID && req.Role != models.RoleAdmin {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "You cannot remove your own admin role"})
+	}
+
+	if err := c.DB.Model(&user).Update("role", req.Role).Error; err != nil {
+		log.Printf("Failed to update role of user %d: %v", user.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update role"})
+	}
+	log.Printf("Admin %d set the role of user %d to %s", adminID, user.ID, req.Role)
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Role updated successfully",
+		Data:    adminUser(user, 0),
+	})
+}
+
+// SuspendUser blocks a user from signing in and revokes their sessions, so
+// their access tokens stop working immediately.
+// POST /admin/users/:id/suspend
+func (c *Controller) SuspendUser(ctx echo.Context) error {
+	var req SuspendUserRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	user, ok := c.adminTargetUser(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	}
+	adminID, _ := library.GetUserIDFronContext(ctx)
+	if user.ID == adminID {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "You cannot suspend yourself"})
+	}
+
+	now := time.Now()
+	var reason *string
+	if r := strings.TrimSpace(req.Reason); r != "" {
+		reason = &r
+	}
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{"suspended_at": now, "suspension_reason": reason}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", user.ID).Update("revoked_at", now).Error
+	})
+	if err != nil {
+		log.Printf("Failed to suspend user %d: %v", user.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to suspend user"})
+	}
+	log.Printf("Admin %d suspended user %d", adminID, user.ID)
+
+	user.SuspendedAt, user.SuspensionReason = &now, reason
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "User suspended successfully",
+		Data:    adminUser(user, 0),
+	})
+}
+
+// POST /admin/users/:id/unsuspend
+func (c *Controller) UnsuspendUser(ctx echo.Context) error {
+	user, ok := c.adminTargetUser(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	}
+
+	if err := c.DB.Model(&user).Updates(map[string]interface{}{"suspended_at": nil, "suspension_reason": nil}).Error; err != nil {
+		log.Printf("Failed to unsuspend user %d: %v", user.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to unsuspend user"})
+	}
+
+	user.SuspendedAt, user.SuspensionReason = nil, nil
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "User unsuspended successfully",
+		Data:    adminUser(user, 0),
+	})
+}
+
+// AdminListPlans lists plans of all users, optionally filtered by user_id and
+// by q, a search over the goal.
+// GET /admin/plans
+func (c *Controller) AdminListPlans(ctx echo.Context) error {
+	query := c.DB.Model(&models.LearningPlanStructure{})
+
+	if v := ctx.QueryParam("user_id"); v != "" {
+		userID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user_id"})
+		}
+		query = query.Where("user_id = ?", userID)
+	}
+	if q := strings.TrimSpace(ctx.QueryParam("q")); q != "" {
+		query = query.Where("goal ILIKE ?", "%"+q+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch plans"})
+	}
+
+	offset, limit := adminPage(ctx)
+	var plans []models.LearningPlanStructure
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&plans).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch plans"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Plans fetched successfully",
+		Data: map[string]interface{}{
+			"plans": plans,
+			"total": total,
+			"limit": limit,
+		},
+	})
+}
+
+// GET /admin/plans/:id
+func (c *Controller) AdminGetPlan(ctx echo.Context) error {
+	plan, ok := c.adminTargetPlan(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+	}
+
+	result := AdminPlan{Plan: plan}
+	if err := c.DB.Where("plan_id = ?", plan.ID).Order("week_number, version").Find(&result.Weeks).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch weekly content"})
+	}
+	if err := c.DB.Where("plan_id = ?", plan.ID).Order("week_number, day_number").Find(&result.Days).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch daily content"})
+	}
+	if err := c.DB.Where("plan_id = ?", plan.ID).Order("created_at DESC").Find(&result.AdaptationFlags).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch adaptation flags"})
+	}
+	progress, err := c.progressSnapshot(plan.UserID, plan.ID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch progress"})
+	}
+	result.Progress = progress
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Plan fetched successfully",
+		Data:    result,
+	})
+}
+
+// DELETE /admin/plans/:id
+func (c *Controller) AdminDeletePlan(ctx echo.Context) error {
+	plan, ok := c.adminTargetPlan(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+	}
+
+	if err := c.Learning.DeletePlan(plan.UserID, plan.ID); err != nil {
+		if errors.Is(err, learning.ErrNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+		}
+		log.Printf("Failed to delete plan %d: %v", plan.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete plan and its associated data"})
+	}
+	adminID, _ := library.GetUserIDFronContext(ctx)
+	log.Printf("Admin %d deleted plan %d of user %d", adminID, plan.ID, plan.UserID)
+
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Plan deleted successfully"})
+}
+
+// RegeneratePlanContent forces content of a plan to be generated again on
+// behalf of its owner. A day is deleted and regenerated; a week is flagged
+// for manual regeneration, which supersedes it and drops the days the learner
+// has not started. Regeneration does not count against the owner's quota.
+// POST /admin/plans/:id/regenerate
+func (c *Controller) RegeneratePlanContent(ctx echo.Context) error {
+	var req RegenerateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if req.DayNumber != 0 && req.WeekNumber == 0 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "week_number is required with day_number"})
+	}
+	if req.WeekNumber < 0 || req.DayNumber < 0 || req.DayNumber > 7 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid week_number or day_number"})
+	}
+
+	plan, ok := c.adminTargetPlan(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found"})
+	}
+	if req.WeekNumber > plan.TotalWeeks {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "week_number is beyond the plan"})
+	}
+
+	reason := strings.TrimSpace(req.Note)
+	if reason == "" {
+		reason = "Regeneration requested by an administrator"
+	}
+
+	if req.DayNumber != 0 {
+		if err := c.Learning.DeleteLesson(plan.UserID, plan.ID, req.WeekNumber, req.DayNumber); err != nil {
+			log.Printf("Failed to delete daily content for regeneration: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start regeneration"})
+		}
+		job, created, err := c.Learning.GenerateDay(plan.UserID, plan.ID, req.WeekNumber, req.DayNumber)
+		if err != nil {
+			log.Printf("Failed to enqueue daily content job: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start regeneration"})
+		}
+		return respondJobAccepted(ctx, job, created)
+	}
+
+	weeks := []int{req.WeekNumber}
+	if req.WeekNumber == 0 {
+		weeks = nil
+		if err := c.DB.Model(&models.GeneratedWeeklyContent{}).
+			Where("plan_id = ? AND user_id = ? AND superseded_at IS NULL", plan.ID, plan.UserID).
+			Order("week_number").Pluck("week_number", &weeks).Error; err != nil {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch weekly content"})
+		}
+		if len(weeks) == 0 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "The plan has no generated weeks"})
+		}
+	}
+
+	var started []*models.GenerationJob
+	created := false
+	for _, week := range weeks {
+		flag, err := c.Learning.AdaptationFlag(plan.UserID, plan.ID, week)
+		if err != nil {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start regeneration"})
+		}
+		if flag == nil {
+			flag = &models.ContentAdaptationFlag{
+				PlanID:            plan.ID,
+				UserID:            plan.UserID,
+				WeekNumber:        week,
+				Kind:              models.AdaptationManual,
+				NeedsRegeneration: true,
+				Reason:            reason,
+			}
+			if err := c.Learning.FlagWeek(flag); err != nil {
+				log.Printf("Failed to flag week %d of plan %d: %v", week, plan.ID, err)
+				return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start regeneration"})
+			}
+		}
+
+		job, jobCreated, err := c.Learning.GenerateWeek(plan.UserID, plan.ID, week)
+		if err != nil {
+			log.Printf("Failed to enqueue weekly content job: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start regeneration"})
+		}
+		started = append(started, job)
+		created = created || jobCreated
+	}
+
+	adminID, _ := library.GetUserIDFronContext(ctx)
+	log.Printf("Admin %d requested regeneration of plan %d weeks %v", adminID, plan.ID, weeks)
+
+	if len(started) == 1 {
+		return respondJobAccepted(ctx, started[0], created)
+	}
+	message := "Generation started"
+	if !created {
+		message = "Generation already in progress"
+	}
+	return ctx.JSON(http.StatusAccepted, models.SuccessResponse{
+		Status:  http.StatusAccepted,
+		Message: message,
+		Data:    started,
+	})
+}
+
+// adminTargetUser loads the user named by the id path parameter.
+func (c *Controller) adminTargetUser(ctx echo.Context) (models.User, bool) {
+	var user models.User
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return user, false
+	}
+	return user, c.DB.First(&user, id).Error == nil
+}
+
+// adminTargetPlan loads the plan named by the id path parameter.
+func (c *Controller) adminTargetPlan(ctx echo.Context) (models.LearningPlanStructure, bool) {
+	var plan models.LearningPlanStructure
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return plan, false
+	}
+	return plan, c.DB.First(&plan, id).Error == nil
+}
@@ -2,17 +2,20 @@ package controllers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
-	"math/rand"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/otp"
+	"github.com/surahj/ai-mentor-backend/app/services"
 	"github.com/surahj/ai-mentor-backend/app/utils"
 	"google.golang.org/api/idtoken"
+	"gorm.io/gorm"
 )
 
 func (c *Controller) Register(ctx echo.Context) error {
@@ -74,30 +77,28 @@ func (c *Controller) Register(ctx echo.Context) error {
 		})
 	}
 
-	// Generate OTP
-	rand.Seed(time.Now().UnixNano())
-	otp := fmt.Sprintf("%06d", rand.Intn(1000000))
-	otpExpiresAt := time.Now().Add(10 * time.Minute) // OTP valid for 10 minutes
-
 	user := models.User{
 		FirstName:       &req.FirstName,
 		LastName:        &req.LastName,
 		Email:           req.Email,
 		Password:        &hashedPass,
 		IsVerified:      false,
-		OTP:             &otp,
-		OTPExpiresAt:    &otpExpiresAt,
 		LearningGoal:    req.LearningGoal,
 		DailyCommitment: req.DailyCommitment,
 		AuthProvider:    "email",
 	}
 
-	// If user exists but is not verified, update their record with new OTP.
-	// Otherwise, create a new user record.
+	// If user exists but is not verified, issue a new OTP before updating their
+	// record, so a request refused by the resend cooldown or lockout cannot
+	// change the password. Otherwise, create a new user record.
+	var otp string
 	if result.Error == nil { // User found
+		otp, err = c.OTP.Issue(existingUser.ID, models.OTPPurposeVerification)
+		if err != nil {
+			return otpErrorResponse(ctx, err)
+		}
+
 		existingUser.Password = &hashedPass
-		existingUser.OTP = &otp
-		existingUser.OTPExpiresAt = &otpExpiresAt
 		existingUser.FirstName = &req.FirstName
 		existingUser.LastName = &req.LastName
 		existingUser.LearningGoal = req.LearningGoal
@@ -108,6 +109,7 @@ func (c *Controller) Register(ctx echo.Context) error {
 				ErrorMessage: "Failed to update user",
 			})
 		}
+		user = existingUser
 	} else { // User not found, create new
 		if err := c.DB.Create(&user).Error; err != nil {
 			return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -115,11 +117,15 @@ func (c *Controller) Register(ctx echo.Context) error {
 				ErrorMessage: "Failed to create user",
 			})
 		}
+
+		otp, err = c.OTP.Issue(user.ID, models.OTPPurposeVerification)
+		if err != nil {
+			return otpErrorResponse(ctx, err)
+		}
 	}
 
 	// Send OTP email
-	emailBody := fmt.Sprintf("Hi %s, <br><br>Your One-Time Password (OTP) for AI-Mentor is: <strong>%s</strong>.<br><br>This OTP is valid for 10 minutes. Please use it to complete your registration.<br><br>Thanks,<br>The AI-Mentor Team", *user.FirstName, otp)
-	err = c.EmailClient.SendEmail(user.Email, "Your AI-Mentor OTP", emailBody)
+	err = c.sendUserEmail(user, services.EmailVerification, otp)
 	if err != nil {
 		log.Printf("Failed to send OTP email to %s: %v", user.Email, err)
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -180,7 +186,11 @@ func (c *Controller) Login(ctx echo.Context) error {
 		})
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	if ok, err := allowSignIn(ctx, user); !ok {
+		return err
+	}
+
+	tokens, err := c.issueTokens(ctx, user.ID)
 	if err != nil {
 		log.Printf("Error generating token: %v", err)
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -189,15 +199,7 @@ func (c *Controller) Login(ctx echo.Context) error {
 		})
 	}
 
-	userData := map[string]interface{}{
-		"token": token,
-		"user": map[string]interface{}{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-		},
-	}
+	userData := authResponse(tokens, user)
 
 	return ctx.JSON(http.StatusOK, models.SuccessResponse{
 		Message: "Login successful",
@@ -234,25 +236,11 @@ func (c *Controller) VerifyOTP(ctx echo.Context) error {
 		})
 	}
 
-	if user.OTP == nil || *user.OTP != req.OTP {
-		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			ErrorCode:    http.StatusBadRequest,
-			ErrorMessage: "Invalid OTP.",
-		})
-	}
-
-	if user.OTPExpiresAt == nil || time.Now().After(*user.OTPExpiresAt) {
-		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			ErrorCode:    http.StatusBadRequest,
-			ErrorMessage: "OTP has expired.",
-		})
+	if err := c.OTP.Verify(user.ID, models.OTPPurposeVerification, req.OTP); err != nil {
+		return otpErrorResponse(ctx, err)
 	}
 
 	user.IsVerified = true
-	emptyString := ""
-	var nilTime *time.Time
-	user.OTP = &emptyString
-	user.OTPExpiresAt = nilTime
 	if err := c.DB.Save(&user).Error; err != nil {
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
@@ -260,7 +248,11 @@ func (c *Controller) VerifyOTP(ctx echo.Context) error {
 		})
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	if ok, err := allowSignIn(ctx, user); !ok {
+		return err
+	}
+
+	tokens, err := c.issueTokens(ctx, user.ID)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
@@ -268,15 +260,7 @@ func (c *Controller) VerifyOTP(ctx echo.Context) error {
 		})
 	}
 
-	userData := map[string]interface{}{
-		"token": token,
-		"user": map[string]interface{}{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-		},
-	}
+	userData := authResponse(tokens, user)
 
 	return ctx.JSON(http.StatusOK, models.SuccessResponse{
 		Message: "Account verified successfully.",
@@ -312,21 +296,12 @@ func (c *Controller) ResendOTP(ctx echo.Context) error {
 		})
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	otp := fmt.Sprintf("%06d", rand.Intn(1000000))
-	otpExpiresAt := time.Now().Add(10 * time.Minute)
-
-	user.OTP = &otp
-	user.OTPExpiresAt = &otpExpiresAt
-	if err := c.DB.Save(&user).Error; err != nil {
-		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			ErrorCode:    http.StatusInternalServerError,
-			ErrorMessage: "Failed to update OTP.",
-		})
+	otp, err := c.OTP.Issue(user.ID, models.OTPPurposeVerification)
+	if err != nil {
+		return otpErrorResponse(ctx, err)
 	}
 
-	emailBody := fmt.Sprintf("Your new AI-Mentor OTP is: %s", otp)
-	if err := c.EmailClient.SendEmail(user.Email, "Your New AI-Mentor OTP", emailBody); err != nil {
+	if err := c.sendUserEmail(user, services.EmailResendOTP, otp); err != nil {
 		log.Printf("Failed to resend OTP email to %s: %v", user.Email, err)
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
@@ -367,21 +342,12 @@ func (c *Controller) ForgotPassword(ctx echo.Context) error {
 		})
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	otp := fmt.Sprintf("%06d", rand.Intn(1000000))
-	otpExpiresAt := time.Now().Add(10 * time.Minute)
-
-	user.OTP = &otp
-	user.OTPExpiresAt = &otpExpiresAt
-	if err := c.DB.Save(&user).Error; err != nil {
-		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			ErrorCode:    http.StatusInternalServerError,
-			ErrorMessage: "Failed to generate reset token.",
-		})
+	otp, err := c.OTP.Issue(user.ID, models.OTPPurposePasswordReset)
+	if err != nil {
+		return otpErrorResponse(ctx, err)
 	}
 
-	emailBody := fmt.Sprintf("Your password reset OTP is: %s", otp)
-	if err := c.EmailClient.SendEmail(user.Email, "Your Password Reset OTP", emailBody); err != nil {
+	if err := c.sendUserEmail(user, services.EmailPasswordReset, otp); err != nil {
 		log.Printf("Failed to send password reset email to %s: %v", user.Email, err)
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
@@ -412,18 +378,15 @@ func (c *Controller) ResetPassword(ctx echo.Context) error {
 		})
 	}
 
-	if user.OTP == nil || *user.OTP != req.OTP {
+	if req.Password == "" {
 		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
 			ErrorCode:    http.StatusBadRequest,
-			ErrorMessage: "Invalid or expired OTP.",
+			ErrorMessage: "Password is required.",
 		})
 	}
 
-	if user.OTPExpiresAt == nil || time.Now().After(*user.OTPExpiresAt) {
-		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
-			ErrorCode:    http.StatusBadRequest,
-			ErrorMessage: "OTP has expired.",
-		})
+	if err := c.OTP.Verify(user.ID, models.OTPPurposePasswordReset, req.OTP); err != nil {
+		return otpErrorResponse(ctx, err)
 	}
 
 	hashedPass, err := utils.HashPassword(req.Password)
@@ -435,11 +398,14 @@ func (c *Controller) ResetPassword(ctx echo.Context) error {
 	}
 
 	user.Password = &hashedPass
-	emptyString := ""
-	var nilTime *time.Time
-	user.OTP = &emptyString
-	user.OTPExpiresAt = nilTime
-	if err := c.DB.Save(&user).Error; err != nil {
+	// a password reset signs out every device
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		return c.revokeUserSessions(tx, user.ID)
+	})
+	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
 			ErrorMessage: "Failed to reset password.",
@@ -465,7 +431,7 @@ func (c *Controller) GoogleLogin(ctx echo.Context) error {
 		})
 	}
 
-	googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
+	googleClientID := c.Config.Google.ClientID
 	if googleClientID == "" {
 		log.Println("Google Client ID is not configured")
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -492,8 +458,14 @@ func (c *Controller) GoogleLogin(ctx echo.Context) error {
 
 	if result.Error != nil { // User does not exist, create them
 		// Generate a random password for Google users
-		rand.Seed(time.Now().UnixNano())
-		randomPassword := fmt.Sprintf("google_%d_%d", time.Now().Unix(), rand.Intn(1000000))
+		randomPassword, err := utils.GenerateRandomToken()
+		if err != nil {
+			log.Printf("Error generating password for Google user: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				ErrorCode:    http.StatusInternalServerError,
+				ErrorMessage: "Failed to create user account.",
+			})
+		}
 		hashedPassword, err := utils.HashPassword(randomPassword)
 		if err != nil {
 			log.Printf("Error hashing password for Google user: %v", err)
@@ -531,7 +503,11 @@ func (c *Controller) GoogleLogin(ctx echo.Context) error {
 		}
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	if ok, err := allowSignIn(ctx, user); !ok {
+		return err
+	}
+
+	tokens, err := c.issueTokens(ctx, user.ID)
 	if err != nil {
 		log.Printf("Error generating token for Google user: %v", err)
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -540,18 +516,47 @@ func (c *Controller) GoogleLogin(ctx echo.Context) error {
 		})
 	}
 
-	userData := map[string]interface{}{
-		"token": token,
-		"user": map[string]interface{}{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-		},
-	}
+	userData := authResponse(tokens, user)
 
 	return ctx.JSON(http.StatusOK, models.SuccessResponse{
 		Message: "Login successful",
 		Data:    userData,
 	})
 }
+
+// otpErrorResponse maps one-time code failures to responses. Cooldowns and
+// lockouts answer 429 with Retry-After.
+func otpErrorResponse(ctx echo.Context, err error) error {
+	var cooldown *otp.CooldownError
+	var locked *otp.LockedError
+	switch {
+	case errors.As(err, &cooldown):
+		ctx.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(cooldown.RetryAfter.Seconds()))))
+		return ctx.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			ErrorCode:    http.StatusTooManyRequests,
+			ErrorMessage: "An OTP was sent recently. Please wait before requesting another.",
+		})
+	case errors.As(err, &locked):
+		ctx.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(locked.Until).Seconds()))))
+		return ctx.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			ErrorCode:    http.StatusTooManyRequests,
+			ErrorMessage: "Too many failed attempts. Please try again later.",
+		})
+	case errors.Is(err, otp.ErrInvalidCode):
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Invalid OTP.",
+		})
+	case errors.Is(err, otp.ErrExpired):
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "OTP has expired.",
+		})
+	default:
+		log.Printf("OTP error: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to process OTP.",
+		})
+	}
+}
@@ -0,0 +1,592 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type CategoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PlanTemplateRequest struct {
+	// PlanID seeds the template from an existing plan structure; when set the
+	// goal, total weeks and structure default to the plan's.
+	PlanID          int64           `json:"plan_id"`
+	CategoryID      *int64          `json:"category_id"`
+	Title           string          `json:"title"`
+	Goal            string          `json:"goal"`
+	Description     string          `json:"description"`
+	Level           string          `json:"level"`
+	TotalWeeks      int             `json:"total_weeks"`
+	DailyCommitment int             `json:"daily_commitment"`
+	Structure       json.RawMessage `json:"structure" swaggertype:"object"`
+}
+
+type PublishTemplateRequest struct {
+	Published bool `json:"published"`
+}
+
+type StartTemplateRequest struct {
+	// DailyCommitment in minutes; defaults to the user's profile setting
+	DailyCommitment int `json:"daily_commitment"`
+}
+
+type RatePlanRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+func validLevel(level string) bool {
+	switch level {
+	case models.LevelBeginner, models.LevelIntermediate, models.LevelAdvanced:
+		return true
+	}
+	return false
+}
+
+// GET /catalog/categories
+func (c *Controller) ListCategories(ctx echo.Context) error {
+	var categories []models.Category
+	if err := c.DB.Order("name").Find(&categories).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch categories"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Categories fetched successfully",
+		Data:    categories,
+	})
+}
+
+// ListTemplates browses published templates. Supported filters are
+// category_id, level, min_weeks, max_weeks and q, a free text search over
+// title, goal and description.
+// GET /catalog/templates
+func (c *Controller) ListTemplates(ctx echo.Context) error {
+	query := c.DB.Model(&models.PlanTemplate{}).Preload("Category").Where("published = ?", true)
+
+	if v := ctx.QueryParam("category_id"); v != "" {
+		categoryID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid category_id"})
+		}
+		query = query.Where("category_id = ?", categoryID)
+	}
+	if level := ctx.QueryParam("level"); level != "" {
+		if !validLevel(level) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Level must be beginner, intermediate or advanced"})
+		}
+		query = query.Where("level = ?", level)
+	}
+	if v := ctx.QueryParam("min_weeks"); v != "" {
+		weeks, err := strconv.Atoi(v)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid min_weeks"})
+		}
+		query = query.Where("total_weeks >= ?", weeks)
+	}
+	if v := ctx.QueryParam("max_weeks"); v != "" {
+		weeks, err := strconv.Atoi(v)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid max_weeks"})
+		}
+		query = query.Where("total_weeks <= ?", weeks)
+	}
+	if q := strings.TrimSpace(ctx.QueryParam("q")); q != "" {
+		like := "%" + q + "%"
+		query = query.Where("title ILIKE ? OR goal ILIKE ? OR description ILIKE ?", like, like, like)
+	}
+
+	var templates []models.PlanTemplate
+	if err := query.Order("start_count DESC, published_at DESC").Find(&templates).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch templates"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Templates fetched successfully",
+		Data:    templates,
+	})
+}
+
+// GET /catalog/templates/:id
+func (c *Controller) GetTemplate(ctx echo.Context) error {
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	var template models.PlanTemplate
+	if err := c.DB.Preload("Category").Where("id = ? AND published = ?", id, true).First(&template).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Template fetched successfully",
+		Data:    template,
+	})
+}
+
+// StartTemplate instantiates a published template as a new private plan for
+// the user, using their own daily commitment.
+// POST /catalog/templates/:id/start
+func (c *Controller) StartTemplate(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req StartTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var template models.PlanTemplate
+	if err := c.DB.Where("id = ? AND published = ?", id, true).First(&template).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+	}
+
+	if req.DailyCommitment == 0 {
+		var user models.User
+		if err := c.DB.First(&user, userID).Error; err == nil {
+			req.DailyCommitment = user.DailyCommitment
+		}
+	}
+	if req.DailyCommitment == 0 {
+		req.DailyCommitment = template.DailyCommitment
+	}
+
+	var structure models.CompleteLearningPlan
+	if err := json.Unmarshal(template.Structure, &structure); err != nil {
+		log.Printf("Failed to unmarshal template %d structure: %v", template.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to parse template structure"})
+	}
+	structure.ID = 0
+	structure.DailyCommitment = req.DailyCommitment
+	structureJSON, _ := json.Marshal(structure)
+
+	templateID := template.ID
+	plan := models.LearningPlanStructure{
+		UserID:     userID,
+		Goal:       template.Goal,
+		TotalWeeks: template.TotalWeeks,
+		Structure:  datatypes.JSON(structureJSON),
+		TemplateID: &templateID,
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&plan).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.PlanTemplate{}).Where("id = ?", template.ID).
+			UpdateColumn("start_count", gorm.Expr("start_count + 1")).Error
+	})
+	if err != nil {
+		log.Printf("Failed to start template %d: %v", template.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start plan"})
+	}
+
+	structure.ID = plan.ID
+	return ctx.JSON(http.StatusCreated, models.SuccessResponse{
+		Status:  http.StatusCreated,
+		Message: "Plan started successfully",
+		Data: map[string]interface{}{
+			"id":   plan.ID,
+			"plan": structure,
+		},
+	})
+}
+
+// RatePlan records the user's 1-5 rating of one of their plans.
+// POST /learnings/structure/:id/rating
+func (c *Controller) RatePlan(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req RatePlanRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Rating must be between 1 and 5"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if _, err := c.ownedPlan(userID, id); err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
+	}
+
+	var rating models.PlanRating
+	err = c.DB.Where("plan_id = ? AND user_id = ?", id, userID).First(&rating).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch rating"})
+	}
+	rating.PlanID = id
+	rating.UserID = userID
+	rating.Rating = req.Rating
+	rating.Comment = req.Comment
+	if err := c.DB.Save(&rating).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save rating"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Rating saved successfully",
+		Data:    rating,
+	})
+}
+
+// POST /admin/categories
+func (c *Controller) CreateCategory(ctx echo.Context) error {
+	var req CategoryRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Name is required"})
+	}
+
+	category := models.Category{
+		Name:        strings.TrimSpace(req.Name),
+		Slug:        slugify(req.Name),
+		Description: req.Description,
+	}
+	if err := c.DB.Create(&category).Error; err != nil {
+		return ctx.JSON(http.StatusConflict, map[string]string{"error": "A category with this name already exists"})
+	}
+
+	return ctx.JSON(http.StatusCreated, models.SuccessResponse{
+		Status:  http.StatusCreated,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// PUT /admin/categories/:id
+func (c *Controller) UpdateCategory(ctx echo.Context) error {
+	var req CategoryRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var category models.Category
+	if err := c.DB.First(&category, id).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Category not found"})
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		category.Name = name
+		category.Slug = slugify(name)
+	}
+	category.Description = req.Description
+	if err := c.DB.Save(&category).Error; err != nil {
+		return ctx.JSON(http.StatusConflict, map[string]string{"error": "A category with this name already exists"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// DeleteCategory removes a category; its templates stay in the catalog uncategorised.
+// DELETE /admin/categories/:id
+func (c *Controller) DeleteCategory(ctx echo.Context) error {
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	err := c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PlanTemplate{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Category not found"})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete category"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Category deleted successfully",
+	})
+}
+
+// ListTemplateCandidates ranks generated plan structures by learner ratings
+// so admins can seed templates from the best ones. Ratings of clones count
+// towards the plan they were cloned from. Query params: min_rating (default
+// 4) and min_ratings (default 1).
+// GET /admin/templates/candidates
+func (c *Controller) ListTemplateCandidates(ctx echo.Context) error {
+	minRating, err := strconv.ParseFloat(ctx.QueryParam("min_rating"), 64)
+	if err != nil {
+		minRating = 4
+	}
+	minRatings, err := strconv.Atoi(ctx.QueryParam("min_ratings"))
+	if err != nil || minRatings < 1 {
+		minRatings = 1
+	}
+
+	var candidates []models.TemplateCandidate
+	err = c.DB.Raw(`
+		SELECT src.id AS plan_id, src.goal, src.total_weeks,
+			AVG(r.rating) AS average_rating, COUNT(r.id) AS rating_count
+		FROM plan_ratings r
+		JOIN learning_plan_structures p ON p.id = r.plan_id
+		JOIN learning_plan_structures src ON src.id = COALESCE(p.source_plan_id, p.id)
+		WHERE src.template_id IS NULL
+			AND NOT EXISTS (SELECT 1 FROM plan_templates t WHERE t.source_plan_id = src.id)
+		GROUP BY src.id, src.goal, src.total_weeks
+		HAVING AVG(r.rating) >= ? AND COUNT(r.id) >= ?
+		ORDER BY average_rating DESC, rating_count DESC
+		LIMIT 50`, minRating, minRatings).Scan(&candidates).Error
+	if err != nil {
+		log.Printf("Failed to fetch template candidates: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch template candidates"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Template candidates fetched successfully",
+		Data:    candidates,
+	})
+}
+
+// GET /admin/templates
+func (c *Controller) AdminListTemplates(ctx echo.Context) error {
+	var templates []models.PlanTemplate
+	if err := c.DB.Preload("Category").Order("created_at DESC").Find(&templates).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch templates"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Templates fetched successfully",
+		Data:    templates,
+	})
+}
+
+// CreateTemplate creates an unpublished template, either from scratch or
+// seeded from an existing plan structure.
+// POST /admin/templates
+func (c *Controller) CreateTemplate(ctx echo.Context) error {
+	var req PlanTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	var template models.PlanTemplate
+	if req.PlanID != 0 {
+		var plan models.LearningPlanStructure
+		if err := c.DB.First(&plan, req.PlanID).Error; err != nil {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
+		}
+		sourceID := plan.ID
+		template = models.PlanTemplate{
+			Title:        plan.Goal,
+			Goal:         plan.Goal,
+			TotalWeeks:   plan.TotalWeeks,
+			Structure:    plan.Structure,
+			SourcePlanID: &sourceID,
+		}
+		var structure models.CompleteLearningPlan
+		if err := json.Unmarshal(plan.Structure, &structure); err == nil {
+			template.DailyCommitment = structure.DailyCommitment
+		}
+	}
+
+	if errMsg := applyTemplateRequest(&template, req); errMsg != "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+	}
+	if template.Level == "" {
+		template.Level = models.LevelBeginner
+	}
+	if errMsg := c.validateTemplate(template); errMsg != "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+	}
+
+	if err := c.DB.Create(&template).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create template"})
+	}
+
+	return ctx.JSON(http.StatusCreated, models.SuccessResponse{
+		Status:  http.StatusCreated,
+		Message: "Template created successfully",
+		Data:    template,
+	})
+}
+
+// PUT /admin/templates/:id
+func (c *Controller) UpdateTemplate(ctx echo.Context) error {
+	var req PlanTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var template models.PlanTemplate
+	if err := c.DB.First(&template, id).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+	}
+
+	if errMsg := applyTemplateRequest(&template, req); errMsg != "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+	}
+	if errMsg := c.validateTemplate(template); errMsg != "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": errMsg})
+	}
+
+	if err := c.DB.Omit("Category").Save(&template).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update template"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Template updated successfully",
+		Data:    template,
+	})
+}
+
+// PUT /admin/templates/:id/publish
+func (c *Controller) PublishTemplate(ctx echo.Context) error {
+	var req PublishTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var template models.PlanTemplate
+	if err := c.DB.First(&template, id).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+	}
+
+	template.Published = req.Published
+	if req.Published {
+		now := time.Now()
+		template.PublishedAt = &now
+	} else {
+		template.PublishedAt = nil
+	}
+	if err := c.DB.Save(&template).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update template"})
+	}
+
+	message := "Template unpublished successfully"
+	if req.Published {
+		message = "Template published successfully"
+	}
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    template,
+	})
+}
+
+// DeleteTemplate removes a template. Plans already started from it are kept.
+// DELETE /admin/templates/:id
+func (c *Controller) DeleteTemplate(ctx echo.Context) error {
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	result := c.DB.Delete(&models.PlanTemplate{}, id)
+	if result.Error != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete template"})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Template not found"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Template deleted successfully",
+	})
+}
+
+// applyTemplateRequest copies the fields set in req onto template.
+func applyTemplateRequest(template *models.PlanTemplate, req PlanTemplateRequest) string {
+	if req.CategoryID != nil {
+		template.CategoryID = req.CategoryID
+		if *req.CategoryID == 0 {
+			template.CategoryID = nil
+		}
+	}
+	if req.Title != "" {
+		template.Title = strings.TrimSpace(req.Title)
+	}
+	if req.Goal != "" {
+		template.Goal = strings.TrimSpace(req.Goal)
+	}
+	if req.Description != "" {
+		template.Description = req.Description
+	}
+	if req.Level != "" {
+		template.Level = req.Level
+	}
+	if req.TotalWeeks != 0 {
+		template.TotalWeeks = req.TotalWeeks
+	}
+	if req.DailyCommitment != 0 {
+		template.DailyCommitment = req.DailyCommitment
+	}
+	if len(req.Structure) > 0 {
+		var structure models.CompleteLearningPlan
+		if err := json.Unmarshal(req.Structure, &structure); err != nil {
+			return "Structure must be a learning plan structure object"
+		}
+		template.Structure = datatypes.JSON(req.Structure)
+	}
+	return ""
+}
+
+func (c *Controller) validateTemplate(template models.PlanTemplate) string {
+	switch {
+	case template.Title == "":
+		return "Title is required"
+	case template.Goal == "":
+		return "Goal is required"
+	case template.TotalWeeks <= 0:
+		return "Total weeks is required"
+	case len(template.Structure) == 0:
+		return "Structure is required"
+	case !validLevel(template.Level):
+		return "Level must be beginner, intermediate or advanced"
+	}
+	if template.CategoryID != nil {
+		var count int64
+		c.DB.Model(&models.Category{}).Where("id = ?", *template.CategoryID).Count(&count)
+		if count == 0 {
+			return "Category not found"
+		}
+	}
+	return ""
+}
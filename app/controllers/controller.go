@@ -2,12 +2,25 @@ package controllers
 
 import (
 	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/jobs"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/otp"
+	"github.com/surahj/ai-mentor-backend/app/ratelimit"
+	"github.com/surahj/ai-mentor-backend/app/repository"
 	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/usage"
 	"gorm.io/gorm"
 )
 
 type Controller struct {
 	DB          *gorm.DB
 	EmailClient services.EmailServiceProvider
+	LLM         services.LLMProvider
 	Config      *configs.Config
+	Jobs        *jobs.Queue
+	OTP         *otp.Service
+	Quota       *ratelimit.Quota
+	Usage       *usage.Ledger
+	Users       repository.UserRepository
+	Learning    *learning.Service
 }
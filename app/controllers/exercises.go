@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errAlreadySubmitted rolls back attempts of which one was saved concurrently
+var errAlreadySubmitted = errors.New("exercise already submitted")
+
+type ExerciseAnswer struct {
+	ExerciseIndex int    `json:"exercise_index"`
+	Answer        string `json:"answer"`
+}
+
+type SubmitExercisesRequest struct {
+	Answers []ExerciseAnswer `json:"answers"`
+	// Language of the translation the exercises were answered in, when it is
+	// not the learner's preferred language
+	Language string `json:"language,omitempty" example:"Spanish"`
+}
+
+type ExerciseFeedback struct {
+	ExerciseIndex int     `json:"exercise_index"`
+	Answer        string  `json:"answer"`
+	Correct       bool    `json:"correct"`
+	Score         float64 `json:"score"`
+	Feedback      string  `json:"feedback"`
+	CorrectAnswer string  `json:"correct_answer"`
+	Explanation   string  `json:"explanation"`
+}
+
+type SubmitExercisesResponse struct {
+	Results      []ExerciseFeedback `json:"results"`
+	TotalScore   float64            `json:"total_score"`
+	MaxScore     float64            `json:"max_score"`
+	CorrectCount int                `json:"correct_count"`
+}
+
+// SubmitExercises grades a learner's answers to the exercises of a day,
+// persists each attempt and returns per-question feedback.
+func (c *Controller) SubmitExercises(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, _ := strconv.ParseInt(ctx.Param("plan_id"), 10, 64)
+	week, _ := strconv.Atoi(ctx.Param("week_number"))
+	day, _ := strconv.Atoi(ctx.Param("day_number"))
+
+	if planID == 0 || week == 0 || day == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID, week number and day number are required",
+		})
+	}
+
+	var req SubmitExercisesRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if len(req.Answers) == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "At least one answer is required",
+		})
+	}
+
+	daily, err := c.Learning.Lesson(userID, planID, week, day)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Daily content not found"})
+	}
+
+	var exercises []models.Exercise
+	if len(daily.Exercises) == 0 || json.Unmarshal(daily.Exercises, &exercises) != nil || len(exercises) == 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "No exercises found for this day. Please generate the exercises first."})
+	}
+
+	// answers to a translated day are graded against the translation the
+	// learner read, in their language unless the request names another
+	language := c.userLanguage(userID)
+	if req.Language != "" {
+		var ok bool
+		if language, ok = utils.KnownLanguage(req.Language); !ok {
+			return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+				ErrorCode:    http.StatusBadRequest,
+				ErrorMessage: "Unsupported language",
+			})
+		}
+	}
+	translated := c.translatedExercises(*daily, language)
+	if len(translated) != len(exercises) {
+		translated = nil
+	}
+
+	// answers are revealed once submitted, so an exercise is graded only once
+	submitted, err := c.Learning.SubmittedExercises(*daily)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch previous attempts"})
+	}
+	answered := map[int]bool{}
+	for _, i := range submitted {
+		answered[i] = true
+	}
+
+	mode := c.Config.LLM.GradingMode
+	response := SubmitExercisesResponse{}
+	attempts := make([]models.ExerciseAttempt, 0, len(req.Answers))
+
+	for _, a := range req.Answers {
+		if a.ExerciseIndex < 0 || a.ExerciseIndex >= len(exercises) {
+			return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+				ErrorCode:    http.StatusBadRequest,
+				ErrorMessage: "Invalid exercise index " + strconv.Itoa(a.ExerciseIndex),
+			})
+		}
+
+		if answered[a.ExerciseIndex] {
+			return ctx.JSON(http.StatusConflict, models.ErrorResponse{
+				ErrorCode:    http.StatusConflict,
+				ErrorMessage: "Exercise " + strconv.Itoa(a.ExerciseIndex) + " has already been submitted",
+			})
+		}
+		answered[a.ExerciseIndex] = true
+
+		exercise := exercises[a.ExerciseIndex]
+		answer := a.Answer
+		if translated != nil {
+			// options picked from the original are mapped to the translation
+			answer = utils.TranslateOption(exercise.Options, translated[a.ExerciseIndex].Options, answer)
+			exercise = translated[a.ExerciseIndex]
+		}
+		result, err := utils.GradeAnswer(c.llmFor(userID, planID), exercise, answer, mode)
+		if err != nil {
+			log.Printf("Failed to grade exercise %d: %v", a.ExerciseIndex, err)
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to grade answers"})
+		}
+
+		attempts = append(attempts, models.ExerciseAttempt{
+			PlanID:               planID,
+			UserID:               userID,
+			WeekNumber:           week,
+			DayNumber:            day,
+			DailyContentID:       &daily.ID,
+			ExercisesGeneratedAt: daily.ExercisesGeneratedAt,
+			ExerciseIndex:        a.ExerciseIndex,
+			Answer:               a.Answer,
+			IsCorrect:            result.Correct,
+			Score:                result.Score,
+			Feedback:             result.Feedback,
+			GradingMode:          result.Mode,
+		})
+
+		response.Results = append(response.Results, ExerciseFeedback{
+			ExerciseIndex: a.ExerciseIndex,
+			Answer:        a.Answer,
+			Correct:       result.Correct,
+			Score:         result.Score,
+			Feedback:      result.Feedback,
+			CorrectAnswer: exercise.Answer,
+			Explanation:   exercise.Explanation,
+		})
+		response.TotalScore += result.Score
+		response.MaxScore++
+		if result.Correct {
+			response.CorrectCount++
+		}
+	}
+
+	// the unique index on the attempted exercise rejects a submission that
+	// raced this one past the check above
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&attempts)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected < int64(len(attempts)) {
+			return errAlreadySubmitted
+		}
+		return nil
+	})
+	if errors.Is(err, errAlreadySubmitted) {
+		return ctx.JSON(http.StatusConflict, models.ErrorResponse{
+			ErrorCode:    http.StatusConflict,
+			ErrorMessage: "The exercises have already been submitted",
+		})
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save exercise attempts"})
+	}
+
+	c.syncFlashcards(*daily)
+	c.evaluateAdaptation(userID, planID)
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Answers graded successfully",
+		Data:    response,
+	})
+}
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultDueFlashcards = 20
+	maxDueFlashcards     = 100
+)
+
+type ReviewFlashcardRequest struct {
+	// Quality is the SM-2 grade: 0 blackout, 1-2 wrong, 3 hard, 4 good, 5 easy
+	Quality *int `json:"quality" example:"4"`
+}
+
+// DueFlashcardsResponse lists the cards due for review today
+type DueFlashcardsResponse struct {
+	DueCount  int                `json:"due_count" example:"12"`
+	Cards     []models.Flashcard `json:"cards"`
+	NextDueAt *time.Time         `json:"next_due_at,omitempty"` // earliest review after today when nothing is due
+}
+
+// GetDueFlashcards lists the user's flashcards due by the end of today (UTC),
+// most overdue first, optionally restricted to one plan. Days generated
+// before flashcards existed get their cards on first use.
+// GET /learnings/flashcards/due
+func (c *Controller) GetDueFlashcards(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, _ := strconv.ParseInt(ctx.QueryParam("plan_id"), 10, 64)
+	limit, _ := strconv.Atoi(ctx.QueryParam("limit"))
+	if limit <= 0 {
+		limit = defaultDueFlashcards
+	}
+	if limit > maxDueFlashcards {
+		limit = maxDueFlashcards
+	}
+
+	c.backfillFlashcards(userID, planID)
+
+	query := func() *gorm.DB {
+		q := c.DB.Model(&models.Flashcard{}).Where("user_id = ?", userID)
+		if planID != 0 {
+			q = q.Where("plan_id = ?", planID)
+		}
+		return q
+	}
+	endOfDay := endOfDayUTC(time.Now())
+
+	response := DueFlashcardsResponse{Cards: []models.Flashcard{}}
+	var dueCount int64
+	if err := query().Where("due_at < ?", endOfDay).Count(&dueCount).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch flashcards"})
+	}
+	response.DueCount = int(dueCount)
+
+	if err := query().Where("due_at < ?", endOfDay).Order("due_at ASC, id ASC").Limit(limit).Find(&response.Cards).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch flashcards"})
+	}
+
+	if response.DueCount == 0 {
+		var next models.Flashcard
+		if err := query().Order("due_at ASC").First(&next).Error; err == nil {
+			response.NextDueAt = &next.DueAt
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Due flashcards fetched successfully",
+		Data:    response,
+	})
+}
+
+// ReviewFlashcard records a review of a flashcard and schedules the next one.
+// POST /learnings/flashcards/:id/review
+func (c *Controller) ReviewFlashcard(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	var req ReviewFlashcardRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if req.Quality == nil || *req.Quality < 0 || *req.Quality > utils.MaxReviewQuality {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Quality must be between 0 and 5",
+		})
+	}
+
+	var card models.Flashcard
+	if err := c.DB.Where("id = ? AND user_id = ?", id, userID).First(&card).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Flashcard not found"})
+	}
+
+	utils.ScheduleReview(&card, *req.Quality, time.Now())
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&card).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.FlashcardReview{
+			FlashcardID:  card.ID,
+			PlanID:       card.PlanID,
+			UserID:       userID,
+			Quality:      *req.Quality,
+			IntervalDays: card.IntervalDays,
+			EaseFactor:   card.EaseFactor,
+		}).Error
+	})
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save review"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Review recorded",
+		Data:    card,
+	})
+}
+
+// syncFlashcards creates the flashcards of a day's key points and attempted
+// exercises, refreshes the text of existing ones without touching their
+// schedule and drops cards whose key point or exercise no longer exists or is
+// no longer attempted. Failures are logged; flashcards never block the
+// learner's request.
+func (c *Controller) syncFlashcards(daily models.DailyContent) {
+	attempted, err := c.Learning.SubmittedExercises(daily)
+	if err != nil {
+		log.Printf("Failed to sync flashcards for daily content %d: %v", daily.ID, err)
+		return
+	}
+	cards := utils.FlashcardsForDay(daily, attempted, time.Now())
+
+	indexes := map[string][]int{
+		models.FlashcardSourceKeyPoint: {},
+		models.FlashcardSourceExercise: {},
+	}
+	for _, card := range cards {
+		indexes[card.Source] = append(indexes[card.Source], card.SourceIndex)
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		for source, kept := range indexes {
+			stale := tx.Where("daily_content_id = ? AND source = ?", daily.ID, source)
+			if len(kept) > 0 {
+				stale = stale.Where("source_index NOT IN ?", kept)
+			}
+			if err := stale.Delete(&models.Flashcard{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(cards) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "daily_content_id"}, {Name: "source"}, {Name: "source_index"}},
+			DoUpdates: clause.AssignmentColumns([]string{"front", "back", "updated_at"}),
+		}).Create(&cards).Error
+	})
+	if err != nil {
+		log.Printf("Failed to sync flashcards for daily content %d: %v", daily.ID, err)
+		return
+	}
+	if err := c.DB.Model(&models.DailyContent{}).Where("id = ?", daily.ID).Update("flashcards_synced_at", time.Now()).Error; err != nil {
+		log.Printf("Failed to mark the flashcards of daily content %d synced: %v", daily.ID, err)
+	}
+}
+
+// backfillFlashcards syncs the days of a user, or of one of their plans, that
+// were never synced.
+func (c *Controller) backfillFlashcards(userID, planID int64) {
+	q := c.DB.Where("user_id = ? AND flashcards_synced_at IS NULL", userID)
+	if planID != 0 {
+		q = q.Where("plan_id = ?", planID)
+	}
+
+	var days []models.DailyContent
+	if err := q.Find(&days).Error; err != nil {
+		log.Printf("Failed to find days without flashcards: %v", err)
+		return
+	}
+	for _, daily := range days {
+		c.syncFlashcards(daily)
+	}
+}
+
+// flashcardProgress adds the learner's flashcard reviews in a plan to its
+// progress snapshot.
+func (c *Controller) flashcardProgress(snapshot *models.ProgressSnapshot, userID, planID int64) error {
+	var cards struct {
+		Total  int
+		Due    int
+		Lapses int
+	}
+	if err := c.DB.Model(&models.Flashcard{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE due_at < ?) AS due, COALESCE(SUM(lapses), 0) AS lapses", endOfDayUTC(time.Now())).
+		Where("plan_id = ? AND user_id = ?", planID, userID).
+		Scan(&cards).Error; err != nil {
+		return err
+	}
+	snapshot.Flashcards = cards.Total
+	snapshot.FlashcardsDue = cards.Due
+	snapshot.FlashcardLapses = cards.Lapses
+
+	var reviews struct {
+		Total    int
+		Recalled int
+	}
+	if err := c.DB.Model(&models.FlashcardReview{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE quality >= ?) AS recalled", utils.MinRecallQuality).
+		Where("plan_id = ? AND user_id = ?", planID, userID).
+		Scan(&reviews).Error; err != nil {
+		return err
+	}
+	snapshot.FlashcardReviews = reviews.Total
+	if reviews.Total > 0 {
+		recall := float64(reviews.Recalled) / float64(reviews.Total)
+		snapshot.FlashcardRecall = &recall
+	}
+	return nil
+}
+
+func endOfDayUTC(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
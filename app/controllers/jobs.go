@@ -0,0 +1,313 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/jobs"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/datatypes"
+	"gorm.io/gorm/clause"
+)
+
+// RegisterJobHandlers wires the generation job kinds to their handlers.
+func (c *Controller) RegisterJobHandlers() {
+	c.Jobs.Register(models.JobKindPlanStructure, c.runPlanStructureJob)
+	c.Jobs.Register(models.JobKindWeeklyContent, c.runWeeklyContentJob)
+	c.Jobs.Register(models.JobKindDailyContent, c.runDailyContentJob)
+}
+
+// PlanJobResult is the result stored by a plan structure job.
+type PlanJobResult struct {
+	ID   int64                        `json:"id"`
+	Plan *models.CompleteLearningPlan `json:"plan"`
+}
+
+// WeekJobResult is the result stored by a weekly content job.
+type WeekJobResult struct {
+	ID      int64                 `json:"id"`
+	Content *models.WeeklyContent `json:"content"`
+}
+
+// DayJobResult is the result stored by a daily content job. Exercise answers
+// are hidden as they are in the daily content response.
+type DayJobResult struct {
+	ID      int64               `json:"id"`
+	Content models.DailyContent `json:"content"`
+}
+
+// GetJob returns the status of a generation job and, once it has succeeded, its result.
+func (c *Controller) GetJob(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	job, err := c.Jobs.Get(id)
+	if err != nil || job.UserID != userID {
+		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
+			ErrorCode:    http.StatusNotFound,
+			ErrorMessage: "Job not found",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Job fetched successfully",
+		Data:    job,
+	})
+}
+
+func respondJobAccepted(ctx echo.Context, job *models.GenerationJob, created bool) error {
+	message := "Generation started"
+	if !created {
+		message = "Generation already in progress"
+	}
+	return ctx.JSON(http.StatusAccepted, models.SuccessResponse{
+		Status:  http.StatusAccepted,
+		Message: message,
+		Data:    job,
+	})
+}
+
+func (c *Controller) runPlanStructureJob(_ context.Context, job *models.GenerationJob) (interface{}, error) {
+	var req learning.PlanJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	// an earlier attempt may have saved the plan before failing
+	var saved models.LearningPlanStructure
+	if err := c.DB.Where("generation_job_id = ?", job.ID).Limit(1).Find(&saved).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch structure: %w", err)
+	}
+	if saved.ID != 0 {
+		return planJobResult(saved)
+	}
+
+	// Generate the learning plan structure using the configured LLM
+	language := c.userLanguage(job.UserID)
+	profile := c.learnerProfile(job.UserID, true, 0)
+	profile.Placement = c.placementResult(job.UserID, req.PlacementDiagnosticID)
+	plan, trace, err := utils.GenerateLearningPlanStructure(c.llmFor(job.UserID, 0), req.Goal, req.TotalWeeks, req.DailyCommitment, language, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate structure: %w", err)
+	}
+
+	// Convert the plan to JSON for storage
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to serialize plan: %w", err))
+	}
+	basisJSON, _ := json.Marshal(generationBasis(nil, nil, profile, trace))
+
+	// Save to database
+	learningPlan := models.LearningPlanStructure{
+		UserID:           job.UserID,
+		Goal:             req.Goal,
+		TotalWeeks:       req.TotalWeeks,
+		Structure:        datatypes.JSON(planJSON),
+		Prompts:          trace.Stamp.JSON(),
+		Language:         language,
+		GeneratedBasedOn: datatypes.JSON(basisJSON),
+		GenerationJobID:  &job.ID,
+	}
+	if profile.Placement != nil {
+		learningPlan.PlacementDiagnosticID = &profile.Placement.DiagnosticID
+	}
+
+	// the unique index on the job rejects a plan saved concurrently by an
+	// attempt whose lease was taken over; the one saved first is kept
+	result := c.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&learningPlan)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to save structure: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if err := c.DB.Where("generation_job_id = ?", job.ID).First(&saved).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch structure: %w", err)
+		}
+		return planJobResult(saved)
+	}
+
+	return PlanJobResult{ID: learningPlan.ID, Plan: plan}, nil
+}
+
+// planJobResult is the result of a plan job that saved the plan already.
+func planJobResult(saved models.LearningPlanStructure) (interface{}, error) {
+	var plan models.CompleteLearningPlan
+	if err := json.Unmarshal(saved.Structure, &plan); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to parse stored structure: %w", err))
+	}
+	return PlanJobResult{ID: saved.ID, Plan: &plan}, nil
+}
+
+func (c *Controller) runWeeklyContentJob(_ context.Context, job *models.GenerationJob) (interface{}, error) {
+	var req learning.WeekJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+	userID := job.UserID
+
+	plan, err := c.Learning.Plan(userID, req.PlanID)
+	if err != nil {
+		return nil, jobs.Permanent(errors.New("plan structure not found"))
+	}
+
+	flag, err := c.Learning.AdaptationFlag(userID, req.PlanID, req.WeekNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// an earlier job may have generated the week in the meantime
+	var generatedContent models.GeneratedWeeklyContent
+	current, err := c.Learning.CurrentWeek(userID, req.PlanID, req.WeekNumber)
+	hasCurrent := err == nil
+	if hasCurrent {
+		generatedContent = *current
+		if flag == nil {
+			var content models.WeeklyContent
+			if err := json.Unmarshal(current.ContentData, &content); err != nil {
+				return nil, jobs.Permanent(fmt.Errorf("failed to parse stored content: %w", err))
+			}
+			return WeekJobResult{ID: current.ID, Content: &content}, nil
+		}
+	}
+
+	progress, err := c.progressSnapshot(userID, req.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch progress: %w", err)
+	}
+
+	// Generate weekly content using the configured LLM
+	language := c.planLanguage(plan)
+	profile := c.learnerProfile(userID, false, req.PlanID)
+	profile.Placement = c.placementResult(userID, plan.PlacementDiagnosticID)
+	content, trace, err := utils.GenerateWeeklyContent(c.llmFor(userID, req.PlanID), plan.Goal, req.WeekNumber, progress, utils.AdaptationGuidance(flag), language, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	// Convert content to JSON for storage
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to serialize content: %w", err))
+	}
+
+	// Record what the content was generated from
+	basisJSON, _ := json.Marshal(generationBasis(&progress, flag, profile, trace))
+
+	previous := generatedContent
+	generatedContent = models.GeneratedWeeklyContent{
+		PlanID:           req.PlanID,
+		WeekNumber:       req.WeekNumber,
+		Version:          1,
+		ContentData:      datatypes.JSON(contentJSON),
+		GeneratedBasedOn: datatypes.JSON(basisJSON),
+		Prompts:          trace.Stamp.JSON(),
+		Language:         language,
+		UserID:           userID,
+	}
+
+	if hasCurrent {
+		generatedContent.Version = previous.Version + 1
+		if err := c.replaceWeekContent(previous, &generatedContent, flag); err != nil {
+			return nil, fmt.Errorf("failed to save regenerated content: %w", err)
+		}
+	} else {
+		if err := c.DB.Create(&generatedContent).Error; err != nil {
+			return nil, fmt.Errorf("failed to save content: %w", err)
+		}
+		if flag != nil {
+			c.resolveAdaptationFlag(flag)
+		}
+	}
+
+	return WeekJobResult{ID: generatedContent.ID, Content: content}, nil
+}
+
+func (c *Controller) runDailyContentJob(_ context.Context, job *models.GenerationJob) (interface{}, error) {
+	var req learning.DayJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	daily, err := c.generateDailyContent(job.UserID, req.PlanID, req.WeekNumber, req.DayNumber, nil)
+	if err != nil {
+		return nil, err
+	}
+	return DayJobResult{ID: daily.ID, Content: c.Learning.HideAnswers(*daily)}, nil
+}
+
+// generateDailyContent creates the lesson and resources for a day unless they
+// already exist. onExplanation, when set, receives the lesson explanation as
+// it is generated.
+func (c *Controller) generateDailyContent(userID, planID int64, week, day int, onExplanation services.StreamHandler) (*models.DailyContent, error) {
+	weekContent, err := c.Learning.CurrentWeek(userID, planID, week)
+	if err != nil {
+		return nil, jobs.Permanent(errors.New("week content not found"))
+	}
+
+	existing, err := c.Learning.Lesson(userID, planID, week, day)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, learning.ErrNotFound) {
+		return nil, err
+	}
+
+	userProgress, err := c.progressSnapshot(userID, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch progress: %w", err)
+	}
+
+	plan, err := c.Learning.Plan(userID, planID)
+	if err != nil {
+		return nil, jobs.Permanent(errors.New("plan structure not found"))
+	}
+
+	dailyStructure := string(weekContent.ContentData)
+	language := c.planLanguage(plan)
+	profile := c.learnerProfile(userID, false, planID)
+	profile.Placement = c.placementResult(userID, plan.PlacementDiagnosticID)
+	lesson, resources, trace, err := utils.StreamDailyContent(c.llmFor(userID, planID), plan.Goal, dailyStructure, week, day, userProgress, language, profile, onExplanation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate daily content: %w", err)
+	}
+	basisJSON, _ := json.Marshal(generationBasis(&userProgress, nil, profile, trace))
+
+	daily := models.DailyContent{
+		PlanID:           planID,
+		UserID:           userID,
+		WeekNumber:       week,
+		DayNumber:        day,
+		Content:          lesson,
+		Resources:        resources,
+		Prompts:          trace.Stamp.JSON(),
+		Language:         language,
+		GeneratedBasedOn: datatypes.JSON(basisJSON),
+	}
+	// the unique index on the day rejects a lesson generated concurrently by
+	// a job or another stream; the one saved first is kept
+	result := c.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&daily)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to save daily content: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return c.Learning.Lesson(userID, planID, week, day)
+	}
+	c.syncFlashcards(daily)
+
+	log.Printf("Generated daily content for plan %d week %d day %d", planID, week, day)
+	return &daily, nil
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type TranslateRequest struct {
+	// Language name or ISO 639-1 code, e.g. "Spanish" or "es"
+	Language string `json:"language" example:"Spanish"`
+}
+
+// userLanguage is the language a user's new content is generated in.
+func (c *Controller) userLanguage(userID int64) string {
+	user, err := c.Users.FindByID(userID)
+	if err != nil || user.PreferredLanguage == nil {
+		return utils.DefaultLanguage
+	}
+	return utils.LanguageName(*user.PreferredLanguage)
+}
+
+// planLanguage is the language of a plan's content. Plans created before
+// languages were recorded follow the owner's current preference.
+func (c *Controller) planLanguage(plan *models.LearningPlanStructure) string {
+	if plan.Language != "" {
+		return plan.Language
+	}
+	return c.userLanguage(plan.UserID)
+}
+
+// sendUserEmail sends an OTP email in the user's preferred language.
+func (c *Controller) sendUserEmail(user models.User, kind, otp string) error {
+	data := services.EmailData{OTP: otp, ValidMinutes: int(c.OTP.TTL().Minutes())}
+	if user.FirstName != nil {
+		data.FirstName = *user.FirstName
+	}
+	language := utils.DefaultLanguage
+	if user.PreferredLanguage != nil {
+		language = utils.LanguageName(*user.PreferredLanguage)
+	}
+
+	subject, body, err := services.RenderEmail(kind, language, data)
+	if err != nil {
+		return err
+	}
+	return c.EmailClient.SendEmail(user.Email, subject, body)
+}
+
+// TranslateDailyContent returns a day's lesson, exercises and resources in
+// another language. Translations are stored and reused until the day's
+// content changes; the curriculum itself is not regenerated.
+// POST /learnings/daily-content/:day_number/:week_number/:plan_id/translate
+func (c *Controller) TranslateDailyContent(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, _ := strconv.ParseInt(ctx.Param("plan_id"), 10, 64)
+	week, _ := strconv.Atoi(ctx.Param("week_number"))
+	day, _ := strconv.Atoi(ctx.Param("day_number"))
+	if planID == 0 || week == 0 || day == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID, week number and day number are required",
+		})
+	}
+
+	var req TranslateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if strings.TrimSpace(req.Language) == "" {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Language is required",
+		})
+	}
+	language, ok := utils.KnownLanguage(req.Language)
+	if !ok {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Unsupported language",
+		})
+	}
+
+	daily, err := c.Learning.Lesson(userID, planID, week, day)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Daily content not found. Please generate the daily lesson first."})
+	}
+
+	if strings.EqualFold(language, daily.Language) {
+		return ctx.JSON(http.StatusOK, models.SuccessResponse{
+			Message: "Daily content is already in " + language,
+			Data:    c.Learning.HideAnswers(*daily),
+		})
+	}
+
+	var translation models.DailyContentTranslation
+	err = c.DB.Where("daily_content_id = ? AND language = ?", daily.ID, language).First(&translation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch translation"})
+	}
+	if err == nil && !translation.SourceUpdatedAt.Before(daily.UpdatedAt) {
+		return ctx.JSON(http.StatusOK, models.SuccessResponse{
+			Message: "Translation fetched successfully",
+			Data:    c.Learning.HideAnswers(translatedDaily(*daily, translation)),
+		})
+	}
+
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
+	}
+	defer quota.release()
+
+	var lesson models.LessonContent
+	var exercises []models.Exercise
+	var resources []models.Resource
+	_ = json.Unmarshal(daily.Content, &lesson)
+	if len(daily.Exercises) > 0 {
+		_ = json.Unmarshal(daily.Exercises, &exercises)
+	}
+	if len(daily.Resources) > 0 {
+		_ = json.Unmarshal(daily.Resources, &resources)
+	}
+
+	translated, stamp, err := utils.TranslateDailyContent(c.llmFor(userID, planID), language, lesson, exercises, resources)
+	if err != nil {
+		log.Printf("Failed to translate daily content %d into %s: %v", daily.ID, language, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to translate daily content"})
+	}
+
+	lessonJSON, _ := json.Marshal(translated.Lesson)
+	translation.DailyContentID = daily.ID
+	translation.Language = language
+	translation.Content = datatypes.JSON(lessonJSON)
+	translation.Exercises = nil
+	if len(exercises) > 0 {
+		exercisesJSON, _ := json.Marshal(translated.Exercises)
+		translation.Exercises = datatypes.JSON(exercisesJSON)
+	}
+	translation.Resources = nil
+	if len(resources) > 0 {
+		resourcesJSON, _ := json.Marshal(translated.Resources)
+		translation.Resources = datatypes.JSON(resourcesJSON)
+	}
+	translation.Prompts = stamp.JSON()
+	translation.SourceUpdatedAt = daily.UpdatedAt
+
+	if err := c.DB.Save(&translation).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save translation"})
+	}
+	quota.keep()
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Message: "Daily content translated successfully",
+		Data:    c.Learning.HideAnswers(translatedDaily(*daily, translation)),
+	})
+}
+
+// translatedExercises returns the exercises of the current translation of a
+// day into language, or nil when the day has no such translation.
+func (c *Controller) translatedExercises(daily models.DailyContent, language string) []models.Exercise {
+	if strings.EqualFold(language, daily.Language) {
+		return nil
+	}
+	var translation models.DailyContentTranslation
+	err := c.DB.Where("daily_content_id = ? AND language = ?", daily.ID, language).First(&translation).Error
+	if err != nil || translation.SourceUpdatedAt.Before(daily.UpdatedAt) || len(translation.Exercises) == 0 {
+		return nil
+	}
+	var exercises []models.Exercise
+	if err := json.Unmarshal(translation.Exercises, &exercises); err != nil {
+		return nil
+	}
+	return exercises
+}
+
+// translatedDaily is the daily content with its text replaced by a translation.
+// Exercise indexes match the original, so answers are still submitted against it.
+func translatedDaily(daily models.DailyContent, translation models.DailyContentTranslation) models.DailyContent {
+	daily.Content = translation.Content
+	daily.Exercises = translation.Exercises
+	daily.Resources = translation.Resources
+	daily.Prompts = translation.Prompts
+	daily.Language = translation.Language
+	return daily
+}
@@ -6,13 +6,14 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/learning"
 	"github.com/surahj/ai-mentor-backend/app/library"
 	"github.com/surahj/ai-mentor-backend/app/models"
 	"github.com/surahj/ai-mentor-backend/app/utils"
-	"gorm.io/datatypes"
-	"gorm.io/gorm"
 )
 
 type GeneratePlanRequest struct {
@@ -24,12 +25,26 @@ type StructureRequest struct {
 	Goal            string `json:"goal"`
 	TotalWeeks      int    `json:"total_weeks"`
 	DailyCommitment int    `json:"daily_commitment"`
+	// CloneFromTemplate copies a shared plan with the same goal and length
+	// instead of generating a new one, when such a plan exists.
+	CloneFromTemplate bool `json:"clone_from_template"`
+	// PlacementDiagnosticID is a completed placement quiz for the goal. Its
+	// level and known concepts shape the plan; the goal defaults to its goal.
+	PlacementDiagnosticID *int64 `json:"placement_diagnostic_id,omitempty" example:"4"`
 }
 
 type ContentRequest struct {
-	PlanID       int64                  `json:"plan_id"`
-	WeekNumber   int                    `json:"week_number"`
-	UserProgress map[string]interface{} `json:"user_progress"`
+	PlanID     int64 `json:"plan_id"`
+	WeekNumber int   `json:"week_number"`
+}
+
+// WeekContentResponse is a week's content. A stale week is being regenerated
+// to adapt to the learner's progress and is served until the new version is
+// ready.
+type WeekContentResponse struct {
+	models.WeeklyContent
+	Stale             bool  `json:"stale"`
+	RegenerationJobID int64 `json:"regeneration_job_id,omitempty"`
 }
 
 type ValidateGoalRequest struct {
@@ -58,6 +73,19 @@ func (c *Controller) GeneratePlanStructure(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
+	if req.PlacementDiagnosticID != nil {
+		diagnostic, err := c.completedPlacement(userID, *req.PlacementDiagnosticID)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid placement diagnostic: " + err.Error()})
+		}
+		if req.Goal == "" {
+			req.Goal = diagnostic.Goal
+		}
+		if !strings.EqualFold(strings.TrimSpace(req.Goal), diagnostic.Goal) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Placement diagnostic was taken for a different goal"})
+		}
+	}
+
 	if req.Goal == "" {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Goal is required"})
 	}
@@ -70,9 +98,8 @@ func (c *Controller) GeneratePlanStructure(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Daily commitment is required"})
 	}
 
-	// check if the goal is already in the database
-	var existingPlan models.LearningPlanStructure
-	if err := c.DB.Where("goal = ?", req.Goal).First(&existingPlan).Error; err == nil {
+	// check if the user already has a plan for this goal
+	if existingPlan, err := c.Learning.PlanForGoal(userID, req.Goal); err == nil {
 		return ctx.JSON(http.StatusOK, models.SuccessResponse{
 			Status:  http.StatusOK,
 			Message: "Goal retrieved successfully",
@@ -80,46 +107,47 @@ func (c *Controller) GeneratePlanStructure(ctx echo.Context) error {
 		})
 	}
 
-	// Generate the learning plan structure using OpenAI
-	plan, err := utils.GenerateLearningPlanStructure(req.Goal, req.TotalWeeks, req.DailyCommitment)
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate structure: " + err.Error()})
+	if req.CloneFromTemplate {
+		if template, err := c.Learning.SharedPlan(userID, req.Goal, req.TotalWeeks); err == nil {
+			return c.respondClonedPlan(ctx, userID, *template)
+		}
 	}
 
-	// Convert the plan to JSON for storage
-	planJSON, err := json.Marshal(plan)
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed tou serialize plan"})
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
 	}
+	defer quota.release()
 
-	log.Printf("Plan: %v", planJSON)
-
-	// Save to database
-	learningPlan := models.LearningPlanStructure{
-		UserID:     userID,
-		Goal:       req.Goal,
-		TotalWeeks: req.TotalWeeks,
-		Structure:  datatypes.JSON(planJSON),
+	job, created, err := c.Learning.GeneratePlan(userID, learning.PlanJob{
+		Goal:                  req.Goal,
+		TotalWeeks:            req.TotalWeeks,
+		DailyCommitment:       req.DailyCommitment,
+		PlacementDiagnosticID: req.PlacementDiagnosticID,
+	})
+	if err != nil {
+		log.Printf("Failed to enqueue plan structure job: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start plan generation"})
 	}
-
-	if err := c.DB.Create(&learningPlan).Error; err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save structure"})
+	if created {
+		quota.keep()
+	} else {
+		// the queued job was paid for when it was created
+		quota.release()
 	}
 
-	return ctx.JSON(http.StatusOK, models.SuccessResponse{
-		Status:  http.StatusOK,
-		Message: "Learning plan structure generated successfully",
-		Data: map[string]interface{}{
-			"id":   learningPlan.ID,
-			"plan": plan,
-		},
-	})
+	return respondJobAccepted(ctx, job, created)
 }
 
 func (c *Controller) GetPlanStructure(ctx echo.Context) error {
-	id, _ := strconv.Atoi(ctx.Param("id"))
-	var plan models.LearningPlanStructure
-	if err := c.DB.First(&plan, id).Error; err != nil {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	plan, err := c.ownedPlan(userID, id)
+	if err != nil {
 		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
 	}
 
@@ -171,58 +199,52 @@ func (c *Controller) GenerateWeekContent(ctx echo.Context) error {
 	}
 
 	// check if the plan exists
-
-	var plan models.LearningPlanStructure
-	if err := c.DB.Where("id = ? AND user_id = ?", req.PlanID, userID).First(&plan).Error; err != nil {
+	if _, err := c.Learning.Plan(userID, req.PlanID); err != nil {
 		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
 	}
 
-	// check if the daily
-	var generatedContent models.GeneratedWeeklyContent
-	if err := c.DB.Where("plan_id = ? AND week_number = ? AND user_id = ?", req.PlanID, req.WeekNumber, userID).First(&generatedContent).Error; err == nil {
+	// a stale week has an open adaptation flag and must be regenerated
+	generatedContent, err := c.Learning.Week(userID, req.PlanID, req.WeekNumber)
+	if err == nil {
 		return ctx.JSON(http.StatusOK, models.SuccessResponse{
 			Status:  http.StatusOK,
 			Message: "content already generated",
 			Data:    generatedContent,
 		})
 	}
-
-	// Generate weekly content using OpenAI
-	content, err := utils.GenerateWeeklyContent(plan.Goal, req.WeekNumber, req.UserProgress)
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate content: " + err.Error()})
+	if errors.Is(err, learning.ErrStale) {
+		// adapting a week to the learner's progress is not charged to them
+		job, created, err := c.Learning.GenerateWeek(userID, req.PlanID, req.WeekNumber)
+		if err != nil {
+			log.Printf("Failed to enqueue weekly content job: %v", err)
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start content generation"})
+		}
+		return respondJobAccepted(ctx, job, created)
 	}
-
-	// Convert content to JSON for storage
-	contentJSON, err := json.Marshal(content)
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to serialize content"})
+	if !errors.Is(err, learning.ErrNotFound) {
+		log.Printf("Database error fetching weekly content: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch weekly content"})
 	}
 
-	// Convert user progress to JSON for storage
-	progressJSON, _ := json.Marshal(req.UserProgress)
-
-	// Save to database
-	generatedContent = models.GeneratedWeeklyContent{
-		PlanID:           req.PlanID,
-		WeekNumber:       req.WeekNumber,
-		ContentData:      datatypes.JSON(contentJSON),
-		GeneratedBasedOn: datatypes.JSON(progressJSON),
-		UserID:           userID,
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
 	}
+	defer quota.release()
 
-	if err := c.DB.Create(&generatedContent).Error; err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save content"})
+	job, created, err := c.Learning.GenerateWeek(userID, req.PlanID, req.WeekNumber)
+	if err != nil {
+		log.Printf("Failed to enqueue weekly content job: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start content generation"})
+	}
+	if created {
+		quota.keep()
+	} else {
+		// the queued job was paid for when it was created
+		quota.release()
 	}
 
-	return ctx.JSON(http.StatusOK, models.SuccessResponse{
-		Status:  http.StatusOK,
-		Message: "Content generated successfully",
-		Data: map[string]interface{}{
-			"id":      generatedContent.ID,
-			"content": content,
-		},
-	})
+	return respondJobAccepted(ctx, job, created)
 }
 
 func (c *Controller) GetWeekContent(ctx echo.Context) error {
@@ -248,14 +270,15 @@ func (c *Controller) GetWeekContent(ctx echo.Context) error {
 		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
 	}
 
-	var content models.GeneratedWeeklyContent
-	if err := c.DB.Where("plan_id = ? AND week_number = ? AND user_id = ?", planID, week, userID).First(&content).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
-				ErrorCode:    http.StatusNotFound,
-				ErrorMessage: "Content not found",
-			})
-		}
+	content, err := c.Learning.Week(userID, planID, week)
+	if errors.Is(err, learning.ErrNotFound) {
+		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
+			ErrorCode:    http.StatusNotFound,
+			ErrorMessage: "Content not found",
+		})
+	}
+	stale := errors.Is(err, learning.ErrStale)
+	if err != nil && !stale {
 		log.Printf("Database error fetching weekly content: %v", err)
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
@@ -274,10 +297,23 @@ func (c *Controller) GetWeekContent(ctx echo.Context) error {
 		})
 	}
 
+	response := WeekContentResponse{WeeklyContent: weeklyContent, Stale: stale}
+	message := "Content fetched successfully"
+	if stale {
+		// the regeneration is normally queued when the week is flagged; this
+		// only finds the pending job, or queues it if that failed
+		message = "Content is being adapted to your progress"
+		if job, _, err := c.Learning.GenerateWeek(userID, planID, week); err != nil {
+			log.Printf("Failed to enqueue weekly content job: %v", err)
+		} else {
+			response.RegenerationJobID = job.ID
+		}
+	}
+
 	return ctx.JSON(http.StatusOK, models.SuccessResponse{
 		Status:  http.StatusOK,
-		Message: "Content fetched successfully",
-		Data:    weeklyContent,
+		Message: message,
+		Data:    response,
 	})
 }
 
@@ -287,8 +323,8 @@ func (c *Controller) GetMyLearnings(ctx echo.Context) error {
 		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
 	}
 
-	var plans []models.LearningPlanStructure
-	if err := c.DB.Where("user_id = ?", userID).Find(&plans).Error; err != nil {
+	plans, err := c.Learning.Plans(userID)
+	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch user plans"})
 	}
 
@@ -330,50 +366,43 @@ func (c *Controller) GetDailyContent(ctx echo.Context) error {
 		})
 	}
 
-	// get the week content
-	var weekContent models.GeneratedWeeklyContent
-	err = c.DB.Where("plan_id = ? AND week_number = ? AND user_id = ?", planID, week, userID).First(&weekContent).Error
-	if err != nil {
+	daily, err := c.Learning.Day(userID, planID, week, day)
+	if err == nil {
+		return ctx.JSON(http.StatusOK, models.SuccessResponse{
+			Message: "Daily content fetched successfully",
+			Data:    c.Learning.HideAnswers(*daily),
+		})
+	}
+	if errors.Is(err, learning.ErrWeekNotGenerated) {
 		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
 			ErrorCode:    http.StatusNotFound,
 			ErrorMessage: "Week content not found",
 		})
 	}
-
-	var daily models.DailyContent
-	err = c.DB.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ?", planID, userID, week, day).First(&daily).Error
-	if err == nil {
-		return ctx.JSON(http.StatusOK, models.SuccessResponse{
-			Message: "Daily content fetched successfully",
-			Data:    daily,
-		})
+	if !errors.Is(err, learning.ErrNotFound) {
+		log.Printf("Database error fetching daily content: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch daily content"})
 	}
 
-	// Optionally, fetch user progress for this day/plan
-	userProgress := map[string]interface{}{} // TODO: fetch from progress table if available
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
+	}
+	defer quota.release()
 
-	dailyStructure := string(weekContent.ContentData)
-	// Generate content
-	plan := models.LearningPlanStructure{}
-	c.DB.Where("id = ? AND user_id = ?", planID, userID).First(&plan)
-	lesson, resources, genErr := utils.GenerateDailyContent(plan.Goal, dailyStructure, week, day, userProgress)
-	if genErr != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate daily content"})
+	job, created, err := c.Learning.GenerateDay(userID, planID, week, day)
+	if err != nil {
+		log.Printf("Failed to enqueue daily content job: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start daily content generation"})
 	}
-	daily = models.DailyContent{
-		PlanID:     planID,
-		UserID:     userID,
-		WeekNumber: week,
-		DayNumber:  day,
-		Content:    lesson,
-		Resources:  resources,
+	if created {
+		quota.keep()
+	} else {
+		// the queued job was paid for when it was created
+		quota.release()
 	}
-	c.DB.Create(&daily)
 
-	return ctx.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Daily content generated successfully",
-		Data:    daily,
-	})
+	return respondJobAccepted(ctx, job, created)
 }
 
 func (c *Controller) GenerateDailyExercises(ctx echo.Context) error {
@@ -407,27 +436,44 @@ func (c *Controller) GenerateDailyExercises(ctx echo.Context) error {
 		})
 	}
 
-	var daily models.DailyContent
-	err = c.DB.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ?", planID, userID, week, day).First(&daily).Error
+	daily, err := c.Learning.Lesson(userID, planID, week, day)
 	if err != nil {
 		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Daily content not found. Please generate the daily lesson first."})
 	}
 
-	userProgress := map[string]interface{}{} // TODO: fetch from progress table if available
+	userProgress, err := c.progressSnapshot(userID, planID)
+	if err != nil {
+		log.Printf("Failed to build progress snapshot: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch progress"})
+	}
+
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
+	}
+	defer quota.release()
 
-	exercises, err := utils.GenerateExercisesForLesson(string(daily.Content), userProgress)
+	language := daily.Language
+	if language == "" {
+		language = c.userLanguage(userID)
+	}
+	exercises, stamp, err := utils.GenerateExercisesForLesson(c.llmFor(userID, planID), string(daily.Content), userProgress, language)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate exercises"})
 	}
 
+	generatedAt := time.Now()
 	daily.Exercises = exercises
-	if err := c.DB.Save(&daily).Error; err != nil {
+	daily.ExercisesGeneratedAt = &generatedAt
+	daily.Prompts = mergePromptStamp(daily.Prompts, stamp)
+	if err := c.Learning.SaveLesson(daily); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save exercises"})
 	}
+	quota.keep()
 
 	return ctx.JSON(http.StatusOK, models.SuccessResponse{
 		Message: "Exercises generated and saved successfully",
-		Data:    daily.Exercises,
+		Data:    c.Learning.HideAnswers(*daily).Exercises,
 	})
 }
 
@@ -441,7 +487,8 @@ func (c *Controller) ValidateGoal(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Goal is required"})
 	}
 
-	appropriate, reason, err := utils.ValidateLearningGoal(req.Goal)
+	userID, _ := library.GetUserIDFronContext(ctx)
+	appropriate, reason, err := utils.ValidateLearningGoal(c.llmFor(userID, 0), req.Goal)
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate goal: " + err.Error()})
 	}
@@ -463,37 +510,8 @@ func (c *Controller) DeletePlan(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid Plan ID"})
 	}
 
-	// Use a transaction to ensure all or nothing is deleted
-	err = c.DB.Transaction(func(tx *gorm.DB) error {
-		// First, verify the plan exists and belongs to the user
-		var plan models.LearningPlanStructure
-		if err := tx.Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("plan not found")
-			}
-			return err
-		}
-
-		// Delete associated daily content
-		if err := tx.Where("plan_id = ? AND user_id = ?", planID, userID).Delete(&models.DailyContent{}).Error; err != nil {
-			return err
-		}
-
-		// Delete associated weekly content
-		if err := tx.Where("plan_id = ? AND user_id = ?", planID, userID).Delete(&models.GeneratedWeeklyContent{}).Error; err != nil {
-			return err
-		}
-
-		// Finally, delete the plan structure itself
-		if err := tx.Where("id = ? AND user_id = ?", planID, userID).Delete(&models.LearningPlanStructure{}).Error; err != nil {
-			return err
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		if err.Error() == "plan not found" {
+	if err := c.Learning.DeletePlan(userID, planID); err != nil {
+		if errors.Is(err, learning.ErrNotFound) {
 			return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan not found or you do not have permission to delete it"})
 		}
 		log.Printf("Failed to delete plan: %v", err)
@@ -0,0 +1,445 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/ratelimit"
+	"github.com/surahj/ai-mentor-backend/app/repository"
+	"gorm.io/datatypes"
+)
+
+// fakeQueue records generation jobs instead of running them. Like the real
+// queue, a second job with the key of a pending one is not created.
+type fakeQueue struct {
+	jobs []*models.GenerationJob
+}
+
+func (q *fakeQueue) Enqueue(userID int64, kind, dedupKey string, payload interface{}) (*models.GenerationJob, bool, error) {
+	for _, job := range q.jobs {
+		if job.DedupKey == dedupKey {
+			return job, false, nil
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	job := &models.GenerationJob{
+		UserID:   userID,
+		Kind:     kind,
+		DedupKey: dedupKey,
+		Payload:  datatypes.JSON(data),
+		Status:   models.JobStatusQueued,
+	}
+	job.ID = int64(len(q.jobs) + 1)
+	q.jobs = append(q.jobs, job)
+	return job, true, nil
+}
+
+type testEnv struct {
+	c     *Controller
+	repos repository.Repositories
+	queue *fakeQueue
+	e     *echo.Echo
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	repos := repository.NewMemory()
+	queue := &fakeQueue{}
+	c := &Controller{
+		Config:   &configs.Config{},
+		Quota:    ratelimit.NewQuota(ratelimit.NewMemoryStore(), map[string]configs.QuotaLimits{models.TierFree: {Daily: 2, Monthly: 10}}),
+		Users:    repos.Users,
+		Learning: learning.NewService(repos, queue),
+	}
+	return &testEnv{c: c, repos: repos, queue: queue, e: echo.New()}
+}
+
+// user creates a learner and returns their ID.
+func (env *testEnv) user(t *testing.T, email string) int64 {
+	t.Helper()
+	user := models.User{Email: email, Tier: models.TierFree, Role: models.RoleLearner}
+	if err := env.repos.Users.Create(&user); err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+func (env *testEnv) plan(t *testing.T, userID int64, goal string) models.LearningPlanStructure {
+	t.Helper()
+	structure, _ := json.Marshal(models.CompleteLearningPlan{Goal: goal, TotalWeeks: 2})
+	plan := models.LearningPlanStructure{UserID: userID, Goal: goal, TotalWeeks: 2, Structure: datatypes.JSON(structure)}
+	if err := env.repos.Plans.Create(&plan); err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func (env *testEnv) week(t *testing.T, plan models.LearningPlanStructure, week int) {
+	t.Helper()
+	data, _ := json.Marshal(models.WeeklyContent{Theme: "Basics", Objectives: []string{"Learn the basics"}})
+	content := models.GeneratedWeeklyContent{PlanID: plan.ID, UserID: plan.UserID, WeekNumber: week, Version: 1, ContentData: datatypes.JSON(data)}
+	if err := env.repos.Weeks.Create(&content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// call runs handler as userID with the path parameters given as name, value
+// pairs and decodes the JSON response into out, when set.
+func (env *testEnv) call(t *testing.T, handler echo.HandlerFunc, userID int64, method, body string, out interface{}, params ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := env.e.NewContext(req, rec)
+	ctx.Set("user_id", userID)
+	var names, values []string
+	for i := 0; i+1 < len(params); i += 2 {
+		names = append(names, params[i])
+		values = append(values, params[i+1])
+	}
+	ctx.SetParamNames(names...)
+	ctx.SetParamValues(values...)
+	if err := handler(ctx); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body.String())
+	}
+}
+
+func TestGeneratePlanStructureReturnsExistingPlan(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+	plan := env.plan(t, userID, "Learn Go")
+
+	var res struct {
+		Data models.LearningPlanStructure `json:"data"`
+	}
+	rec := env.call(t, env.c.GeneratePlanStructure, userID, http.MethodPost,
+		`{"goal":"Learn Go","total_weeks":2,"daily_commitment":30}`, &res)
+
+	expectStatus(t, rec, http.StatusOK)
+	if res.Data.ID != plan.ID {
+		t.Errorf("plan = %d, want the existing plan %d", res.Data.ID, plan.ID)
+	}
+	if len(env.queue.jobs) != 0 {
+		t.Errorf("queued %d jobs for an existing plan", len(env.queue.jobs))
+	}
+}
+
+func TestGeneratePlanStructureQueuesOneJobPerPlan(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+	body := `{"goal":"Learn Rust","total_weeks":4,"daily_commitment":45}`
+
+	var res struct {
+		Message string               `json:"message"`
+		Data    models.GenerationJob `json:"data"`
+	}
+	rec := env.call(t, env.c.GeneratePlanStructure, userID, http.MethodPost, body, &res)
+	expectStatus(t, rec, http.StatusAccepted)
+	if res.Data.Kind != models.JobKindPlanStructure {
+		t.Errorf("job kind = %q, want %q", res.Data.Kind, models.JobKindPlanStructure)
+	}
+	var payload learning.PlanJob
+	if err := json.Unmarshal(res.Data.Payload, &payload); err != nil || payload.Goal != "Learn Rust" || payload.TotalWeeks != 4 {
+		t.Errorf("payload = %s", res.Data.Payload)
+	}
+	if rec.Header().Get("X-Quota-Daily-Remaining") != "1" {
+		t.Errorf("X-Quota-Daily-Remaining = %q, want 1", rec.Header().Get("X-Quota-Daily-Remaining"))
+	}
+
+	rec = env.call(t, env.c.GeneratePlanStructure, userID, http.MethodPost, body, &res)
+	expectStatus(t, rec, http.StatusAccepted)
+	if res.Message != "Generation already in progress" || len(env.queue.jobs) != 1 {
+		t.Errorf("second request: message %q, %d jobs", res.Message, len(env.queue.jobs))
+	}
+	if rec.Header().Get("X-Quota-Daily-Remaining") != "1" {
+		t.Errorf("a pending job consumed quota again")
+	}
+}
+
+func TestGeneratePlanStructureEnforcesQuota(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+
+	for i, goal := range []string{"Learn Go", "Learn SQL"} {
+		rec := env.call(t, env.c.GeneratePlanStructure, userID, http.MethodPost,
+			`{"goal":"`+goal+`","total_weeks":2,"daily_commitment":30}`, nil)
+		expectStatus(t, rec, http.StatusAccepted)
+		if len(env.queue.jobs) != i+1 {
+			t.Fatalf("jobs = %d, want %d", len(env.queue.jobs), i+1)
+		}
+	}
+
+	rec := env.call(t, env.c.GeneratePlanStructure, userID, http.MethodPost,
+		`{"goal":"Learn Python","total_weeks":2,"daily_commitment":30}`, nil)
+	expectStatus(t, rec, http.StatusTooManyRequests)
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+}
+
+func TestSharedPlanIsOfferedToOtherUsers(t *testing.T) {
+	env := newTestEnv(t)
+	author := env.user(t, "author@example.com")
+	learner := env.user(t, "learner@example.com")
+	template := models.LearningPlanStructure{UserID: author, Goal: "Learn Go", TotalWeeks: 2, Shared: true}
+	if err := env.repos.Plans.Create(&template); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := env.c.Learning.SharedPlan(learner, "learn go", 2)
+	if err != nil || found.ID != template.ID {
+		t.Fatalf("SharedPlan = %v, %v; want plan %d", found, err, template.ID)
+	}
+	if _, err := env.c.Learning.SharedPlan(learner, "Learn Go", 4); err != learning.ErrNotFound {
+		t.Errorf("a plan of another length was offered: %v", err)
+	}
+	if _, err := env.c.Learning.SharedPlan(author, "Learn Go", 2); err != learning.ErrNotFound {
+		t.Errorf("a user's own plan was offered as a template: %v", err)
+	}
+}
+
+func TestGetPlanStructureIsScopedToOwner(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user(t, "owner@example.com")
+	other := env.user(t, "other@example.com")
+	plan := env.plan(t, owner, "Learn Go")
+	id := itoa(plan.ID)
+
+	var res struct {
+		Data struct {
+			ID   int64                       `json:"id"`
+			Plan models.CompleteLearningPlan `json:"plan"`
+		} `json:"data"`
+	}
+	rec := env.call(t, env.c.GetPlanStructure, owner, http.MethodGet, "", &res, "id", id)
+	expectStatus(t, rec, http.StatusOK)
+	if res.Data.ID != plan.ID || res.Data.Plan.Goal != "Learn Go" {
+		t.Errorf("got plan %d %q", res.Data.ID, res.Data.Plan.Goal)
+	}
+
+	rec = env.call(t, env.c.GetPlanStructure, other, http.MethodGet, "", nil, "id", id)
+	expectStatus(t, rec, http.StatusNotFound)
+}
+
+func TestGetMyLearningsListsOwnPlans(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user(t, "owner@example.com")
+	other := env.user(t, "other@example.com")
+	env.plan(t, owner, "Learn Go")
+	env.plan(t, owner, "Learn SQL")
+	env.plan(t, other, "Learn Rust")
+
+	var res struct {
+		Data []models.LearningPlanStructure `json:"data"`
+	}
+	rec := env.call(t, env.c.GetMyLearnings, owner, http.MethodGet, "", &res)
+	expectStatus(t, rec, http.StatusOK)
+	if len(res.Data) != 2 {
+		t.Fatalf("plans = %d, want 2", len(res.Data))
+	}
+	for _, plan := range res.Data {
+		if plan.UserID != owner {
+			t.Errorf("listed plan %d of user %d", plan.ID, plan.UserID)
+		}
+	}
+}
+
+func TestGenerateWeekContent(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+	plan := env.plan(t, userID, "Learn Go")
+	body := `{"plan_id":` + itoa(plan.ID) + `,"week_number":1}`
+
+	rec := env.call(t, env.c.GenerateWeekContent, userID, http.MethodPost, `{"plan_id":999,"week_number":1}`, nil)
+	expectStatus(t, rec, http.StatusNotFound)
+
+	rec = env.call(t, env.c.GenerateWeekContent, userID, http.MethodPost, body, nil)
+	expectStatus(t, rec, http.StatusAccepted)
+	if len(env.queue.jobs) != 1 || env.queue.jobs[0].Kind != models.JobKindWeeklyContent {
+		t.Fatalf("jobs = %+v", env.queue.jobs)
+	}
+
+	env.queue.jobs = nil
+	env.week(t, plan, 1)
+	rec = env.call(t, env.c.GenerateWeekContent, userID, http.MethodPost, body, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if len(env.queue.jobs) != 0 {
+		t.Errorf("queued a job for generated content")
+	}
+
+	// a flagged week is regenerated
+	if err := env.c.Learning.FlagWeek(&models.ContentAdaptationFlag{PlanID: plan.ID, UserID: userID, WeekNumber: 1, Kind: models.AdaptationRemedial, NeedsRegeneration: true}); err != nil {
+		t.Fatal(err)
+	}
+	rec = env.call(t, env.c.GenerateWeekContent, userID, http.MethodPost, body, nil)
+	expectStatus(t, rec, http.StatusAccepted)
+	if len(env.queue.jobs) != 1 {
+		t.Fatalf("jobs = %+v", env.queue.jobs)
+	}
+}
+
+func TestGetWeekContent(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+	plan := env.plan(t, userID, "Learn Go")
+	params := []string{"plan_id", itoa(plan.ID), "week_number", "1"}
+
+	rec := env.call(t, env.c.GetWeekContent, userID, http.MethodGet, "", nil, params...)
+	expectStatus(t, rec, http.StatusNotFound)
+
+	env.week(t, plan, 1)
+	var res struct {
+		Data models.WeeklyContent `json:"data"`
+	}
+	rec = env.call(t, env.c.GetWeekContent, userID, http.MethodGet, "", &res, params...)
+	expectStatus(t, rec, http.StatusOK)
+	if res.Data.Theme != "Basics" {
+		t.Errorf("theme = %q, want Basics", res.Data.Theme)
+	}
+
+	other := env.user(t, "other@example.com")
+	rec = env.call(t, env.c.GetWeekContent, other, http.MethodGet, "", nil, params...)
+	expectStatus(t, rec, http.StatusNotFound)
+
+	if err := env.c.Learning.FlagWeek(&models.ContentAdaptationFlag{PlanID: plan.ID, UserID: userID, WeekNumber: 1, NeedsRegeneration: true}); err != nil {
+		t.Fatal(err)
+	}
+	// a flagged week is served, marked stale, while it is regenerated
+	var stale struct {
+		Data WeekContentResponse `json:"data"`
+	}
+	rec = env.call(t, env.c.GetWeekContent, userID, http.MethodGet, "", &stale, params...)
+	expectStatus(t, rec, http.StatusOK)
+	if !stale.Data.Stale || stale.Data.Theme != "Basics" {
+		t.Errorf("content = %+v, want the stale current version", stale.Data)
+	}
+	if len(env.queue.jobs) != 1 || stale.Data.RegenerationJobID != env.queue.jobs[0].ID {
+		t.Errorf("jobs = %+v, regeneration job = %d", env.queue.jobs, stale.Data.RegenerationJobID)
+	}
+}
+
+func TestGetDailyContent(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+	plan := env.plan(t, userID, "Learn Go")
+	params := []string{"plan_id", itoa(plan.ID), "week_number", "1", "day_number", "2"}
+
+	rec := env.call(t, env.c.GetDailyContent, userID, http.MethodGet, "", nil, params...)
+	expectStatus(t, rec, http.StatusNotFound)
+
+	env.week(t, plan, 1)
+	var job struct {
+		Data models.GenerationJob `json:"data"`
+	}
+	rec = env.call(t, env.c.GetDailyContent, userID, http.MethodGet, "", &job, params...)
+	expectStatus(t, rec, http.StatusAccepted)
+	var payload learning.DayJob
+	if err := json.Unmarshal(job.Data.Payload, &payload); err != nil || payload != (learning.DayJob{PlanID: plan.ID, WeekNumber: 1, DayNumber: 2}) {
+		t.Errorf("payload = %s", job.Data.Payload)
+	}
+
+	daily := models.DailyContent{
+		PlanID:     plan.ID,
+		UserID:     userID,
+		WeekNumber: 1,
+		DayNumber:  2,
+		Content:    datatypes.JSON(`{"title":"Variables"}`),
+		Exercises:  datatypes.JSON(`[{"question":"2+2?","answer":"4","explanation":"arithmetic"}]`),
+	}
+	if err := env.repos.Days.Create(&daily); err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Data models.DailyContent `json:"data"`
+	}
+	rec = env.call(t, env.c.GetDailyContent, userID, http.MethodGet, "", &res, params...)
+	expectStatus(t, rec, http.StatusOK)
+	if res.Data.ID != daily.ID {
+		t.Errorf("daily = %d, want %d", res.Data.ID, daily.ID)
+	}
+	if strings.Contains(string(res.Data.Exercises), "answer") || !strings.Contains(string(res.Data.Exercises), "question") {
+		t.Errorf("exercises = %s, want answers hidden", res.Data.Exercises)
+	}
+}
+
+func TestDeletePlanRemovesContent(t *testing.T) {
+	env := newTestEnv(t)
+	owner := env.user(t, "owner@example.com")
+	other := env.user(t, "other@example.com")
+	plan := env.plan(t, owner, "Learn Go")
+	env.week(t, plan, 1)
+	if err := env.repos.Days.Create(&models.DailyContent{PlanID: plan.ID, UserID: owner, WeekNumber: 1, DayNumber: 1}); err != nil {
+		t.Fatal(err)
+	}
+	id := itoa(plan.ID)
+
+	rec := env.call(t, env.c.DeletePlan, other, http.MethodDelete, "", nil, "id", id)
+	expectStatus(t, rec, http.StatusNotFound)
+
+	rec = env.call(t, env.c.DeletePlan, owner, http.MethodDelete, "", nil, "id", id)
+	expectStatus(t, rec, http.StatusOK)
+
+	if _, err := env.repos.Plans.FindOwned(owner, plan.ID); err != repository.ErrNotFound {
+		t.Errorf("plan still exists: %v", err)
+	}
+	if _, err := env.repos.Weeks.FindCurrent(owner, plan.ID, 1); err != repository.ErrNotFound {
+		t.Errorf("weekly content still exists: %v", err)
+	}
+	if _, err := env.repos.Days.Find(owner, plan.ID, 1, 1); err != repository.ErrNotFound {
+		t.Errorf("daily content still exists: %v", err)
+	}
+
+	rec = env.call(t, env.c.DeletePlan, owner, http.MethodDelete, "", nil, "id", id)
+	expectStatus(t, rec, http.StatusNotFound)
+}
+
+func TestUpdateProfile(t *testing.T) {
+	env := newTestEnv(t)
+	userID := env.user(t, "learner@example.com")
+
+	rec := env.call(t, env.c.UpdateProfile, userID, http.MethodPut, `{"level":"beginner","country":"Kenya"}`, nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	user, err := env.repos.Users.FindByID(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Level == nil || *user.Level != "beginner" || user.Country == nil || *user.Country != "Kenya" {
+		t.Errorf("profile not saved: level %v, country %v", user.Level, user.Country)
+	}
+	if user.UpdatedAt.Before(time.Now().Add(-time.Minute)) {
+		t.Errorf("updated_at not set")
+	}
+
+	rec = env.call(t, env.c.GetProfile, 999, http.MethodGet, "", nil)
+	expectStatus(t, rec, http.StatusNotFound)
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+)
+
+// maxPastGoals caps the goals of other plans listed in a learner's history
+const maxPastGoals = 5
+
+// learnerProfile builds the profile injected into a user's generation
+// prompts. With withHistory set it also summarises the user's plans other than
+// excludePlanID; week and day prompts get the plan's own progress instead.
+func (c *Controller) learnerProfile(userID int64, withHistory bool, excludePlanID int64) models.LearnerProfile {
+	var user models.User
+	if err := c.DB.First(&user, userID).Error; err != nil {
+		return models.LearnerProfile{}
+	}
+	profile := utils.LearnerProfileFor(user)
+	if withHistory {
+		profile.History = c.learnerHistory(userID, excludePlanID)
+	}
+	return profile
+}
+
+// learnerHistory summarises the user's other plans, or returns nil for a
+// first plan.
+func (c *Controller) learnerHistory(userID, excludePlanID int64) *models.LearnerHistory {
+	var plans []models.LearningPlanStructure
+	if err := c.DB.Select("id", "goal").Where("user_id = ? AND id <> ?", userID, excludePlanID).Order("created_at DESC").Find(&plans).Error; err != nil || len(plans) == 0 {
+		return nil
+	}
+
+	history := &models.LearnerHistory{OtherPlans: len(plans)}
+	planIDs := make([]int64, 0, len(plans))
+	for _, p := range plans {
+		planIDs = append(planIDs, p.ID)
+		if len(history.PastGoals) < maxPastGoals {
+			history.PastGoals = append(history.PastGoals, p.Goal)
+		}
+	}
+
+	var completed int64
+	c.DB.Model(&models.LessonProgress{}).
+		Where("user_id = ? AND plan_id IN ? AND status = ?", userID, planIDs, models.LessonStatusCompleted).
+		Count(&completed)
+	history.LessonsCompleted = int(completed)
+
+	var scores struct {
+		Attempts int
+		Average  *float64
+	}
+	c.DB.Model(&models.ExerciseAttempt{}).
+		Select("COUNT(*) AS attempts, AVG(score) AS average").
+		Where("user_id = ? AND plan_id IN ?", userID, planIDs).
+		Scan(&scores)
+	history.ExerciseAttempts = scores.Attempts
+	if scores.Attempts > 0 {
+		history.ExerciseAccuracy = scores.Average
+	}
+	return history
+}
+
+// generationBasis records the progress and learner profile content was
+// generated from. Progress is nil for plan structures. The profile
+// attributes are the ones the traced prompts contained, which are none when
+// the prompt version does not render the learner profile.
+func generationBasis(progress *models.ProgressSnapshot, flag *models.ContentAdaptationFlag, profile models.LearnerProfile, trace prompts.Trace) models.GenerationBasis {
+	basis := models.GenerationBasis{
+		Progress:          progress,
+		Adaptation:        flag,
+		ProfileAttributes: trace.ProfileAttributes,
+	}
+	if basis.ProfileAttributes == nil {
+		basis.ProfileAttributes = []string{}
+	}
+	if len(basis.ProfileAttributes) > 0 {
+		basis.Profile = &profile
+	}
+	return basis
+}
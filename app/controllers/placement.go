@@ -0,0 +1,303 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/datatypes"
+)
+
+type PlacementRequest struct {
+	Goal string `json:"goal" example:"Learn Go"`
+}
+
+type PlacementAnswerInput struct {
+	QuestionIndex int    `json:"question_index" example:"3"`
+	Answer        string `json:"answer" example:"defer"`
+}
+
+type SubmitPlacementRequest struct {
+	Answers []PlacementAnswerInput `json:"answers"`
+}
+
+// PlacementQuestionView is a placement question as shown to the learner. The
+// answer and explanation are only included once the question was answered.
+type PlacementQuestionView struct {
+	Index       int      `json:"index" example:"3"`
+	Question    string   `json:"question" example:"Which statement runs a function when the surrounding function returns?"`
+	Options     []string `json:"options" example:"[\"defer\",\"go\",\"finally\"]"`
+	Difficulty  string   `json:"difficulty" example:"intermediate"`
+	Concept     string   `json:"concept" example:"Defer"`
+	Answer      string   `json:"answer,omitempty" example:"defer"`
+	Explanation string   `json:"explanation,omitempty" example:"Deferred calls run on return."`
+	Given       *string  `json:"given,omitempty" example:"defer"`
+	Correct     *bool    `json:"correct,omitempty" example:"true"`
+}
+
+// PlacementResponse is a placement diagnostic with the questions of the
+// current stage to answer and the questions answered so far.
+type PlacementResponse struct {
+	ID            int64                   `json:"id" example:"4"`
+	Goal          string                  `json:"goal" example:"Learn Go"`
+	Language      string                  `json:"language,omitempty" example:"English"`
+	Status        string                  `json:"status" example:"in_progress"`
+	Stage         string                  `json:"stage,omitempty" example:"intermediate"`
+	Questions     []PlacementQuestionView `json:"questions"`
+	Answered      []PlacementQuestionView `json:"answered"`
+	Level         string                  `json:"level,omitempty" example:"intermediate"`
+	KnownConcepts []string                `json:"known_concepts,omitempty"`
+	Score         *float64                `json:"score,omitempty" example:"0.75"`
+	CompletedAt   *time.Time              `json:"completed_at,omitempty"`
+}
+
+// StartPlacement generates an adaptive placement quiz for a goal. The quiz
+// starts with intermediate questions; see SubmitPlacementAnswers.
+// POST /learnings/placement
+func (c *Controller) StartPlacement(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req PlacementRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	req.Goal = strings.TrimSpace(req.Goal)
+	if req.Goal == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Goal is required"})
+	}
+
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
+	}
+	defer quota.release()
+
+	language := c.userLanguage(userID)
+	questions, stamp, err := utils.GeneratePlacementQuiz(c.llmFor(userID, 0), req.Goal, language, c.learnerProfile(userID, false, 0))
+	if err != nil {
+		log.Printf("Failed to generate placement quiz: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to generate placement quiz"})
+	}
+	quota.keep()
+
+	questionsJSON, _ := json.Marshal(questions)
+	diagnostic := models.PlacementDiagnostic{
+		UserID:    userID,
+		Goal:      req.Goal,
+		Language:  language,
+		Questions: datatypes.JSON(questionsJSON),
+		Stage:     utils.FirstPlacementStage,
+		Status:    models.PlacementStatusInProgress,
+		Prompts:   stamp.JSON(),
+	}
+	if err := c.DB.Create(&diagnostic).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save placement quiz"})
+	}
+
+	return ctx.JSON(http.StatusCreated, models.SuccessResponse{
+		Status:  http.StatusCreated,
+		Message: "Placement quiz created",
+		Data:    placementResponse(diagnostic),
+	})
+}
+
+// GetPlacement returns a placement diagnostic of the current user.
+// GET /learnings/placement/:id
+func (c *Controller) GetPlacement(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	diagnostic, err := c.ownedPlacement(userID, id)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Placement diagnostic not found"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Placement diagnostic retrieved successfully",
+		Data:    placementResponse(*diagnostic),
+	})
+}
+
+// SubmitPlacementAnswers grades the answers to the current stage of a
+// placement quiz. Passing the intermediate stage moves on to advanced
+// questions and failing it to beginner ones; after the second stage the level
+// and known concepts are inferred and the diagnostic is completed.
+// POST /learnings/placement/:id/answers
+func (c *Controller) SubmitPlacementAnswers(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	diagnostic, err := c.ownedPlacement(userID, id)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Placement diagnostic not found"})
+	}
+	if diagnostic.Status == models.PlacementStatusCompleted {
+		return ctx.JSON(http.StatusConflict, map[string]string{"error": "Placement diagnostic is already completed"})
+	}
+
+	var req SubmitPlacementRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	var questions []models.PlacementQuestion
+	var answers []models.PlacementAnswer
+	_ = json.Unmarshal(diagnostic.Questions, &questions)
+	if len(diagnostic.Answers) > 0 {
+		_ = json.Unmarshal(diagnostic.Answers, &answers)
+	}
+
+	inStage := map[int]bool{}
+	for _, i := range utils.PlacementStageQuestions(questions, diagnostic.Stage) {
+		inStage[i] = true
+	}
+	given := map[int]string{}
+	for _, a := range req.Answers {
+		if !inStage[a.QuestionIndex] {
+			return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+				ErrorCode:    http.StatusBadRequest,
+				ErrorMessage: "Question " + strconv.Itoa(a.QuestionIndex) + " is not part of the current stage",
+			})
+		}
+		given[a.QuestionIndex] = a.Answer
+	}
+
+	answers = append(answers, utils.GradePlacementStage(questions, diagnostic.Stage, given)...)
+	answersJSON, _ := json.Marshal(answers)
+	diagnostic.Answers = datatypes.JSON(answersJSON)
+
+	diagnostic.Stage = utils.NextPlacementStage(questions, answers, diagnostic.Stage)
+	message := "Answers graded, continue with the next stage"
+	if diagnostic.Stage == "" {
+		level, known, score := utils.InferPlacement(questions, answers)
+		knownJSON, _ := json.Marshal(known)
+		now := time.Now()
+		diagnostic.Status = models.PlacementStatusCompleted
+		diagnostic.Level = level
+		diagnostic.KnownConcepts = datatypes.JSON(knownJSON)
+		diagnostic.Score = &score
+		diagnostic.CompletedAt = &now
+		message = "Placement diagnostic completed"
+	}
+
+	if err := c.DB.Save(diagnostic).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save placement answers"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    placementResponse(*diagnostic),
+	})
+}
+
+func (c *Controller) ownedPlacement(userID, id int64) (*models.PlacementDiagnostic, error) {
+	var diagnostic models.PlacementDiagnostic
+	if err := c.DB.Where("id = ? AND user_id = ?", id, userID).First(&diagnostic).Error; err != nil {
+		return nil, err
+	}
+	return &diagnostic, nil
+}
+
+// completedPlacement loads a completed placement diagnostic of the user for
+// plan generation.
+func (c *Controller) completedPlacement(userID, id int64) (*models.PlacementDiagnostic, error) {
+	diagnostic, err := c.ownedPlacement(userID, id)
+	if err != nil {
+		return nil, errors.New("placement diagnostic not found")
+	}
+	if diagnostic.Status != models.PlacementStatusCompleted {
+		return nil, errors.New("placement diagnostic is not completed")
+	}
+	return diagnostic, nil
+}
+
+// placementResult is the placement outcome injected into the generation
+// prompts of a plan, or nil when the plan has no completed diagnostic.
+func (c *Controller) placementResult(userID int64, diagnosticID *int64) *models.PlacementResult {
+	if diagnosticID == nil {
+		return nil
+	}
+	diagnostic, err := c.completedPlacement(userID, *diagnosticID)
+	if err != nil {
+		return nil
+	}
+	result := &models.PlacementResult{DiagnosticID: diagnostic.ID, Level: diagnostic.Level}
+	if len(diagnostic.KnownConcepts) > 0 {
+		_ = json.Unmarshal(diagnostic.KnownConcepts, &result.KnownConcepts)
+	}
+	return result
+}
+
+func placementResponse(d models.PlacementDiagnostic) PlacementResponse {
+	var questions []models.PlacementQuestion
+	var answers []models.PlacementAnswer
+	_ = json.Unmarshal(d.Questions, &questions)
+	if len(d.Answers) > 0 {
+		_ = json.Unmarshal(d.Answers, &answers)
+	}
+
+	response := PlacementResponse{
+		ID:          d.ID,
+		Goal:        d.Goal,
+		Language:    d.Language,
+		Status:      d.Status,
+		Stage:       d.Stage,
+		Questions:   []PlacementQuestionView{},
+		Answered:    []PlacementQuestionView{},
+		Level:       d.Level,
+		Score:       d.Score,
+		CompletedAt: d.CompletedAt,
+	}
+	if len(d.KnownConcepts) > 0 {
+		_ = json.Unmarshal(d.KnownConcepts, &response.KnownConcepts)
+	}
+
+	for _, i := range utils.PlacementStageQuestions(questions, d.Stage) {
+		q := questions[i]
+		response.Questions = append(response.Questions, PlacementQuestionView{
+			Index:      i,
+			Question:   q.Question,
+			Options:    q.Options,
+			Difficulty: q.Difficulty,
+			Concept:    q.Concept,
+		})
+	}
+	for _, a := range answers {
+		if a.QuestionIndex < 0 || a.QuestionIndex >= len(questions) {
+			continue
+		}
+		q := questions[a.QuestionIndex]
+		given, correct := a.Answer, a.Correct
+		response.Answered = append(response.Answered, PlacementQuestionView{
+			Index:       a.QuestionIndex,
+			Question:    q.Question,
+			Options:     q.Options,
+			Difficulty:  q.Difficulty,
+			Concept:     q.Concept,
+			Answer:      q.Answer,
+			Explanation: q.Explanation,
+			Given:       &given,
+			Correct:     &correct,
+		})
+	}
+	return response
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+type SharePlanRequest struct {
+	Shared bool `json:"shared"`
+}
+
+// ownedPlan returns a plan structure only if it belongs to the user.
+func (c *Controller) ownedPlan(userID, planID int64) (*models.LearningPlanStructure, error) {
+	return c.Learning.Plan(userID, planID)
+}
+
+// SharePlan lets the owner publish a plan structure as a template other users
+// can clone, or withdraw it again. Weekly and daily content are never shared.
+// PUT /learnings/structure/:id/share
+func (c *Controller) SharePlan(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req SharePlanRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	plan, err := c.ownedPlan(userID, id)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
+	}
+
+	plan.Shared = req.Shared
+	if err := c.DB.Model(plan).Update("shared", req.Shared).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update plan"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Plan sharing updated successfully",
+		Data:    plan,
+	})
+}
+
+// GetSharedPlans lists shared plan structures, optionally filtered by goal.
+// GET /learnings/shared?goal=
+func (c *Controller) GetSharedPlans(ctx echo.Context) error {
+	query := c.DB.Model(&models.LearningPlanStructure{}).Where("shared = ?", true)
+	if goal := strings.TrimSpace(ctx.QueryParam("goal")); goal != "" {
+		query = query.Where("goal ILIKE ?", "%"+goal+"%")
+	}
+
+	var templates []models.SharedPlanTemplate
+	if err := query.Select("id, goal, total_weeks, structure").Order("created_at DESC").Limit(50).Scan(&templates).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch shared plans"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Shared plans fetched successfully",
+		Data:    templates,
+	})
+}
+
+// ClonePlan copies a shared plan structure into the caller's account. The
+// clone is a new private plan; the original row is never handed out.
+// POST /learnings/structure/:id/clone
+func (c *Controller) ClonePlan(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var template models.LearningPlanStructure
+	if err := c.DB.Where("id = ? AND (shared = ? OR user_id = ?)", id, true, userID).First(&template).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Shared plan not found"})
+	}
+
+	return c.respondClonedPlan(ctx, userID, template)
+}
+
+func (c *Controller) respondClonedPlan(ctx echo.Context, userID int64, template models.LearningPlanStructure) error {
+	clone, err := c.clonePlan(userID, template)
+	if err != nil {
+		log.Printf("Failed to clone plan %d: %v", template.ID, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to clone plan"})
+	}
+
+	var completePlan models.CompleteLearningPlan
+	if err := json.Unmarshal(clone.Structure, &completePlan); err != nil {
+		log.Printf("Failed to unmarshal plan structure: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to parse plan structure"})
+	}
+	completePlan.ID = clone.ID
+
+	return ctx.JSON(http.StatusCreated, models.SuccessResponse{
+		Status:  http.StatusCreated,
+		Message: "Plan cloned successfully",
+		Data: map[string]interface{}{
+			"id":   clone.ID,
+			"plan": completePlan,
+		},
+	})
+}
+
+// clonePlan creates a private copy of a plan structure for the user.
+func (c *Controller) clonePlan(userID int64, template models.LearningPlanStructure) (*models.LearningPlanStructure, error) {
+	sourceID := template.ID
+	clone := models.LearningPlanStructure{
+		UserID:       userID,
+		Goal:         template.Goal,
+		TotalWeeks:   template.TotalWeeks,
+		Structure:    template.Structure,
+		SourcePlanID: &sourceID,
+		Language:     template.Language,
+	}
+	if err := c.DB.Create(&clone).Error; err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
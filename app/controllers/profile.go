@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/surahj/ai-mentor-backend/app/library"
 	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
 )
 
 func (c *Controller) GetProfile(ctx echo.Context) error {
@@ -17,8 +19,8 @@ func (c *Controller) GetProfile(ctx echo.Context) error {
 		})
 	}
 
-	var user models.User
-	if err := c.DB.First(&user, userID).Error; err != nil {
+	user, err := c.Users.FindByID(userID)
+	if err != nil {
 		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
 			ErrorCode:    http.StatusNotFound,
 			ErrorMessage: "User not found",
@@ -59,8 +61,17 @@ func (c *Controller) UpdateProfile(ctx echo.Context) error {
 		})
 	}
 
-	var user models.User
-	if err := c.DB.First(&user, userID).Error; err != nil {
+	if req.PreferredLanguage != nil && strings.TrimSpace(*req.PreferredLanguage) != "" {
+		if _, ok := utils.KnownLanguage(*req.PreferredLanguage); !ok {
+			return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+				ErrorCode:    http.StatusBadRequest,
+				ErrorMessage: "Unsupported preferred language",
+			})
+		}
+	}
+
+	user, err := c.Users.FindByID(userID)
+	if err != nil {
 		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
 			ErrorCode:    http.StatusNotFound,
 			ErrorMessage: "User not found",
@@ -87,7 +98,7 @@ func (c *Controller) UpdateProfile(ctx echo.Context) error {
 		user.Country = req.Country
 	}
 
-	if err := c.DB.Save(&user).Error; err != nil {
+	if err := c.Users.Save(user); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			ErrorCode:    http.StatusInternalServerError,
 			ErrorMessage: "Failed to update profile",
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/gorm"
+)
+
+// strugglingAccuracy is the exercise accuracy below which a day is reported as struggling
+const strugglingAccuracy = 0.5
+
+type LessonProgressRequest struct {
+	PlanID           int64  `json:"plan_id"`
+	WeekNumber       int    `json:"week_number"`
+	DayNumber        int    `json:"day_number"`
+	Status           string `json:"status"`             // started, completed
+	TimeSpentSeconds int    `json:"time_spent_seconds"` // added to the time already recorded
+	Confidence       *int   `json:"confidence"`         // 1-5
+}
+
+// UpdateLessonProgress records that a lesson was started or completed, the time
+// spent on it and the learner's self-rated confidence.
+func (c *Controller) UpdateLessonProgress(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var req LessonProgressRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if req.PlanID == 0 || req.WeekNumber == 0 || req.DayNumber == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID, week number and day number are required",
+		})
+	}
+
+	if req.Status != "" && req.Status != models.LessonStatusStarted && req.Status != models.LessonStatusCompleted {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Status must be 'started' or 'completed'",
+		})
+	}
+
+	if req.TimeSpentSeconds < 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Time spent cannot be negative",
+		})
+	}
+
+	if req.Confidence != nil && (*req.Confidence < 1 || *req.Confidence > 5) {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Confidence must be between 1 and 5",
+		})
+	}
+
+	var plan models.LearningPlanStructure
+	if err := c.DB.Where("id = ? AND user_id = ?", req.PlanID, userID).First(&plan).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
+	}
+
+	var progress models.LessonProgress
+	err = c.DB.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ?", req.PlanID, userID, req.WeekNumber, req.DayNumber).First(&progress).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Database error fetching lesson progress: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch lesson progress"})
+	}
+
+	now := time.Now()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		progress = models.LessonProgress{
+			PlanID:     req.PlanID,
+			UserID:     userID,
+			WeekNumber: req.WeekNumber,
+			DayNumber:  req.DayNumber,
+			Status:     models.LessonStatusStarted,
+			StartedAt:  &now,
+		}
+	}
+
+	// a completed lesson stays completed
+	if req.Status == models.LessonStatusCompleted && progress.Status != models.LessonStatusCompleted {
+		progress.Status = models.LessonStatusCompleted
+		progress.CompletedAt = &now
+	}
+	progress.TimeSpentSeconds += req.TimeSpentSeconds
+	if req.Confidence != nil {
+		progress.Confidence = req.Confidence
+	}
+
+	if err := c.DB.Save(&progress).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save lesson progress"})
+	}
+
+	if req.Status == models.LessonStatusCompleted {
+		c.evaluateAdaptation(userID, req.PlanID)
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Lesson progress saved successfully",
+		Data:    progress,
+	})
+}
+
+// GetProgress returns the aggregated progress snapshot for a plan.
+func (c *Controller) GetProgress(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, _ := strconv.ParseInt(ctx.Param("plan_id"), 10, 64)
+	if planID == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID is required",
+		})
+	}
+
+	var plan models.LearningPlanStructure
+	if err := c.DB.Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Plan structure not found"})
+	}
+
+	snapshot, err := c.progressSnapshot(userID, planID)
+	if err != nil {
+		log.Printf("Failed to build progress snapshot: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch progress"})
+	}
+
+	var lessons []models.LessonProgress
+	if err := c.DB.Where("plan_id = ? AND user_id = ?", planID, userID).Order("week_number, day_number").Find(&lessons).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch progress"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Progress fetched successfully",
+		Data: map[string]interface{}{
+			"summary": snapshot,
+			"lessons": lessons,
+		},
+	})
+}
+
+// progressSnapshot aggregates the lesson progress and exercise attempts of a plan.
+func (c *Controller) progressSnapshot(userID, planID int64) (models.ProgressSnapshot, error) {
+	snapshot := models.ProgressSnapshot{
+		PlanID:      planID,
+		GeneratedAt: time.Now(),
+	}
+
+	var lessons []models.LessonProgress
+	if err := c.DB.Where("plan_id = ? AND user_id = ?", planID, userID).Find(&lessons).Error; err != nil {
+		return snapshot, err
+	}
+
+	confidenceSum, confidenceCount, seconds := 0, 0, 0
+	for _, l := range lessons {
+		snapshot.LessonsStarted++
+		if l.Status == models.LessonStatusCompleted {
+			snapshot.LessonsCompleted++
+		}
+		seconds += l.TimeSpentSeconds
+		if l.Confidence != nil {
+			confidenceSum += *l.Confidence
+			confidenceCount++
+		}
+		if l.WeekNumber > snapshot.LastWeekNumber || (l.WeekNumber == snapshot.LastWeekNumber && l.DayNumber > snapshot.LastDayNumber) {
+			snapshot.LastWeekNumber = l.WeekNumber
+			snapshot.LastDayNumber = l.DayNumber
+		}
+	}
+	snapshot.TotalTimeSpentMinutes = seconds / 60
+	if confidenceCount > 0 {
+		avg := float64(confidenceSum) / float64(confidenceCount)
+		snapshot.AverageConfidence = &avg
+	}
+
+	var attempts []models.ExerciseAttempt
+	if err := c.DB.Where("plan_id = ? AND user_id = ?", planID, userID).Order("week_number, day_number").Find(&attempts).Error; err != nil {
+		return snapshot, err
+	}
+
+	type dayKey struct{ week, day int }
+	days := map[dayKey]*models.DayPerformance{}
+	var order []dayKey
+	scoreSum := 0.0
+	for _, a := range attempts {
+		key := dayKey{a.WeekNumber, a.DayNumber}
+		perf, ok := days[key]
+		if !ok {
+			perf = &models.DayPerformance{WeekNumber: a.WeekNumber, DayNumber: a.DayNumber}
+			days[key] = perf
+			order = append(order, key)
+		}
+		perf.Attempts++
+		perf.Accuracy += a.Score
+		scoreSum += a.Score
+	}
+
+	snapshot.ExerciseAttempts = len(attempts)
+	if len(attempts) > 0 {
+		accuracy := scoreSum / float64(len(attempts))
+		snapshot.ExerciseAccuracy = &accuracy
+	}
+	recentScore, recentCount := 0.0, 0
+	for _, a := range attempts {
+		if a.WeekNumber > snapshot.RecentWeekNumber {
+			snapshot.RecentWeekNumber = a.WeekNumber
+			recentScore, recentCount = 0, 0
+		}
+		if a.WeekNumber == snapshot.RecentWeekNumber {
+			recentScore += a.Score
+			recentCount++
+		}
+	}
+	if recentCount > 0 {
+		recent := recentScore / float64(recentCount)
+		snapshot.RecentAccuracy = &recent
+	}
+
+	for _, key := range order {
+		perf := days[key]
+		perf.Accuracy /= float64(perf.Attempts)
+		if perf.Accuracy < strugglingAccuracy {
+			snapshot.StrugglingDays = append(snapshot.StrugglingDays, *perf)
+		}
+	}
+
+	if err := c.flashcardProgress(&snapshot, userID, planID); err != nil {
+		return snapshot, err
+	}
+
+	return snapshot, nil
+}
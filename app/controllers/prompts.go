@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/datatypes"
+)
+
+// mergePromptStamp adds the prompts in stamp to a record's existing stamp.
+func mergePromptStamp(existing datatypes.JSON, stamp prompts.Stamp) datatypes.JSON {
+	merged := prompts.Stamp{}
+	if len(existing) > 0 {
+		_ = json.Unmarshal(existing, &merged)
+	}
+	merged.Merge(stamp)
+	return merged.JSON()
+}
+
+// GET /admin/prompts
+func (c *Controller) ListPrompts(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Prompt templates fetched successfully",
+		Data:    prompts.Default.List(),
+	})
+}
+
+// PreviewPrompt renders a prompt with the data generation would use for the
+// given plan, week and day, without calling the LLM. The plan owner's progress
+// is used. version defaults to the active version.
+// GET /admin/prompts/:name/preview
+func (c *Controller) PreviewPrompt(ctx echo.Context) error {
+	name := ctx.Param("name")
+	version, _ := strconv.Atoi(ctx.QueryParam("version"))
+	if _, ok := prompts.Default.Get(name, version); !ok {
+		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
+			ErrorCode:    http.StatusNotFound,
+			ErrorMessage: "Prompt template not found",
+		})
+	}
+
+	data, err := c.promptPreviewData(ctx, name)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: err.Error(),
+		})
+	}
+
+	rendered, err := prompts.Default.RenderVersion(name, version, data)
+	if err != nil {
+		log.Printf("Failed to render prompt %s: %v", name, err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to render prompt: " + err.Error()})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Prompt rendered successfully",
+		Data:    rendered,
+	})
+}
+
+// promptPreviewData assembles the template data for a prompt from the query
+// parameters plan_id, week, day, goal, exercise and answer.
+func (c *Controller) promptPreviewData(ctx echo.Context, name string) (interface{}, error) {
+	planID, _ := strconv.ParseInt(ctx.QueryParam("plan_id"), 10, 64)
+	week, _ := strconv.Atoi(ctx.QueryParam("week"))
+	day, _ := strconv.Atoi(ctx.QueryParam("day"))
+
+	var plan models.LearningPlanStructure
+	if planID != 0 {
+		if err := c.DB.First(&plan, planID).Error; err != nil {
+			return nil, errors.New("plan not found")
+		}
+	}
+
+	switch name {
+	case prompts.ValidateGoal:
+		goal := ctx.QueryParam("goal")
+		if goal == "" {
+			goal = plan.Goal
+		}
+		if goal == "" {
+			return nil, errors.New("goal or plan_id is required")
+		}
+		return utils.GoalPrompt{Goal: goal}, nil
+
+	case prompts.PlacementQuiz:
+		goal := ctx.QueryParam("goal")
+		if goal == "" {
+			goal = plan.Goal
+		}
+		if goal == "" {
+			return nil, errors.New("goal or plan_id is required")
+		}
+		if planID == 0 {
+			return utils.NewPlacementPrompt(goal, "", models.LearnerProfile{}), nil
+		}
+		return utils.NewPlacementPrompt(goal, c.planLanguage(&plan), c.learnerProfile(plan.UserID, false, planID)), nil
+
+	case prompts.PlanStructure:
+		if planID == 0 {
+			goal := ctx.QueryParam("goal")
+			if goal == "" {
+				return nil, errors.New("goal or plan_id is required")
+			}
+			totalWeeks, _ := strconv.Atoi(ctx.QueryParam("total_weeks"))
+			dailyCommitment, _ := strconv.Atoi(ctx.QueryParam("daily_commitment"))
+			return utils.PlanPrompt{Goal: goal, TotalWeeks: totalWeeks, DailyCommitment: dailyCommitment}, nil
+		}
+		var structure models.CompleteLearningPlan
+		_ = json.Unmarshal(plan.Structure, &structure)
+		profile := c.learnerProfile(plan.UserID, true, planID)
+		profile.Placement = c.placementResult(plan.UserID, plan.PlacementDiagnosticID)
+		return utils.PlanPrompt{
+			Goal:            plan.Goal,
+			TotalWeeks:      plan.TotalWeeks,
+			DailyCommitment: structure.DailyCommitment,
+			Language:        c.planLanguage(&plan),
+			Profile:         profile,
+		}, nil
+	}
+
+	if planID == 0 || week == 0 {
+		return nil, errors.New("plan_id and week are required")
+	}
+	progress, err := c.progressSnapshot(plan.UserID, planID)
+	if err != nil {
+		return nil, err
+	}
+	language := c.planLanguage(&plan)
+	profile := c.learnerProfile(plan.UserID, false, planID)
+	profile.Placement = c.placementResult(plan.UserID, plan.PlacementDiagnosticID)
+
+	if name == prompts.WeeklyContent {
+		flag, err := c.Learning.AdaptationFlag(plan.UserID, planID, week)
+		if err != nil {
+			return nil, err
+		}
+		return utils.WeekPrompt{Goal: plan.Goal, WeekNumber: week, Progress: progress, Adaptation: utils.AdaptationGuidance(flag), Language: language, Profile: profile}, nil
+	}
+
+	if day == 0 {
+		return nil, errors.New("day is required")
+	}
+
+	switch name {
+	case prompts.DailyLesson, prompts.DailyResources:
+		var weekContent models.GeneratedWeeklyContent
+		if err := c.DB.Where("plan_id = ? AND week_number = ? AND superseded_at IS NULL", planID, week).First(&weekContent).Error; err != nil {
+			return nil, errors.New("week content not found")
+		}
+		return utils.DayPrompt{Goal: plan.Goal, DailyStructure: string(weekContent.ContentData), Week: week, Day: day, Progress: progress, Language: language, Profile: profile}, nil
+	}
+
+	var daily models.DailyContent
+	if err := c.DB.Where("plan_id = ? AND week_number = ? AND day_number = ?", planID, week, day).First(&daily).Error; err != nil {
+		return nil, errors.New("daily content not found")
+	}
+
+	switch name {
+	case prompts.TutorChat:
+		return c.tutorPromptData(&models.TutorThread{UserID: plan.UserID, PlanID: planID, WeekNumber: week, DayNumber: day})
+	case prompts.Exercises:
+		return utils.ExercisePrompt{LessonContent: string(daily.Content), Progress: progress, Language: language}, nil
+	case prompts.GradeAnswer:
+		var exercises []models.Exercise
+		_ = json.Unmarshal(daily.Exercises, &exercises)
+		index, _ := strconv.Atoi(ctx.QueryParam("exercise"))
+		if index < 0 || index >= len(exercises) {
+			return nil, errors.New("exercise index out of range")
+		}
+		return utils.GradePrompt{
+			Question:        exercises[index].Question,
+			ReferenceAnswer: exercises[index].Answer,
+			Answer:          ctx.QueryParam("answer"),
+		}, nil
+	}
+
+	return nil, errors.New("no preview data for prompt " + name)
+}
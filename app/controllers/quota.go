@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/ratelimit"
+)
+
+func (c *Controller) userTier(userID int64) string {
+	user, err := c.Users.FindByID(userID)
+	if err != nil || user.Tier == "" {
+		return models.TierFree
+	}
+	return user.Tier
+}
+
+// generationQuota is a generation reserved by reserveGenerationQuota. It is
+// refunded by release unless keep marked it as spent; callers defer release
+// right after reserving. A nil generationQuota does nothing.
+type generationQuota struct {
+	c      *Controller
+	ctx    echo.Context
+	userID int64
+	status ratelimit.QuotaStatus
+	kept   bool
+}
+
+// keep marks the reserved generation as spent.
+func (q *generationQuota) keep() {
+	if q != nil {
+		q.kept = true
+	}
+}
+
+// release refunds the reserved generation unless it was kept, and corrects
+// the quota headers if the response has not been sent yet.
+func (q *generationQuota) release() {
+	if q == nil || q.kept {
+		return
+	}
+	q.kept = true
+	if err := q.c.Quota.Refund(q.userID, q.status); err != nil {
+		log.Printf("Failed to refund generation quota: %v", err)
+		return
+	}
+	q.status.DailyUsed--
+	q.status.MonthlyUsed--
+	if !q.ctx.Response().Committed {
+		q.status.SetHeaders(q.ctx.Response().Header())
+	}
+}
+
+// reserveGenerationQuota consumes one of the user's generations and sets the
+// quota headers. When the user has used up their generations it writes a 429
+// response and returns false.
+func (c *Controller) reserveGenerationQuota(ctx echo.Context, userID int64) (*generationQuota, bool, error) {
+	status, ok, err := c.Quota.Reserve(userID, c.userTier(userID))
+	if err != nil {
+		// quotas protect spend but must not take generation down with them
+		log.Printf("Failed to reserve generation quota: %v", err)
+		return nil, true, nil
+	}
+
+	status.SetHeaders(ctx.Response().Header())
+	if !ok {
+		status.SetRetryAfter(ctx.Response().Header())
+		return nil, false, ctx.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			ErrorCode:    http.StatusTooManyRequests,
+			ErrorMessage: "AI generation quota exceeded. Please try again later.",
+		})
+	}
+	return &generationQuota{c: c, ctx: ctx, userID: userID, status: status}, true, nil
+}
+
+// GetQuota returns the user's AI generation usage for the current day and month.
+// GET /quota
+func (c *Controller) GetQuota(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	status, err := c.Quota.Check(userID, c.userTier(userID))
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch quota"})
+	}
+	status.SetHeaders(ctx.Response().Header())
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Quota fetched successfully",
+		Data:    status,
+	})
+}
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/gorm"
+)
+
+// issueTokens starts a new session for the user on the requesting device and
+// returns its access and refresh tokens.
+func (c *Controller) issueTokens(ctx echo.Context, userID int64) (*models.AuthTokens, error) {
+	refreshToken, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		UserID:           userID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        ctx.Request().UserAgent(),
+		IPAddress:        ctx.RealIP(),
+		ExpiresAt:        now.Add(c.Config.JWT.RefreshTTL),
+		LastUsedAt:       now,
+	}
+	if err := c.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := utils.GenerateJWT(c.Config.JWT, userID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthTokens{
+		Token:            token,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// allowSignIn writes a 403 response and returns false when the account is
+// suspended.
+func allowSignIn(ctx echo.Context, user models.User) (bool, error) {
+	if !user.Suspended() {
+		return true, nil
+	}
+	return false, ctx.JSON(http.StatusForbidden, models.ErrorResponse{
+		ErrorCode:    http.StatusForbidden,
+		ErrorMessage: "Account suspended",
+	})
+}
+
+// authResponse is the payload returned by every sign-in flow.
+func authResponse(tokens *models.AuthTokens, user models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"token":              tokens.Token,
+		"refresh_token":      tokens.RefreshToken,
+		"expires_at":         tokens.ExpiresAt,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+		"user": map[string]interface{}{
+			"id":         user.ID,
+			"email":      user.Email,
+			"first_name": user.FirstName,
+			"last_name":  user.LastName,
+			"role":       user.Role,
+		},
+	}
+}
+
+// RefreshToken exchanges a refresh token for a new access token and rotates
+// the refresh token. Reusing a refresh token that was already rotated revokes
+// the session, since only a stolen copy can be replayed.
+func (c *Controller) RefreshToken(ctx echo.Context) error {
+	var req models.RefreshTokenRequest
+	if err := ctx.Bind(&req); err != nil || req.RefreshToken == "" {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Refresh token is required",
+		})
+	}
+
+	invalid := models.ErrorResponse{
+		ErrorCode:    http.StatusUnauthorized,
+		ErrorMessage: "Invalid or expired refresh token",
+	}
+
+	hash := utils.HashToken(req.RefreshToken)
+	now := time.Now()
+
+	var session models.Session
+	err := c.DB.Where("refresh_token_hash = ?", hash).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var reused models.Session
+		if c.DB.Where("previous_token_hash = ? AND revoked_at IS NULL", hash).First(&reused).Error == nil {
+			log.Printf("Refresh token reuse detected for session %d, revoking it", reused.ID)
+			c.DB.Model(&reused).Update("revoked_at", now)
+		}
+		return ctx.JSON(http.StatusUnauthorized, invalid)
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to refresh token",
+		})
+	}
+	if !session.Active(now) {
+		return ctx.JSON(http.StatusUnauthorized, invalid)
+	}
+
+	refreshToken, err := utils.GenerateRandomToken()
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to refresh token",
+		})
+	}
+
+	// the hash condition makes concurrent refreshes with the same token race safely
+	result := c.DB.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, hash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  utils.HashToken(refreshToken),
+			"previous_token_hash": hash,
+			"last_used_at":        now,
+			"ip_address":          ctx.RealIP(),
+			"user_agent":          ctx.Request().UserAgent(),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return ctx.JSON(http.StatusUnauthorized, invalid)
+	}
+
+	token, expiresAt, err := utils.GenerateJWT(c.Config.JWT, session.UserID, session.ID)
+	if err != nil {
+		log.Printf("Error generating token: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to generate token",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Token refreshed successfully",
+		Data: models.AuthTokens{
+			Token:            token,
+			RefreshToken:     refreshToken,
+			ExpiresAt:        expiresAt,
+			RefreshExpiresAt: session.ExpiresAt,
+		},
+	})
+}
+
+// Logout revokes the session the request was made with.
+func (c *Controller) Logout(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+	sessionID, _ := ctx.Get("session_id").(int64)
+
+	if err := c.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to log out",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Logged out successfully",
+	})
+}
+
+// LogoutAll revokes every session of the user, signing out all devices.
+func (c *Controller) LogoutAll(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	if err := c.revokeUserSessions(c.DB, userID); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to log out",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Logged out of all devices successfully",
+	})
+}
+
+// ListSessions returns the user's active sessions, one per signed-in device.
+func (c *Controller) ListSessions(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	var sessions []models.Session
+	if err := c.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").Find(&sessions).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to fetch sessions",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Sessions fetched successfully",
+		Data:    sessions,
+	})
+}
+
+func (c *Controller) revokeUserSessions(db *gorm.DB, userID int64) error {
+	return db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
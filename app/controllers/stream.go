@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// Server-sent events emitted by StreamDailyContent
+const (
+	streamEventDelta = "delta"
+	streamEventDone  = "done"
+	streamEventError = "error"
+)
+
+// StreamDailyContent generates a day's content like GetDailyContent but sends
+// the lesson explanation HTML over server-sent events while it is produced.
+// Each "delta" event carries {"html": "..."} to append; a final "done" event
+// carries the persisted DailyContent, or an "error" event carries the failure.
+// GET /learnings/daily-content/:day_number/:week_number/:plan_id/stream
+func (c *Controller) StreamDailyContent(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+	planID, _ := strconv.ParseInt(ctx.Param("plan_id"), 10, 64)
+	week, _ := strconv.Atoi(ctx.Param("week_number"))
+	day, _ := strconv.Atoi(ctx.Param("day_number"))
+
+	if planID == 0 || week == 0 || day == 0 {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID, week number and day number are required",
+		})
+	}
+
+	_, err = c.Learning.Day(userID, planID, week, day)
+	if errors.Is(err, learning.ErrWeekNotGenerated) {
+		return ctx.JSON(http.StatusNotFound, models.ErrorResponse{
+			ErrorCode:    http.StatusNotFound,
+			ErrorMessage: "Week content not found",
+		})
+	}
+
+	// content that already exists is sent as is and costs no quota
+	var quota *generationQuota
+	if err != nil {
+		var ok bool
+		if quota, ok, err = c.reserveGenerationQuota(ctx, userID); !ok {
+			return err
+		}
+		defer quota.release()
+	}
+
+	res := startSSE(ctx)
+
+	daily, err := c.generateDailyContent(userID, planID, week, day, func(html string) error {
+		if err := ctx.Request().Context().Err(); err != nil {
+			return err
+		}
+		return writeSSE(res, streamEventDelta, map[string]string{"html": html})
+	})
+	if err != nil {
+		log.Printf("Failed to stream daily content: %v", err)
+		return writeSSE(res, streamEventError, models.ErrorResponse{
+			ErrorCode:    http.StatusInternalServerError,
+			ErrorMessage: "Failed to generate daily content",
+		})
+	}
+
+	quota.keep()
+
+	return writeSSE(res, streamEventDone, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Daily content generated successfully",
+		Data:    c.Learning.HideAnswers(*daily),
+	})
+}
+
+// startSSE sends the headers of a server-sent event stream. The server write
+// timeout is lifted for the response, since a stream lasts as long as the
+// generation behind it.
+func startSSE(ctx echo.Context) *echo.Response {
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// stop reverse proxies from buffering the stream
+	res.Header().Set("X-Accel-Buffering", "no")
+	_ = http.NewResponseController(res.Writer).SetWriteDeadline(time.Time{})
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+	return res
+}
+
+func writeSSE(res *echo.Response, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,373 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTutorMessageLength = 4000
+	maxTutorTitleLength   = 80
+)
+
+var errTutorDayNotFound = errors.New("daily content not found")
+
+type CreateTutorThreadRequest struct {
+	Title string `json:"title" example:"Questions about JSX"`
+}
+
+type TutorMessageRequest struct {
+	Content string `json:"content" example:"Why does JSX need a build step?"`
+}
+
+// TutorTurnResponse is a learner's question and the tutor's reply
+type TutorTurnResponse struct {
+	Message models.TutorMessage `json:"message"`
+	Reply   models.TutorMessage `json:"reply"`
+}
+
+// CreateTutorThread starts a tutor conversation about a generated day.
+// POST /learnings/daily-content/:day_number/:week_number/:plan_id/tutor/threads
+func (c *Controller) CreateTutorThread(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, week, day, ok := tutorDayParams(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID, week number and day number are required",
+		})
+	}
+
+	var req CreateTutorThreadRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	if _, err := c.Learning.Lesson(userID, planID, week, day); err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Daily content not found"})
+	}
+
+	thread := models.TutorThread{
+		UserID:     userID,
+		PlanID:     planID,
+		WeekNumber: week,
+		DayNumber:  day,
+		Title:      tutorTitle(req.Title),
+	}
+	if err := c.DB.Create(&thread).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create thread"})
+	}
+
+	return ctx.JSON(http.StatusCreated, models.SuccessResponse{
+		Status:  http.StatusCreated,
+		Message: "Tutor thread created",
+		Data:    thread,
+	})
+}
+
+// ListTutorThreads lists the tutor conversations about a day, most recent first.
+// GET /learnings/daily-content/:day_number/:week_number/:plan_id/tutor/threads
+func (c *Controller) ListTutorThreads(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	planID, week, day, ok := tutorDayParams(ctx)
+	if !ok {
+		return ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Plan ID, week number and day number are required",
+		})
+	}
+
+	threads := []models.TutorThread{}
+	if err := c.DB.Where("user_id = ? AND plan_id = ? AND week_number = ? AND day_number = ?", userID, planID, week, day).
+		Order("updated_at DESC").Find(&threads).Error; err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch threads"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Tutor threads fetched successfully",
+		Data:    threads,
+	})
+}
+
+// GetTutorThread returns a tutor conversation with all of its messages.
+// GET /learnings/tutor/threads/:id
+func (c *Controller) GetTutorThread(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var thread models.TutorThread
+	err = c.DB.Preload("Messages", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("id = ? AND user_id = ?", id, userID).First(&thread).Error
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Tutor thread not found"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Tutor thread retrieved successfully",
+		Data:    thread,
+	})
+}
+
+// DeleteTutorThread deletes a tutor conversation and its messages.
+// DELETE /learnings/tutor/threads/:id
+func (c *Controller) DeleteTutorThread(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	result := c.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.TutorThread{})
+	if result.Error != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to delete thread"})
+	}
+	if result.RowsAffected == 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Tutor thread not found"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Tutor thread deleted successfully",
+	})
+}
+
+// SendTutorMessage asks the tutor a question in a thread and returns its reply.
+// POST /learnings/tutor/threads/:id/messages
+func (c *Controller) SendTutorMessage(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	thread, content, ok, err := c.tutorMessageRequest(ctx, userID)
+	if !ok {
+		return err
+	}
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
+	}
+	defer quota.release()
+
+	turn, err := c.tutorTurn(thread, content, nil)
+	if errors.Is(err, errTutorDayNotFound) {
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Daily content not found"})
+	}
+	if err != nil {
+		log.Printf("Failed to answer tutor message: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get a reply from the tutor"})
+	}
+	quota.keep()
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Tutor replied",
+		Data:    turn,
+	})
+}
+
+// StreamTutorMessage is SendTutorMessage over server-sent events: "delta"
+// events carry {"text": "..."} to append as the reply is generated and a
+// final "done" event carries the persisted turn, or an "error" event the
+// failure.
+// POST /learnings/tutor/threads/:id/messages/stream
+func (c *Controller) StreamTutorMessage(ctx echo.Context) error {
+	userID, err := library.GetUserIDFronContext(ctx)
+	if err != nil || userID == 0 {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	thread, content, ok, err := c.tutorMessageRequest(ctx, userID)
+	if !ok {
+		return err
+	}
+	quota, ok, err := c.reserveGenerationQuota(ctx, userID)
+	if !ok {
+		return err
+	}
+	defer quota.release()
+
+	res := startSSE(ctx)
+
+	turn, err := c.tutorTurn(thread, content, func(delta string) error {
+		if err := ctx.Request().Context().Err(); err != nil {
+			return err
+		}
+		return writeSSE(res, streamEventDelta, map[string]string{"text": delta})
+	})
+	if err != nil {
+		log.Printf("Failed to stream tutor reply: %v", err)
+		status, message := http.StatusInternalServerError, "Failed to get a reply from the tutor"
+		if errors.Is(err, errTutorDayNotFound) {
+			status, message = http.StatusNotFound, "Daily content not found"
+		}
+		return writeSSE(res, streamEventError, models.ErrorResponse{ErrorCode: status, ErrorMessage: message})
+	}
+	quota.keep()
+
+	return writeSSE(res, streamEventDone, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Tutor replied",
+		Data:    turn,
+	})
+}
+
+// tutorMessageRequest loads the thread and validates the question of a tutor
+// message request. On failure it writes the error response and returns false.
+func (c *Controller) tutorMessageRequest(ctx echo.Context, userID int64) (*models.TutorThread, string, bool, error) {
+	id, _ := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	var thread models.TutorThread
+	if err := c.DB.Where("id = ? AND user_id = ?", id, userID).First(&thread).Error; err != nil {
+		return nil, "", false, ctx.JSON(http.StatusNotFound, map[string]string{"error": "Tutor thread not found"})
+	}
+
+	var req TutorMessageRequest
+	if err := ctx.Bind(&req); err != nil {
+		return nil, "", false, ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" || utf8.RuneCountInString(content) > maxTutorMessageLength {
+		return nil, "", false, ctx.JSON(http.StatusBadRequest, models.ErrorResponse{
+			ErrorCode:    http.StatusBadRequest,
+			ErrorMessage: "Content is required and may be at most " + strconv.Itoa(maxTutorMessageLength) + " characters",
+		})
+	}
+	return &thread, content, true, nil
+}
+
+// tutorTurn answers a question in a thread and persists both messages. Turns
+// beyond the tutor's context are first folded into the thread summary; if
+// summarizing fails they are left out of this reply and retried next turn.
+func (c *Controller) tutorTurn(thread *models.TutorThread, question string, onDelta services.StreamHandler) (*TutorTurnResponse, error) {
+	data, err := c.tutorPromptData(thread)
+	if err != nil {
+		return nil, err
+	}
+	llm := c.llmFor(thread.UserID, thread.PlanID)
+	stamp := prompts.Stamp{}
+
+	var pending []models.TutorMessage
+	if err := c.DB.Where("thread_id = ? AND id > ?", thread.ID, thread.SummarizedThroughID).Order("id ASC").Find(&pending).Error; err != nil {
+		return nil, err
+	}
+	older, recent := utils.SplitTutorHistory(pending)
+	if len(older) > 0 {
+		summary, summaryStamp, err := utils.SummarizeTutorTurns(llm, utils.TutorSummaryPrompt{
+			LessonTitle:     data.Lesson.Title,
+			PreviousSummary: thread.Summary,
+			Turns:           older,
+			Language:        data.Language,
+		})
+		if err != nil {
+			log.Printf("Failed to summarize tutor thread %d: %v", thread.ID, err)
+		} else {
+			thread.Summary = summary
+			thread.SummarizedThroughID = older[len(older)-1].ID
+			stamp.Merge(summaryStamp)
+		}
+	}
+	data.Summary = thread.Summary
+
+	reply, replyStamp, err := utils.TutorReply(llm, data, recent, question, onDelta)
+	if err != nil {
+		return nil, err
+	}
+	stamp.Merge(replyStamp)
+
+	turn := &TutorTurnResponse{
+		Message: models.TutorMessage{ThreadID: thread.ID, Role: services.LLMRoleUser, Content: question},
+		Reply:   models.TutorMessage{ThreadID: thread.ID, Role: services.LLMRoleAssistant, Content: reply, Prompts: stamp.JSON()},
+	}
+	if thread.Title == "" {
+		thread.Title = tutorTitle(question)
+	}
+
+	err = c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&turn.Message).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&turn.Reply).Error; err != nil {
+			return err
+		}
+		return tx.Model(thread).Select("title", "summary", "summarized_through_id", "updated_at").Updates(thread).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return turn, nil
+}
+
+// tutorPromptData grounds the tutor in the thread's day: the lesson, its
+// exercises with the learner's attempts since they were generated, and the
+// learner profile.
+func (c *Controller) tutorPromptData(thread *models.TutorThread) (utils.TutorPrompt, error) {
+	daily, err := c.Learning.Lesson(thread.UserID, thread.PlanID, thread.WeekNumber, thread.DayNumber)
+	if err != nil {
+		return utils.TutorPrompt{}, errTutorDayNotFound
+	}
+	plan, err := c.ownedPlan(thread.UserID, thread.PlanID)
+	if err != nil {
+		return utils.TutorPrompt{}, errTutorDayNotFound
+	}
+
+	data := utils.TutorPrompt{
+		Goal:     plan.Goal,
+		Week:     thread.WeekNumber,
+		Day:      thread.DayNumber,
+		Language: c.planLanguage(plan),
+		Profile:  c.learnerProfile(thread.UserID, false, plan.ID),
+	}
+	data.Profile.Placement = c.placementResult(thread.UserID, plan.PlacementDiagnosticID)
+	_ = json.Unmarshal(daily.Content, &data.Lesson)
+
+	var exercises []models.Exercise
+	if len(daily.Exercises) > 0 && daily.ExercisesGeneratedAt != nil && json.Unmarshal(daily.Exercises, &exercises) == nil {
+		var attempts []models.ExerciseAttempt
+		c.DB.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ? AND created_at >= ?", daily.PlanID, daily.UserID, daily.WeekNumber, daily.DayNumber, *daily.ExercisesGeneratedAt).
+			Order("created_at ASC").Find(&attempts)
+		data.Exercises = utils.TutorExercises(exercises, attempts)
+	}
+	return data, nil
+}
+
+func tutorDayParams(ctx echo.Context) (planID int64, week, day int, ok bool) {
+	planID, _ = strconv.ParseInt(ctx.Param("plan_id"), 10, 64)
+	week, _ = strconv.Atoi(ctx.Param("week_number"))
+	day, _ = strconv.Atoi(ctx.Param("day_number"))
+	return planID, week, day, planID != 0 && week != 0 && day != 0
+}
+
+// tutorTitle turns a title or first question into a one line thread title.
+func tutorTitle(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= maxTutorTitleLength {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:maxTutorTitleLength-1])) + "…"
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+)
+
+// llmFor returns the LLM provider to use on behalf of a user, recording the
+// usage of every call against the user and plan.
+func (c *Controller) llmFor(userID, planID int64) utils.LLM {
+	llm := utils.LLM{LLMProvider: c.LLM, JSONRepairAttempts: c.Config.LLM.JSONMaxRetries}
+	if c.Usage != nil {
+		llm.LLMProvider = c.Usage.Provider(c.LLM, userID, planID)
+	}
+	return llm
+}
+
+// GetUsageCosts reports LLM token usage and cost by user, day and purpose.
+// from and to are YYYY-MM-DD dates (UTC, to inclusive) and default to the
+// last 30 days.
+// GET /admin/usage/costs
+func (c *Controller) GetUsageCosts(ctx echo.Context) error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today.AddDate(0, 0, -29), today
+
+	if v := ctx.QueryParam("from"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "from must be a YYYY-MM-DD date"})
+		}
+		from = d
+	}
+	if v := ctx.QueryParam("to"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "to must be a YYYY-MM-DD date"})
+		}
+		to = d
+	}
+	if to.Before(from) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "to must not be before from"})
+	}
+
+	report, err := c.Usage.Report(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		log.Printf("Failed to build usage report: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to build usage report"})
+	}
+
+	return ctx.JSON(http.StatusOK, models.SuccessResponse{
+		Status:  http.StatusOK,
+		Message: "Usage costs fetched successfully",
+		Data:    report,
+	})
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// preBaselineSchema is what AutoMigrate created before versioned migrations,
+// which the baseline has to adopt.
+const preBaselineSchema = `
+CREATE TABLE "users" (
+    "id" bigserial PRIMARY KEY,
+    "email" text NOT NULL,
+    "password" text DEFAULT null,
+    "first_name" text DEFAULT null,
+    "last_name" text DEFAULT null,
+    "daily_commitment" bigint NOT NULL,
+    "learning_goal" text NOT NULL,
+    "age" bigint DEFAULT null,
+    "level" text DEFAULT null,
+    "background" text DEFAULT null,
+    "preferred_language" text DEFAULT null,
+    "interests" text DEFAULT null,
+    "country" text DEFAULT null,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_verified" boolean DEFAULT false,
+    "otp" text,
+    "otp_expires_at" timestamptz,
+    "auth_provider" text DEFAULT 'email'
+);
+CREATE UNIQUE INDEX "idx_users_email" ON "users" ("email");
+CREATE INDEX "idx_users_deleted_at" ON "users" ("deleted_at");
+INSERT INTO "users" ("email", "daily_commitment", "learning_goal") VALUES ('old@example.com', 30, 'Learn Go');
+
+CREATE TABLE "learning_plan_structures" (
+    "id" bigserial PRIMARY KEY,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "goal" text,
+    "total_weeks" bigint,
+    "structure" JSONB
+);
+
+CREATE TABLE "generated_weekly_contents" (
+    "id" bigserial PRIMARY KEY,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "content_data" JSONB,
+    "generated_based_on" JSONB,
+    "created_at" timestamptz
+);
+
+CREATE TABLE "daily_contents" (
+    "id" bigserial PRIMARY KEY,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "day_number" bigint,
+    "content" JSONB,
+    "exercises" JSONB,
+    "resources" JSONB
+);
+`
+
+// TestMigrationsCreateModelColumns applies the migrations to an empty
+// database and to one created by AutoMigrate before the baseline, and checks
+// that every column of every model exists. It needs Postgres, so it only runs
+// when TEST_DATABASE_URL is set; each case uses a schema of its own.
+func TestMigrationsCreateModelColumns(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	t.Run("empty database", func(t *testing.T) {
+		db := newTestSchema(t, dsn)
+		migrator := migrateUp(t, db)
+		assertModelColumns(t, db)
+
+		if _, err := migrator.Down(context.Background(), len(migrator.migrations)); err != nil {
+			t.Fatalf("down migrations: %v", err)
+		}
+	})
+
+	t.Run("pre-baseline database", func(t *testing.T) {
+		db := newTestSchema(t, dsn)
+		if err := db.Exec(preBaselineSchema).Error; err != nil {
+			t.Fatal(err)
+		}
+		migrateUp(t, db)
+		assertModelColumns(t, db)
+
+		var role string
+		if err := db.Raw(`SELECT "role" FROM "users" WHERE "email" = 'old@example.com'`).Scan(&role).Error; err != nil {
+			t.Fatal(err)
+		}
+		if role != "learner" {
+			t.Errorf("role of an existing user = %q, want learner", role)
+		}
+
+		for _, column := range []string{"otp", "otp_expires_at"} {
+			if db.Migrator().HasColumn("users", column) {
+				t.Errorf("column users.%s is not dropped by the migrations", column)
+			}
+		}
+	})
+}
+
+// newTestSchema creates an empty schema, dropped when the test ends, and
+// returns a connection that uses it.
+func newTestSchema(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if err := admin.Exec(`CREATE SCHEMA "` + schema + `"`).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(`DROP SCHEMA "` + schema + `" CASCADE`)
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("TEST_DATABASE_URL must be a postgres:// URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	db, err := gorm.Open(postgres.Open(u.String()), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func migrateUp(t *testing.T, db *gorm.DB) *Migrator {
+	t.Helper()
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("up migrations: %v", err)
+	}
+	return migrator
+}
+
+// assertModelColumns reports the model columns the database lacks.
+func assertModelColumns(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, model := range Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatal(err)
+		}
+		columns, err := db.Migrator().ColumnTypes(model)
+		if err != nil {
+			t.Fatalf("columns of %s: %v", stmt.Schema.Table, err)
+		}
+		existing := map[string]bool{}
+		for _, column := range columns {
+			existing[column.Name()] = true
+		}
+		if len(existing) == 0 {
+			t.Errorf("table %s is not created by the migrations", stmt.Schema.Table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !existing[field.DBName] {
+				t.Errorf("column %s.%s is not created by the migrations", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
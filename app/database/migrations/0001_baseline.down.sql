@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS "tutor_messages";
+DROP TABLE IF EXISTS "tutor_threads";
+DROP TABLE IF EXISTS "flashcard_reviews";
+DROP TABLE IF EXISTS "flashcards";
+DROP TABLE IF EXISTS "placement_diagnostics";
+DROP TABLE IF EXISTS "daily_content_translations";
+DROP TABLE IF EXISTS "prompt_templates";
+DROP TABLE IF EXISTS "llm_usages";
+DROP TABLE IF EXISTS "rate_limit_counters";
+DROP TABLE IF EXISTS "one_time_codes";
+DROP TABLE IF EXISTS "sessions";
+DROP TABLE IF EXISTS "plan_ratings";
+DROP TABLE IF EXISTS "plan_templates";
+DROP TABLE IF EXISTS "categories";
+DROP TABLE IF EXISTS "generation_jobs";
+DROP TABLE IF EXISTS "content_adaptation_flags";
+DROP TABLE IF EXISTS "exercise_attempts";
+DROP TABLE IF EXISTS "lesson_progresses";
+DROP TABLE IF EXISTS "daily_contents";
+DROP TABLE IF EXISTS "generated_weekly_contents";
+DROP TABLE IF EXISTS "learning_plan_structures";
+DROP TABLE IF EXISTS "users";
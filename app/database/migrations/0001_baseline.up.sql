@@ -0,0 +1,387 @@
+-- Baseline schema, matching what gorm AutoMigrate created before versioned
+-- migrations. IF NOT EXISTS lets databases created by AutoMigrate adopt it;
+-- 0004 adds the columns such databases may lack and the indexes on them.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "email" text NOT NULL,
+    "password" text DEFAULT null,
+    "first_name" text DEFAULT null,
+    "last_name" text DEFAULT null,
+    "daily_commitment" bigint NOT NULL,
+    "learning_goal" text NOT NULL,
+    "age" bigint DEFAULT null,
+    "level" text DEFAULT null,
+    "background" text DEFAULT null,
+    "preferred_language" text DEFAULT null,
+    "interests" text DEFAULT null,
+    "country" text DEFAULT null,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "is_verified" boolean DEFAULT false,
+    "auth_provider" text DEFAULT 'email',
+    "tier" text DEFAULT 'free',
+    "role" varchar(16) DEFAULT 'learner',
+    "suspended_at" timestamptz DEFAULT null,
+    "suspension_reason" text DEFAULT null,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "learning_plan_structures" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "goal" text,
+    "total_weeks" bigint,
+    "structure" JSONB,
+    "shared" boolean DEFAULT false,
+    "source_plan_id" bigint,
+    "template_id" bigint,
+    "language" varchar(64),
+    "prompts" JSONB,
+    "generated_based_on" JSONB,
+    "placement_diagnostic_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_learning_plan_structures_user_id" ON "learning_plan_structures" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "generated_weekly_contents" (
+    "id" bigserial,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "version" bigint DEFAULT 1,
+    "content_data" JSONB,
+    "generated_based_on" JSONB,
+    "superseded_at" timestamptz,
+    "language" varchar(64),
+    "prompts" JSONB,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "daily_contents" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "day_number" bigint,
+    "content" JSONB,
+    "exercises" JSONB,
+    "resources" JSONB,
+    "prompts" JSONB,
+    "language" varchar(64),
+    "generated_based_on" JSONB,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "lesson_progresses" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "day_number" bigint,
+    "status" text,
+    "started_at" timestamptz,
+    "completed_at" timestamptz,
+    "time_spent_seconds" bigint,
+    "confidence" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_lesson_progress_day" ON "lesson_progresses" ("plan_id","user_id","week_number","day_number");
+
+CREATE TABLE IF NOT EXISTS "exercise_attempts" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "day_number" bigint,
+    "exercise_index" bigint,
+    "answer" text,
+    "is_correct" boolean,
+    "score" decimal,
+    "feedback" text,
+    "grading_mode" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_exercise_attempt_day" ON "exercise_attempts" ("plan_id","user_id","week_number","day_number");
+
+CREATE TABLE IF NOT EXISTS "content_adaptation_flags" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "kind" text,
+    "needs_regeneration" boolean,
+    "reason" text,
+    "resolved_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_content_adaptation_flags_user_id" ON "content_adaptation_flags" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_content_adaptation_flags_plan_id" ON "content_adaptation_flags" ("plan_id");
+
+CREATE TABLE IF NOT EXISTS "generation_jobs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "kind" text,
+    "dedup_key" text,
+    "payload" JSONB,
+    "status" text,
+    "attempts" bigint,
+    "max_attempts" bigint,
+    "run_at" timestamptz,
+    "locked_at" timestamptz,
+    "locked_by" text,
+    "last_error" text,
+    "result" JSONB,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_generation_jobs_run_at" ON "generation_jobs" ("run_at");
+CREATE INDEX IF NOT EXISTS "idx_generation_jobs_status" ON "generation_jobs" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_generation_jobs_active_dedup" ON "generation_jobs" ("dedup_key") WHERE status IN ('queued','running');
+CREATE INDEX IF NOT EXISTS "idx_generation_jobs_user_id" ON "generation_jobs" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "categories" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "name" text NOT NULL,
+    "slug" text NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_categories_slug" ON "categories" ("slug");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_categories_name" ON "categories" ("name");
+
+CREATE TABLE IF NOT EXISTS "plan_templates" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "category_id" bigint,
+    "title" text NOT NULL,
+    "goal" text NOT NULL,
+    "description" text,
+    "level" text,
+    "total_weeks" bigint,
+    "daily_commitment" bigint,
+    "structure" JSONB,
+    "source_plan_id" bigint,
+    "published" boolean DEFAULT false,
+    "published_at" timestamptz,
+    "start_count" bigint DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_plan_templates_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_plan_templates_category_id" ON "plan_templates" ("category_id");
+CREATE INDEX IF NOT EXISTS "idx_plan_templates_published" ON "plan_templates" ("published");
+CREATE INDEX IF NOT EXISTS "idx_plan_templates_source_plan_id" ON "plan_templates" ("source_plan_id");
+CREATE INDEX IF NOT EXISTS "idx_plan_templates_total_weeks" ON "plan_templates" ("total_weeks");
+CREATE INDEX IF NOT EXISTS "idx_plan_templates_level" ON "plan_templates" ("level");
+
+CREATE TABLE IF NOT EXISTS "plan_ratings" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "rating" bigint,
+    "comment" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_plan_ratings_plan_user" ON "plan_ratings" ("plan_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "sessions" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "refresh_token_hash" text NOT NULL,
+    "previous_token_hash" text,
+    "user_agent" text,
+    "ip_address" text,
+    "expires_at" timestamptz,
+    "last_used_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sessions_revoked_at" ON "sessions" ("revoked_at");
+CREATE INDEX IF NOT EXISTS "idx_sessions_previous_token_hash" ON "sessions" ("previous_token_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sessions_refresh_token_hash" ON "sessions" ("refresh_token_hash");
+CREATE INDEX IF NOT EXISTS "idx_sessions_user_id" ON "sessions" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "one_time_codes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "purpose" text,
+    "code_hash" text NOT NULL,
+    "expires_at" timestamptz,
+    "attempts" bigint,
+    "max_attempts" bigint,
+    "consumed_at" timestamptz,
+    "locked_until" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_one_time_codes_user_purpose" ON "one_time_codes" ("user_id","purpose");
+
+CREATE TABLE IF NOT EXISTS "rate_limit_counters" (
+    "key" text,
+    "window_start" timestamptz,
+    "count" bigint NOT NULL DEFAULT 0,
+    "expires_at" timestamptz,
+    PRIMARY KEY ("key","window_start")
+);
+CREATE INDEX IF NOT EXISTS "idx_rate_limit_counters_expires_at" ON "rate_limit_counters" ("expires_at");
+
+CREATE TABLE IF NOT EXISTS "llm_usages" (
+    "id" bigserial,
+    "user_id" bigint,
+    "plan_id" bigint,
+    "purpose" text,
+    "model" text,
+    "prompt_tokens" bigint,
+    "completion_tokens" bigint,
+    "latency_ms" bigint,
+    "streamed" boolean,
+    "error" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_llm_usages_created_at" ON "llm_usages" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_llm_usages_purpose" ON "llm_usages" ("purpose");
+CREATE INDEX IF NOT EXISTS "idx_llm_usages_plan_id" ON "llm_usages" ("plan_id");
+CREATE INDEX IF NOT EXISTS "idx_llm_usages_user_id" ON "llm_usages" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "prompt_templates" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "name" text NOT NULL,
+    "version" bigint NOT NULL,
+    "body" text NOT NULL,
+    "active" boolean DEFAULT true,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_prompt_templates_name_version" ON "prompt_templates" ("name","version");
+
+CREATE TABLE IF NOT EXISTS "daily_content_translations" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "daily_content_id" bigint NOT NULL,
+    "language" varchar(64) NOT NULL,
+    "content" JSONB,
+    "exercises" JSONB,
+    "resources" JSONB,
+    "prompts" JSONB,
+    "source_updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_daily_content_translations_daily_content" FOREIGN KEY ("daily_content_id") REFERENCES "daily_contents"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_daily_content_translations_language" ON "daily_content_translations" ("daily_content_id","language");
+
+CREATE TABLE IF NOT EXISTS "placement_diagnostics" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "goal" text NOT NULL,
+    "language" varchar(64),
+    "questions" JSONB,
+    "answers" JSONB,
+    "stage" text,
+    "status" text,
+    "level" text,
+    "known_concepts" JSONB,
+    "score" decimal,
+    "prompts" JSONB,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_placement_diagnostics_user_id" ON "placement_diagnostics" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_placement_diagnostics_status" ON "placement_diagnostics" ("status");
+
+CREATE TABLE IF NOT EXISTS "flashcards" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "daily_content_id" bigint NOT NULL,
+    "source" varchar(32) NOT NULL,
+    "source_index" bigint NOT NULL,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "week_number" bigint,
+    "day_number" bigint,
+    "front" text,
+    "back" text,
+    "ease_factor" decimal NOT NULL DEFAULT 2.5,
+    "interval_days" bigint NOT NULL DEFAULT 0,
+    "repetitions" bigint NOT NULL DEFAULT 0,
+    "lapses" bigint NOT NULL DEFAULT 0,
+    "due_at" timestamptz,
+    "last_reviewed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_flashcards_daily_content" FOREIGN KEY ("daily_content_id") REFERENCES "daily_contents"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_flashcards_due" ON "flashcards" ("plan_id","user_id","due_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_flashcards_source" ON "flashcards" ("daily_content_id","source","source_index");
+
+CREATE TABLE IF NOT EXISTS "flashcard_reviews" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "flashcard_id" bigint NOT NULL,
+    "plan_id" bigint,
+    "user_id" bigint,
+    "quality" bigint,
+    "interval_days" bigint,
+    "ease_factor" decimal,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_flashcard_reviews_flashcard" FOREIGN KEY ("flashcard_id") REFERENCES "flashcards"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_flashcard_reviews_flashcard_id" ON "flashcard_reviews" ("flashcard_id");
+CREATE INDEX IF NOT EXISTS "idx_flashcard_reviews_plan" ON "flashcard_reviews" ("plan_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "tutor_threads" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "user_id" bigint,
+    "plan_id" bigint,
+    "week_number" bigint,
+    "day_number" bigint,
+    "title" text,
+    "summary" text,
+    "summarized_through_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_tutor_threads_day" ON "tutor_threads" ("user_id","plan_id","week_number","day_number");
+
+CREATE TABLE IF NOT EXISTS "tutor_messages" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "thread_id" bigint NOT NULL,
+    "role" varchar(16) NOT NULL,
+    "content" text NOT NULL,
+    "prompts" JSONB,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_tutor_threads_messages" FOREIGN KEY ("thread_id") REFERENCES "tutor_threads"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_tutor_messages_thread_id" ON "tutor_messages" ("thread_id");
@@ -0,0 +1,7 @@
+-- Answers are revealed for attempts made since the exercises were generated,
+-- which used to be approximated by updated_at.
+
+ALTER TABLE "daily_contents" ADD COLUMN IF NOT EXISTS "exercises_generated_at" timestamptz;
+
+UPDATE "daily_contents" SET "exercises_generated_at" = "updated_at"
+WHERE "exercises" IS NOT NULL AND "exercises_generated_at" IS NULL;
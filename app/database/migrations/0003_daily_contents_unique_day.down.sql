@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_daily_contents_day";
@@ -0,0 +1,13 @@
+-- A day has one lesson per learner. The stream endpoint and the generation
+-- job could both save one, so keep the earliest row, which is the one reads
+-- already returned, before enforcing it.
+
+DELETE FROM "daily_contents" d
+USING "daily_contents" earlier
+WHERE d."plan_id" = earlier."plan_id"
+  AND d."user_id" = earlier."user_id"
+  AND d."week_number" = earlier."week_number"
+  AND d."day_number" = earlier."day_number"
+  AND d."id" > earlier."id";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_daily_contents_day" ON "daily_contents" ("plan_id","user_id","week_number","day_number");
@@ -0,0 +1,7 @@
+-- The columns belong to the baseline schema and stay; only the indexes moved
+-- here from 0001 are dropped.
+DROP INDEX IF EXISTS "idx_users_role";
+DROP INDEX IF EXISTS "idx_learning_plan_structures_template_id";
+DROP INDEX IF EXISTS "idx_learning_plan_structures_shared";
+DROP INDEX IF EXISTS "idx_learning_plan_structures_placement_diagnostic_id";
+DROP INDEX IF EXISTS "idx_generated_weekly_contents_superseded_at";
@@ -0,0 +1,30 @@
+-- Databases created by AutoMigrate before the baseline already had these four
+-- tables, so 0001 kept them as they were. Add the columns the baseline
+-- defines since then, and the indexes on them, which 0001 could not create.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "tier" text DEFAULT 'free';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" varchar(16) DEFAULT 'learner';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "suspended_at" timestamptz DEFAULT null;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "suspension_reason" text DEFAULT null;
+CREATE INDEX IF NOT EXISTS "idx_users_role" ON "users" ("role");
+
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "shared" boolean DEFAULT false;
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "source_plan_id" bigint;
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "template_id" bigint;
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "language" varchar(64);
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "prompts" JSONB;
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "generated_based_on" JSONB;
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "placement_diagnostic_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_learning_plan_structures_template_id" ON "learning_plan_structures" ("template_id");
+CREATE INDEX IF NOT EXISTS "idx_learning_plan_structures_shared" ON "learning_plan_structures" ("shared");
+CREATE INDEX IF NOT EXISTS "idx_learning_plan_structures_placement_diagnostic_id" ON "learning_plan_structures" ("placement_diagnostic_id");
+
+ALTER TABLE "generated_weekly_contents" ADD COLUMN IF NOT EXISTS "version" bigint DEFAULT 1;
+ALTER TABLE "generated_weekly_contents" ADD COLUMN IF NOT EXISTS "superseded_at" timestamptz;
+ALTER TABLE "generated_weekly_contents" ADD COLUMN IF NOT EXISTS "language" varchar(64);
+ALTER TABLE "generated_weekly_contents" ADD COLUMN IF NOT EXISTS "prompts" JSONB;
+CREATE INDEX IF NOT EXISTS "idx_generated_weekly_contents_superseded_at" ON "generated_weekly_contents" ("superseded_at");
+
+ALTER TABLE "daily_contents" ADD COLUMN IF NOT EXISTS "prompts" JSONB;
+ALTER TABLE "daily_contents" ADD COLUMN IF NOT EXISTS "language" varchar(64);
+ALTER TABLE "daily_contents" ADD COLUMN IF NOT EXISTS "generated_based_on" JSONB;
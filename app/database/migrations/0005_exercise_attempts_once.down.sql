@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_exercise_attempt_once";
+ALTER TABLE "exercise_attempts" DROP COLUMN IF EXISTS "exercises_generated_at";
+ALTER TABLE "exercise_attempts" DROP COLUMN IF EXISTS "daily_content_id";
@@ -0,0 +1,7 @@
+-- An exercise is answered once per generation. Earlier attempts have no
+-- daily content or generation recorded and are left out of the index.
+
+ALTER TABLE "exercise_attempts" ADD COLUMN IF NOT EXISTS "daily_content_id" bigint;
+ALTER TABLE "exercise_attempts" ADD COLUMN IF NOT EXISTS "exercises_generated_at" timestamptz;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_exercise_attempt_once" ON "exercise_attempts" ("daily_content_id","exercises_generated_at","exercise_index");
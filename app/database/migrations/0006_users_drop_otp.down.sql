@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "otp" text, ADD COLUMN IF NOT EXISTS "otp_expires_at" timestamptz;
@@ -0,0 +1,3 @@
+-- Codes live in their own table since the baseline; databases created by
+-- AutoMigrate before it still carry the old columns on users.
+ALTER TABLE "users" DROP COLUMN IF EXISTS "otp", DROP COLUMN IF EXISTS "otp_expires_at";
@@ -0,0 +1 @@
+ALTER TABLE "daily_contents" DROP COLUMN IF EXISTS "flashcards_synced_at";
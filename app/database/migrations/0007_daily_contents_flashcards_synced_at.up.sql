@@ -0,0 +1,9 @@
+-- Days without flashcards used to be synced on every listing of due cards,
+-- including those that have no cards to make. Days that have cards were
+-- synced already.
+
+ALTER TABLE "daily_contents" ADD COLUMN IF NOT EXISTS "flashcards_synced_at" timestamptz;
+
+UPDATE "daily_contents" SET "flashcards_synced_at" = now()
+WHERE "flashcards_synced_at" IS NULL
+  AND EXISTS (SELECT 1 FROM "flashcards" WHERE "flashcards"."daily_content_id" = "daily_contents"."id");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_learning_plan_structures_generation_job_id";
+ALTER TABLE "learning_plan_structures" DROP COLUMN IF EXISTS "generation_job_id";
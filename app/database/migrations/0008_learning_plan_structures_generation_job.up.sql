@@ -0,0 +1,6 @@
+-- A plan job retried after saving its plan used to save another. Plans
+-- saved before record no job and are left out of the index.
+
+ALTER TABLE "learning_plan_structures" ADD COLUMN IF NOT EXISTS "generation_job_id" bigint;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_learning_plan_structures_generation_job_id" ON "learning_plan_structures" ("generation_job_id");
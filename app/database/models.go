@@ -0,0 +1,30 @@
+package database
+
+import "github.com/surahj/ai-mentor-backend/app/models"
+
+// Models lists every model stored in the database. The migrations must
+// create a column for each of their fields.
+var Models = []interface{}{
+	&models.User{},
+	&models.Session{},
+	&models.OneTimeCode{},
+	&models.RateLimitCounter{},
+	&models.LearningPlanStructure{},
+	&models.GeneratedWeeklyContent{},
+	&models.DailyContent{},
+	&models.DailyContentTranslation{},
+	&models.LessonProgress{},
+	&models.ExerciseAttempt{},
+	&models.ContentAdaptationFlag{},
+	&models.GenerationJob{},
+	&models.Category{},
+	&models.PlanTemplate{},
+	&models.PlanRating{},
+	&models.LLMUsage{},
+	&models.PromptTemplate{},
+	&models.PlacementDiagnostic{},
+	&models.Flashcard{},
+	&models.FlashcardReview{},
+	&models.TutorThread{},
+	&models.TutorMessage{},
+}
@@ -1,10 +1,12 @@
 package database
 
 import (
-	"fmt"
-	"os"
+	"context"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/surahj/ai-mentor-backend/app/configs"
 	"github.com/surahj/ai-mentor-backend/app/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -12,51 +14,96 @@ import (
 
 var dbInstance *gorm.DB
 
-func InitPostgres() (*gorm.DB, error) {
+// InitPostgres connects to the database, applies pending migrations unless
+// db.auto_migrate is off and, while there is no admin yet, promotes the
+// configured admin emails.
+func InitPostgres(config *configs.Config) (*gorm.DB, error) {
 	if dbInstance != nil {
 		return dbInstance, nil
 	}
 
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
+	db, err := Connect(config.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.DB.AutoMigrate {
+		migrator, err := NewMigrator(db)
+		if err != nil {
+			return nil, err
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range applied {
+			log.Printf("Applied migration %d_%s", m.Version, m.Name)
+		}
+	}
+
+	if err := bootstrapAdmins(db, config.AdminEmails); err != nil {
+		return nil, err
+	}
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s",
-		dbHost, dbPort, dbUser, dbPassword, dbName,
-	)
+	dbInstance = db
+	return dbInstance, nil
+}
 
+// Connect opens a connection to the database without migrating it.
+func Connect(config configs.DBConfig) (*gorm.DB, error) {
 	var db *gorm.DB
 	var err error
 	maxRetries := 3
 	for i := 0; i < maxRetries; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(config.DSN()), &gorm.Config{})
 		if err == nil {
 			break
 		}
 		time.Sleep(2 * time.Second)
 	}
-	if err != nil {
-		return nil, err
-	}
+	return db, err
+}
 
-	// Auto migrate models
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.LearningPlanStructure{},
-		&models.GeneratedWeeklyContent{},
-		&models.DailyContent{},
-		// &models.ContentAdaptationFlag{},
-	)
-	if err != nil {
-		return nil, err
-	}
+func GetDB() *gorm.DB {
+	return dbInstance
+}
 
+// SetDB replaces the instance returned by GetDB, for callers such as tests
+// that open their own database.
+func SetDB(db *gorm.DB) {
 	dbInstance = db
-	return dbInstance, nil
 }
 
-func GetDB() *gorm.DB {
-	return dbInstance
+// bootstrapAdmins gives the admin role to the users with the given emails
+// when no user has it yet, so a fresh deployment gets its first admins.
+// Afterwards roles are managed through the admin API and a listed email that
+// was demoted is not promoted again on the next start.
+func bootstrapAdmins(db *gorm.DB, adminEmails []string) error {
+	var emails []string
+	for _, email := range adminEmails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	var admins int64
+	if err := db.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&admins).Error; err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+	result := db.Model(&models.User{}).
+		Where("LOWER(email) IN ?", emails).
+		Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Bootstrapped %d admin(s) from admin_emails", result.RowsAffected)
+	}
+	return nil
 }
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBootstrapAdmins(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "users.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, email := range []string{"owner@example.com", "other@example.com"} {
+		if err := db.Create(&models.User{Email: email, Role: models.RoleLearner}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	role := func(email string) string {
+		var user models.User
+		if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user.Role
+	}
+
+	if err := bootstrapAdmins(db, []string{" Owner@Example.com "}); err != nil {
+		t.Fatal(err)
+	}
+	if got := role("owner@example.com"); got != models.RoleAdmin {
+		t.Fatalf("role after bootstrap = %q, want admin", got)
+	}
+
+	// once an admin exists, listed emails are no longer promoted
+	if err := db.Model(&models.User{}).Where("email = ?", "owner@example.com").Update("role", models.RoleLearner).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&models.User{}).Where("email = ?", "other@example.com").Update("role", models.RoleAdmin).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := bootstrapAdmins(db, []string{"owner@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := role("owner@example.com"); got != models.RoleLearner {
+		t.Errorf("demoted admin was promoted again: role = %q", got)
+	}
+}
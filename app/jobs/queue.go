@@ -0,0 +1,260 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Handler runs a job and returns the value stored as its result.
+type Handler func(ctx context.Context, job *models.GenerationJob) (interface{}, error)
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job fails immediately instead of being retried.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Queue is a Postgres backed job queue with a pool of workers. Workers claim
+// jobs with SELECT ... FOR UPDATE SKIP LOCKED, so several replicas can share
+// the same table.
+type Queue struct {
+	db           *gorm.DB
+	handlers     map[string]Handler
+	mu           sync.RWMutex
+	workers      int
+	pollInterval time.Duration
+	lease        time.Duration
+	backoff      time.Duration
+	maxAttempts  int
+	hostname     string
+}
+
+// NewQueue creates a queue with the worker count, polling, lease and retry
+// settings of config.
+func NewQueue(db *gorm.DB, config configs.JobsConfig) *Queue {
+	hostname, _ := os.Hostname()
+	return &Queue{
+		db:           db,
+		handlers:     map[string]Handler{},
+		workers:      config.Workers,
+		pollInterval: config.PollInterval,
+		lease:        config.Lease,
+		backoff:      config.RetryBackoff,
+		maxAttempts:  config.MaxAttempts,
+		hostname:     hostname,
+	}
+}
+
+// Register sets the handler for a job kind.
+func (q *Queue) Register(kind string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = h
+}
+
+// Enqueue adds a job unless one with the same dedup key is already queued or
+// running, in which case the existing job is returned with created set to false.
+func (q *Queue) Enqueue(userID int64, kind, dedupKey string, payload interface{}) (job *models.GenerationJob, created bool, err error) {
+	if existing, err := q.active(dedupKey); err != nil || existing != nil {
+		return existing, false, err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode job payload: %v", err)
+	}
+
+	job = &models.GenerationJob{
+		UserID:      userID,
+		Kind:        kind,
+		DedupKey:    dedupKey,
+		Payload:     datatypes.JSON(data),
+		Status:      models.JobStatusQueued,
+		MaxAttempts: q.maxAttempts,
+		RunAt:       time.Now(),
+	}
+
+	// the partial unique index on dedup_key rejects a concurrent duplicate
+	result := q.db.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		existing, err := q.active(dedupKey)
+		return existing, false, err
+	}
+
+	return job, true, nil
+}
+
+// Get returns a job by ID.
+func (q *Queue) Get(id int64) (*models.GenerationJob, error) {
+	var job models.GenerationJob
+	if err := q.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *Queue) active(dedupKey string) (*models.GenerationJob, error) {
+	var job models.GenerationJob
+	err := q.db.Where("dedup_key = ? AND status IN ?", dedupKey, []string{models.JobStatusQueued, models.JobStatusRunning}).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Start launches the worker pool. Workers stop when ctx is cancelled.
+func (q *Queue) Start(ctx context.Context) {
+	for i := 0; i < q.workers; i++ {
+		workerID := fmt.Sprintf("%s-%d-%d", q.hostname, os.Getpid(), i)
+		go q.work(ctx, workerID)
+	}
+	log.Printf("Started %d generation job workers", q.workers)
+}
+
+func (q *Queue) work(ctx context.Context, workerID string) {
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// drain the queue before waiting for the next tick
+		for {
+			job, err := q.claim(workerID)
+			if err != nil {
+				log.Printf("Job worker %s failed to claim a job: %v", workerID, err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			q.run(ctx, workerID, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim locks the next runnable job, including running jobs whose lease has
+// expired because their worker died.
+func (q *Queue) claim(workerID string) (*models.GenerationJob, error) {
+	var job models.GenerationJob
+	now := time.Now()
+
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)",
+				models.JobStatusQueued, now, models.JobStatusRunning, now.Add(-q.lease)).
+			Order("run_at").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = models.JobStatusRunning
+		job.Attempts++
+		job.LockedAt = &now
+		job.LockedBy = workerID
+		return tx.Save(&job).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// run executes a claimed job and records the outcome. The update only applies
+// while workerID still holds the lease, so a worker whose lease expired and
+// was reclaimed cannot overwrite the newer attempt.
+func (q *Queue) run(ctx context.Context, workerID string, job *models.GenerationJob) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Kind]
+	q.mu.RUnlock()
+
+	var result interface{}
+	var err error
+	if !ok {
+		err = Permanent(fmt.Errorf("no handler registered for job kind '%s'", job.Kind))
+	} else {
+		result, err = q.safeRun(ctx, handler, job)
+	}
+
+	now := time.Now()
+	job.LockedAt = nil
+	job.LockedBy = ""
+
+	if err == nil {
+		data, _ := json.Marshal(result)
+		job.Status = models.JobStatusSucceeded
+		job.Result = datatypes.JSON(data)
+		job.LastError = ""
+		job.CompletedAt = &now
+	} else {
+		job.LastError = err.Error()
+		var permanent permanentError
+		if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+			job.Status = models.JobStatusFailed
+			job.CompletedAt = &now
+		} else {
+			// exponential backoff: backoff, 2*backoff, 4*backoff, ...
+			job.Status = models.JobStatusQueued
+			job.RunAt = now.Add(q.backoff * time.Duration(1<<(job.Attempts-1)))
+		}
+		log.Printf("Job %d (%s) attempt %d failed: %v", job.ID, job.Kind, job.Attempts, err)
+	}
+
+	saved := q.db.Model(job).
+		Where("id = ? AND locked_by = ?", job.ID, workerID).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"result":       job.Result,
+			"last_error":   job.LastError,
+			"run_at":       job.RunAt,
+			"completed_at": job.CompletedAt,
+			"locked_at":    nil,
+			"locked_by":    "",
+		})
+	if saved.Error != nil {
+		log.Printf("Failed to save job %d: %v", job.ID, saved.Error)
+	} else if saved.RowsAffected == 0 {
+		log.Printf("Job %d lease was taken over by another worker, discarding attempt %d", job.ID, job.Attempts)
+	}
+}
+
+// safeRun converts a handler panic into a job failure.
+func (q *Queue) safeRun(ctx context.Context, handler Handler, job *models.GenerationJob) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
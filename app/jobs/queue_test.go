@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "jobs.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.GenerationJob{}); err != nil {
+		t.Fatal(err)
+	}
+	return NewQueue(db, configs.JobsConfig{Workers: 1, PollInterval: time.Second, Lease: time.Minute, RetryBackoff: time.Second, MaxAttempts: 3})
+}
+
+func TestRunRecordsResult(t *testing.T) {
+	q := newTestQueue(t)
+	q.Register("echo", func(_ context.Context, job *models.GenerationJob) (interface{}, error) {
+		return struct {
+			ID int64 `json:"id"`
+		}{ID: job.ID}, nil
+	})
+
+	if _, _, err := q.Enqueue(1, "echo", "echo:1", nil); err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.claim("worker-a")
+	if err != nil || job == nil {
+		t.Fatalf("claim = %v, %v", job, err)
+	}
+	q.run(context.Background(), "worker-a", job)
+
+	saved, err := q.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != models.JobStatusSucceeded || saved.LockedBy != "" || saved.CompletedAt == nil {
+		t.Errorf("job = %+v, want succeeded and unlocked", saved)
+	}
+	if string(saved.Result) != `{"id":1}` {
+		t.Errorf("result = %s", saved.Result)
+	}
+}
+
+func TestRunDiscardsAttemptAfterLeaseTakeover(t *testing.T) {
+	q := newTestQueue(t)
+	q.Register("echo", func(_ context.Context, _ *models.GenerationJob) (interface{}, error) {
+		return "stale", nil
+	})
+
+	if _, _, err := q.Enqueue(1, "echo", "echo:1", nil); err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.claim("worker-a")
+	if err != nil || job == nil {
+		t.Fatalf("claim = %v, %v", job, err)
+	}
+
+	// the lease expired and another worker reclaimed the job
+	if err := q.db.Model(&models.GenerationJob{}).Where("id = ?", job.ID).Update("locked_by", "worker-b").Error; err != nil {
+		t.Fatal(err)
+	}
+	q.run(context.Background(), "worker-a", job)
+
+	saved, err := q.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != models.JobStatusRunning || saved.LockedBy != "worker-b" || len(saved.Result) != 0 {
+		t.Errorf("job = %+v, want it left to worker-b", saved)
+	}
+}
@@ -0,0 +1,237 @@
+// Package learning decides whether a learner's plan, weeks and days are served
+// from storage or have to be generated, and queues the generation jobs.
+package learning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/repository"
+	"gorm.io/datatypes"
+)
+
+var (
+	// ErrNotFound is returned for plans and content that do not exist or
+	// belong to another user.
+	ErrNotFound = repository.ErrNotFound
+	// ErrStale is returned for a week an adaptation flag asks to regenerate.
+	ErrStale = errors.New("content is being adapted to the learner's progress")
+	// ErrWeekNotGenerated is returned for a day whose week has no content yet.
+	ErrWeekNotGenerated = errors.New("week content has not been generated")
+)
+
+// Enqueuer queues generation jobs, deduplicated by key. *jobs.Queue
+// implements it.
+type Enqueuer interface {
+	Enqueue(userID int64, kind, dedupKey string, payload interface{}) (*models.GenerationJob, bool, error)
+}
+
+// PlanJob is the payload of a plan structure generation job.
+type PlanJob struct {
+	Goal                  string `json:"goal"`
+	TotalWeeks            int    `json:"total_weeks"`
+	DailyCommitment       int    `json:"daily_commitment"`
+	PlacementDiagnosticID *int64 `json:"placement_diagnostic_id,omitempty"`
+}
+
+// WeekJob is the payload of a weekly content generation job.
+type WeekJob struct {
+	PlanID     int64 `json:"plan_id"`
+	WeekNumber int   `json:"week_number"`
+}
+
+// DayJob is the payload of a daily content generation job.
+type DayJob struct {
+	PlanID     int64 `json:"plan_id"`
+	WeekNumber int   `json:"week_number"`
+	DayNumber  int   `json:"day_number"`
+}
+
+// Service serves stored plans and content and queues the generation of
+// what is missing or stale. Generation quotas are left to the caller, which
+// checks them between a failed fetch and the call that generates.
+type Service struct {
+	plans repository.PlanRepository
+	weeks repository.WeeklyContentRepository
+	days  repository.DailyContentRepository
+	jobs  Enqueuer
+}
+
+// NewService creates a learning service.
+func NewService(repos repository.Repositories, jobs Enqueuer) *Service {
+	return &Service{plans: repos.Plans, weeks: repos.Weeks, days: repos.Days, jobs: jobs}
+}
+
+// Plan returns a plan structure only if it belongs to the user.
+func (s *Service) Plan(userID, planID int64) (*models.LearningPlanStructure, error) {
+	return s.plans.FindOwned(userID, planID)
+}
+
+// Plans lists the user's plan structures.
+func (s *Service) Plans(userID int64) ([]models.LearningPlanStructure, error) {
+	return s.plans.ListByUser(userID)
+}
+
+// PlanForGoal returns the plan the user already has for a goal.
+func (s *Service) PlanForGoal(userID int64, goal string) (*models.LearningPlanStructure, error) {
+	return s.plans.FindByGoal(userID, goal)
+}
+
+// SharedPlan returns the newest plan another user has shared for the goal
+// and number of weeks, to be cloned instead of generated.
+func (s *Service) SharedPlan(userID int64, goal string, totalWeeks int) (*models.LearningPlanStructure, error) {
+	return s.plans.FindShared(userID, goal, totalWeeks)
+}
+
+// GeneratePlan queues the generation of a plan structure. created is false
+// when the same plan is already being generated.
+func (s *Service) GeneratePlan(userID int64, req PlanJob) (job *models.GenerationJob, created bool, err error) {
+	return s.jobs.Enqueue(userID, models.JobKindPlanStructure, planDedupKey(userID, req), req)
+}
+
+// DeletePlan deletes a user's plan and everything generated or recorded for it.
+func (s *Service) DeletePlan(userID, planID int64) error {
+	return s.plans.Delete(userID, planID)
+}
+
+// CurrentWeek returns the current version of a week, whether or not it is
+// flagged for regeneration.
+func (s *Service) CurrentWeek(userID, planID int64, week int) (*models.GeneratedWeeklyContent, error) {
+	return s.weeks.FindCurrent(userID, planID, week)
+}
+
+// AdaptationFlag returns the open flag asking for a week to be regenerated,
+// or nil when there is none.
+func (s *Service) AdaptationFlag(userID, planID int64, week int) (*models.ContentAdaptationFlag, error) {
+	return s.weeks.OpenAdaptationFlag(userID, planID, week)
+}
+
+// FlagWeek records that a week has to be regenerated.
+func (s *Service) FlagWeek(flag *models.ContentAdaptationFlag) error {
+	return s.weeks.CreateAdaptationFlag(flag)
+}
+
+// Week returns the current version of a week. It returns ErrNotFound when the
+// week has not been generated and ErrStale, with the content, when it has to
+// be regenerated.
+func (s *Service) Week(userID, planID int64, week int) (*models.GeneratedWeeklyContent, error) {
+	content, err := s.weeks.FindCurrent(userID, planID, week)
+	if err != nil {
+		return nil, err
+	}
+	flag, err := s.weeks.OpenAdaptationFlag(userID, planID, week)
+	if err != nil {
+		return nil, err
+	}
+	if flag != nil {
+		return content, ErrStale
+	}
+	return content, nil
+}
+
+// GenerateWeek queues the generation of a week's content.
+func (s *Service) GenerateWeek(userID, planID int64, week int) (job *models.GenerationJob, created bool, err error) {
+	return s.jobs.Enqueue(userID, models.JobKindWeeklyContent, weekDedupKey(planID, week), WeekJob{PlanID: planID, WeekNumber: week})
+}
+
+// Day returns a day's content. It returns ErrWeekNotGenerated when the week
+// has no current version to build the day from, and ErrNotFound when the day
+// has not been generated.
+func (s *Service) Day(userID, planID int64, week, day int) (*models.DailyContent, error) {
+	if _, err := s.weeks.FindCurrent(userID, planID, week); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWeekNotGenerated
+		}
+		return nil, err
+	}
+	return s.days.Find(userID, planID, week, day)
+}
+
+// Lesson returns a day's content without checking its week.
+func (s *Service) Lesson(userID, planID int64, week, day int) (*models.DailyContent, error) {
+	return s.days.Find(userID, planID, week, day)
+}
+
+// SaveLesson stores a day's content.
+func (s *Service) SaveLesson(daily *models.DailyContent) error {
+	return s.days.Save(daily)
+}
+
+// DeleteLesson deletes a day's content so that it is generated again.
+func (s *Service) DeleteLesson(userID, planID int64, week, day int) error {
+	return s.days.Delete(userID, planID, week, day)
+}
+
+// GenerateDay queues the generation of a day's content.
+func (s *Service) GenerateDay(userID, planID int64, week, day int) (job *models.GenerationJob, created bool, err error) {
+	payload := DayJob{PlanID: planID, WeekNumber: week, DayNumber: day}
+	return s.jobs.Enqueue(userID, models.JobKindDailyContent, dayDedupKey(planID, week, day), payload)
+}
+
+// SubmittedExercises returns the indexes of the day's exercises the learner
+// has answered since they were last generated. Their answers are revealed.
+func (s *Service) SubmittedExercises(daily models.DailyContent) ([]int, error) {
+	return s.days.SubmittedExercises(daily)
+}
+
+// HideAnswers strips answers and explanations from exercises the learner has
+// not yet submitted. Attempts made before the exercises were last regenerated
+// refer to other questions and do not reveal anything. Exercises that cannot
+// be read are left out rather than returned with their answers.
+func (s *Service) HideAnswers(daily models.DailyContent) models.DailyContent {
+	if len(daily.Exercises) == 0 {
+		return daily
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(daily.Exercises, &items); err != nil {
+		log.Printf("Failed to read the exercises of daily content %d, leaving them out: %v", daily.ID, err)
+		daily.Exercises = nil
+		return daily
+	}
+
+	submitted, _ := s.days.SubmittedExercises(daily)
+	revealed := map[int]bool{}
+	for _, i := range submitted {
+		revealed[i] = true
+	}
+
+	for i, item := range items {
+		if !revealed[i] {
+			delete(item, "answer")
+			delete(item, "explanation")
+		}
+	}
+
+	hidden, err := json.Marshal(items)
+	if err != nil {
+		log.Printf("Failed to hide the answers of daily content %d, leaving the exercises out: %v", daily.ID, err)
+		daily.Exercises = nil
+		return daily
+	}
+	daily.Exercises = datatypes.JSON(hidden)
+	return daily
+}
+
+func planDedupKey(userID int64, req PlanJob) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(req.Goal))))
+	key := fmt.Sprintf("%s:%d:%s:%d:%d", models.JobKindPlanStructure, userID, hex.EncodeToString(sum[:8]), req.TotalWeeks, req.DailyCommitment)
+	if req.PlacementDiagnosticID != nil {
+		key += fmt.Sprintf(":%d", *req.PlacementDiagnosticID)
+	}
+	return key
+}
+
+func weekDedupKey(planID int64, week int) string {
+	return fmt.Sprintf("%s:%d:%d", models.JobKindWeeklyContent, planID, week)
+}
+
+func dayDedupKey(planID int64, week, day int) string {
+	return fmt.Sprintf("%s:%d:%d:%d", models.JobKindDailyContent, planID, week, day)
+}
@@ -0,0 +1,33 @@
+package learning
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/repository"
+	"gorm.io/datatypes"
+)
+
+func TestHideAnswers(t *testing.T) {
+	s := NewService(repository.NewMemory(), nil)
+
+	exercises, _ := json.Marshal([]models.Exercise{{Question: "What is JSX?", Answer: "JavaScript XML", Explanation: "JSX stands for JavaScript XML"}})
+	hidden := s.HideAnswers(models.DailyContent{Exercises: exercises})
+	var items []map[string]interface{}
+	if err := json.Unmarshal(hidden.Exercises, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0]["question"] != "What is JSX?" {
+		t.Fatalf("exercises = %s", hidden.Exercises)
+	}
+	if _, ok := items[0]["answer"]; ok {
+		t.Errorf("answer of an unsubmitted exercise is shown: %s", hidden.Exercises)
+	}
+
+	// exercises that cannot be read are left out rather than shown as stored
+	unreadable := s.HideAnswers(models.DailyContent{Exercises: datatypes.JSON(`{"question": "What is JSX?", "answer": "JavaScript XML"}`)})
+	if unreadable.Exercises != nil {
+		t.Errorf("unreadable exercises = %s, want none", unreadable.Exercises)
+	}
+}
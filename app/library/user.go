@@ -3,6 +3,7 @@ package library
 import (
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/surahj/ai-mentor-backend/app/database"
@@ -21,6 +22,16 @@ func GetUserByID(userID int64) (models.User, error) {
 	return user, nil
 }
 
+// SessionActive reports whether the session exists for the user and has
+// been neither revoked nor expired.
+func SessionActive(sessionID, userID int64) bool {
+	var count int64
+	err := database.GetDB().Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, time.Now()).
+		Count(&count).Error
+	return err == nil && count > 0
+}
+
 func GetUserIDFronContext(ctx echo.Context) (int64, error) {
 	userID := ctx.Get("user_id")
 	if userID == nil {
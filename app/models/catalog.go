@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Plan template levels
+const (
+	LevelBeginner     = "beginner"
+	LevelIntermediate = "intermediate"
+	LevelAdvanced     = "advanced"
+)
+
+// Category groups plan templates in the public catalog
+type Category struct {
+	BaseModel
+	Name        string `gorm:"uniqueIndex;not null" json:"name" example:"Web Development"`
+	Slug        string `gorm:"uniqueIndex;not null" json:"slug" example:"web-development"`
+	Description string `json:"description" example:"Frontend and backend web technologies"`
+}
+
+// PlanTemplate is a curated plan structure that any user can start. Only
+// published templates are visible in the public catalog.
+type PlanTemplate struct {
+	BaseModel
+	CategoryID      *int64         `gorm:"index" json:"category_id,omitempty" example:"1"`
+	Category        *Category      `json:"category,omitempty"`
+	Title           string         `gorm:"not null" json:"title" example:"React and TypeScript in 8 weeks"`
+	Goal            string         `gorm:"not null" json:"goal" example:"Learn React and TypeScript"`
+	Description     string         `json:"description" example:"From JSX basics to typed state management"`
+	Level           string         `gorm:"index" json:"level" example:"beginner"` // beginner, intermediate, advanced
+	TotalWeeks      int            `gorm:"index" json:"total_weeks" example:"8"`
+	DailyCommitment int            `json:"daily_commitment" example:"30"` // recommended minutes per day
+	Structure       datatypes.JSON `json:"structure" swaggertype:"object"`
+	SourcePlanID    *int64         `gorm:"index" json:"source_plan_id,omitempty" example:"3"`
+	Published       bool           `gorm:"default:false;index" json:"published" example:"true"`
+	PublishedAt     *time.Time     `json:"published_at,omitempty"`
+	StartCount      int            `gorm:"default:0" json:"start_count" example:"42"`
+}
+
+// PlanRating is a learner's rating of one of their plans. Ratings of clones
+// count towards the plan they were cloned from when curating templates.
+type PlanRating struct {
+	BaseModel
+	PlanID  int64  `gorm:"uniqueIndex:idx_plan_ratings_plan_user" json:"plan_id" example:"1"`
+	UserID  int64  `gorm:"uniqueIndex:idx_plan_ratings_plan_user" json:"user_id" example:"1"`
+	Rating  int    `json:"rating" example:"5"` // 1-5
+	Comment string `json:"comment,omitempty" example:"Well paced"`
+}
+
+// TemplateCandidate is a generated plan structure ranked by learner ratings
+type TemplateCandidate struct {
+	PlanID        int64   `json:"plan_id" example:"3"`
+	Goal          string  `json:"goal" example:"Learn React and TypeScript"`
+	TotalWeeks    int     `json:"total_weeks" example:"8"`
+	AverageRating float64 `json:"average_rating" example:"4.6"`
+	RatingCount   int     `json:"rating_count" example:"5"`
+}
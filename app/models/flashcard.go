@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Flashcard sources
+const (
+	FlashcardSourceKeyPoint = "key_point"
+	FlashcardSourceExercise = "exercise"
+)
+
+// Flashcard is a spaced repetition card derived from a key point or an
+// exercise of a day. Reviews are scheduled with SM-2: EaseFactor, IntervalDays
+// and Repetitions carry the algorithm's state and DueAt is the next review.
+type Flashcard struct {
+	BaseModel
+	DailyContentID int64         `gorm:"uniqueIndex:idx_flashcards_source;not null" json:"daily_content_id" example:"1"`
+	DailyContent   *DailyContent `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Source         string        `gorm:"uniqueIndex:idx_flashcards_source;size:32;not null" json:"source" example:"key_point"` // key_point, exercise
+	SourceIndex    int           `gorm:"uniqueIndex:idx_flashcards_source;not null" json:"source_index" example:"0"`
+	PlanID         int64         `gorm:"index:idx_flashcards_due" json:"plan_id" example:"1"`
+	UserID         int64         `gorm:"index:idx_flashcards_due" json:"user_id" example:"1"`
+	WeekNumber     int           `json:"week_number" example:"1"`
+	DayNumber      int           `json:"day_number" example:"1"`
+	Front          string        `json:"front" example:"JSX compiles to _____"`
+	Back           string        `json:"back" example:"JSX compiles to React.createElement"`
+	EaseFactor     float64       `gorm:"not null;default:2.5" json:"ease_factor" example:"2.5"`
+	IntervalDays   int           `gorm:"not null;default:0" json:"interval_days" example:"6"`
+	Repetitions    int           `gorm:"not null;default:0" json:"repetitions" example:"2"`
+	Lapses         int           `gorm:"not null;default:0" json:"lapses" example:"0"`
+	DueAt          time.Time     `gorm:"index:idx_flashcards_due" json:"due_at"`
+	LastReviewedAt *time.Time    `json:"last_reviewed_at,omitempty"`
+}
+
+// FlashcardReview records one review of a flashcard. Quality is the SM-2
+// grade from 0 (blackout) to 5 (perfect recall).
+type FlashcardReview struct {
+	BaseModel
+	FlashcardID  int64      `gorm:"index;not null" json:"flashcard_id" example:"1"`
+	Flashcard    *Flashcard `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	PlanID       int64      `gorm:"index:idx_flashcard_reviews_plan" json:"plan_id" example:"1"`
+	UserID       int64      `gorm:"index:idx_flashcard_reviews_plan" json:"user_id" example:"1"`
+	Quality      int        `json:"quality" example:"4"`
+	IntervalDays int        `json:"interval_days" example:"6"` // interval scheduled by the review
+	EaseFactor   float64    `json:"ease_factor" example:"2.5"`
+}
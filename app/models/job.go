@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Generation job kinds
+const (
+	JobKindPlanStructure = "plan_structure"
+	JobKindWeeklyContent = "weekly_content"
+	JobKindDailyContent  = "daily_content"
+)
+
+// Generation job statuses
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// GenerationJob is a unit of background AI generation work. At most one job per
+// DedupKey can be queued or running at a time.
+type GenerationJob struct {
+	BaseModel
+	UserID      int64          `gorm:"index" json:"user_id" example:"1"`
+	Kind        string         `json:"kind" example:"weekly_content"`
+	DedupKey    string         `gorm:"index:idx_generation_jobs_active_dedup,unique,where:status IN ('queued'\\,'running')" json:"-"`
+	Payload     datatypes.JSON `json:"payload" swaggertype:"object"`
+	Status      string         `gorm:"index" json:"status" example:"queued"` // queued, running, succeeded, failed
+	Attempts    int            `json:"attempts" example:"1"`
+	MaxAttempts int            `json:"max_attempts" example:"3"`
+	RunAt       time.Time      `gorm:"index" json:"run_at"`
+	LockedAt    *time.Time     `json:"-"`
+	LockedBy    string         `json:"-"`
+	LastError   string         `json:"last_error,omitempty"`
+	Result      datatypes.JSON `json:"result,omitempty" swaggertype:"object"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+}
@@ -9,10 +9,36 @@ import (
 // LearningPlanStructure represents the high-level structure of a learning plan
 type LearningPlanStructure struct {
 	BaseModel
-	UserID     int64          `json:"user_id" example:"1"`
+	UserID     int64          `gorm:"index" json:"user_id" example:"1"`
 	Goal       string         `json:"goal" example:"Learn React and TypeScript"`
 	TotalWeeks int            `json:"total_weeks" example:"8"`
 	Structure  datatypes.JSON `json:"structure" swaggertype:"object"` // JSONB: stores the complete structure
+	// Shared plans can be cloned into other users' accounts as templates
+	Shared bool `gorm:"default:false;index" json:"shared" example:"false"`
+	// SourcePlanID is the shared plan this one was cloned from, if any
+	SourcePlanID *int64 `json:"source_plan_id,omitempty" example:"3"`
+	// TemplateID is the catalog template this plan was started from, if any
+	TemplateID *int64 `gorm:"index" json:"template_id,omitempty" example:"2"`
+	// Language the plan and its content are written in
+	Language string `gorm:"size:64" json:"language,omitempty" example:"Spanish"`
+	// Prompts maps the name of each prompt template used to generate the plan to its version
+	Prompts datatypes.JSON `json:"prompts,omitempty" swaggertype:"object"`
+	// GeneratedBasedOn records the learner profile the plan was generated for
+	GeneratedBasedOn datatypes.JSON `json:"generated_based_on,omitempty" swaggertype:"object"`
+	// PlacementDiagnosticID is the placement quiz the plan was generated from, if any
+	PlacementDiagnosticID *int64 `gorm:"index" json:"placement_diagnostic_id,omitempty" example:"4"`
+	// GenerationJobID is the job that generated the plan, so a retried job
+	// finds the plan it saved instead of saving another
+	GenerationJobID *int64 `gorm:"uniqueIndex" json:"-"`
+}
+
+// SharedPlanTemplate is the public view of a shared plan structure. It omits
+// the owner so templates can be browsed without exposing other users.
+type SharedPlanTemplate struct {
+	ID         int64          `json:"id" example:"3"`
+	Goal       string         `json:"goal" example:"Learn React and TypeScript"`
+	TotalWeeks int            `json:"total_weeks" example:"8"`
+	Structure  datatypes.JSON `json:"structure" swaggertype:"object"`
 }
 
 // WeeklyTheme represents a week's learning theme and objectives
@@ -55,6 +81,14 @@ type Resource struct {
 	Duration    int    `json:"duration_minutes,omitempty" example:"60"`
 }
 
+// LessonContent represents the lesson generated for a single day
+type LessonContent struct {
+	Title       string   `json:"title" example:"Introduction to JSX"`
+	Summary     string   `json:"summary" example:"What JSX is and why React uses it"`
+	KeyPoints   []string `json:"key_points" example:"[\"JSX compiles to React.createElement\"]"`
+	Explanation string   `json:"explanation" example:"<p>JSX is a syntax extension...</p>"` // HTML
+}
+
 // WeeklyContent represents the structure of content generated for a week
 type WeeklyContent struct {
 	Theme           string           `json:"theme"`
@@ -67,7 +101,7 @@ type WeeklyContent struct {
 
 // CompleteLearningPlan represents the full structure
 type CompleteLearningPlan struct {
-	ID              int64               `gorm:"primaryKey" json:"id" example:"1"`
+	ID              int64               `gorm:"primaryKey" json:"id" example:"1" jsonschema:"optional"`
 	Goal            string              `json:"goal" example:"Learn React and TypeScript"`
 	TotalWeeks      int                 `json:"total_weeks" example:"8"`
 	DailyCommitment int                 `json:"daily_commitment_minutes" example:"30"`
@@ -76,32 +110,85 @@ type CompleteLearningPlan struct {
 	AdaptiveRules   map[string]string   `json:"adaptive_rules" example:"{\"difficulty\":\"auto\"}"` // rules for content adaptation
 }
 
-// GeneratedWeeklyContent represents the content stored in the database
+// GeneratedWeeklyContent represents the content stored in the database.
+// Regenerating a week keeps the previous rows with SupersededAt set; the
+// current version of a week is the row where SupersededAt is null.
 type GeneratedWeeklyContent struct {
 	ID               int64          `gorm:"primaryKey" json:"id" example:"1"`
 	PlanID           int64          `json:"plan_id" example:"1"` // FK to LearningPlanStructure
 	UserID           int64          `json:"user_id" example:"1"`
 	WeekNumber       int            `json:"week_number" example:"1"`
+	Version          int            `gorm:"default:1" json:"version" example:"1"`
 	ContentData      datatypes.JSON `json:"content_data" swaggertype:"object"`       // JSONB: stores int64
 	GeneratedBasedOn datatypes.JSON `json:"generated_based_on" swaggertype:"object"` // JSONB: snapshot of user progress
+	SupersededAt     *time.Time     `gorm:"index" json:"superseded_at,omitempty"`
+	Language         string         `gorm:"size:64" json:"language,omitempty" example:"Spanish"`
+	Prompts          datatypes.JSON `json:"prompts,omitempty" swaggertype:"object"` // JSONB: prompt template name -> version
 	CreatedAt        time.Time      `json:"created_at"`
 }
 
+// GenerationBasis is stored in GeneratedBasedOn and records what a plan, week
+// or day was generated from
+type GenerationBasis struct {
+	Progress   *ProgressSnapshot      `json:"progress,omitempty"`
+	Adaptation *ContentAdaptationFlag `json:"adaptation,omitempty"`
+	// Profile is the learner profile rendered into the prompt and
+	// ProfileAttributes names the attributes it contained
+	Profile           *LearnerProfile `json:"profile,omitempty"`
+	ProfileAttributes []string        `json:"profile_attributes"`
+}
+
+// Adaptation kinds
+const (
+	AdaptationRemedial    = "remedial"
+	AdaptationAccelerated = "accelerated"
+	AdaptationManual      = "manual"
+)
+
 // ContentAdaptationFlag represents flags for content regeneration
 type ContentAdaptationFlag struct {
-	PlanID            uint   `json:"plan_id" example:"1"`
-	WeekNumber        int    `json:"week_number" example:"1"`
-	NeedsRegeneration bool   `json:"needs_regeneration" example:"false"`
-	Reason            string `json:"reason" example:"User struggling with concepts"`
+	BaseModel
+	PlanID            int64      `gorm:"index" json:"plan_id" example:"1"`
+	UserID            int64      `gorm:"index" json:"user_id" example:"1"`
+	WeekNumber        int        `json:"week_number" example:"1"`
+	Kind              string     `json:"kind" example:"remedial"` // remedial, accelerated, manual
+	NeedsRegeneration bool       `json:"needs_regeneration" example:"false"`
+	Reason            string     `json:"reason" example:"User struggling with concepts"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
 }
 
 type DailyContent struct {
 	BaseModel
-	PlanID     int64          `json:"plan_id" example:"1"`
-	UserID     int64          `json:"user_id" example:"1"`
-	WeekNumber int            `json:"week_number" example:"1"`
-	DayNumber  int            `json:"day_number" example:"1"`
-	Content    datatypes.JSON `json:"content" swaggertype:"object"`   // The main lesson/content for the day
-	Exercises  datatypes.JSON `json:"exercises" swaggertype:"object"` // Exercises for the day
-	Resources  datatypes.JSON `json:"resources" swaggertype:"object"` // List of resource links
+	PlanID     int64          `gorm:"uniqueIndex:idx_daily_contents_day" json:"plan_id" example:"1"`
+	UserID     int64          `gorm:"uniqueIndex:idx_daily_contents_day" json:"user_id" example:"1"`
+	WeekNumber int            `gorm:"uniqueIndex:idx_daily_contents_day" json:"week_number" example:"1"`
+	DayNumber  int            `gorm:"uniqueIndex:idx_daily_contents_day" json:"day_number" example:"1"`
+	Content    datatypes.JSON `json:"content" swaggertype:"object"`           // The main lesson/content for the day
+	Exercises  datatypes.JSON `json:"exercises" swaggertype:"object"`         // Exercises for the day
+	Resources  datatypes.JSON `json:"resources" swaggertype:"object"`         // List of resource links
+	Prompts    datatypes.JSON `json:"prompts,omitempty" swaggertype:"object"` // Prompt template name -> version used for the content and exercises
+	Language   string         `gorm:"size:64" json:"language,omitempty" example:"Spanish"`
+	// GeneratedBasedOn records the progress and learner profile the day was generated from
+	GeneratedBasedOn datatypes.JSON `json:"generated_based_on,omitempty" swaggertype:"object"`
+	// ExercisesGeneratedAt is when the exercises were last generated. Attempts
+	// made before it answered other questions.
+	ExercisesGeneratedAt *time.Time `json:"exercises_generated_at,omitempty"`
+	// FlashcardsSyncedAt is when the day's flashcards were last synced. Days
+	// generated before flashcards existed have none and are synced on first use.
+	FlashcardsSyncedAt *time.Time `json:"-"`
+}
+
+// DailyContentTranslation is a day's lesson, exercises and resources
+// translated into another language. It is rebuilt when the source content
+// changes after SourceUpdatedAt.
+type DailyContentTranslation struct {
+	BaseModel
+	DailyContentID  int64          `gorm:"uniqueIndex:idx_daily_content_translations_language;not null" json:"daily_content_id" example:"1"`
+	DailyContent    *DailyContent  `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Language        string         `gorm:"uniqueIndex:idx_daily_content_translations_language;size:64;not null" json:"language" example:"Spanish"`
+	Content         datatypes.JSON `json:"content" swaggertype:"object"`
+	Exercises       datatypes.JSON `json:"exercises" swaggertype:"object"`
+	Resources       datatypes.JSON `json:"resources" swaggertype:"object"`
+	Prompts         datatypes.JSON `json:"prompts,omitempty" swaggertype:"object"`
+	SourceUpdatedAt time.Time      `json:"source_updated_at"`
 }
@@ -7,15 +7,6 @@ package models
 // 	"gorm.io/datatypes"
 // )
 
-// Only define Category struct once in the codebase. Remove this if already defined elsewhere.
-// type Category struct {
-// 	ID          uint   `gorm:"primaryKey"`
-// 	Name        string `gorm:"unique;not null"`
-// 	Description string
-// 	CreatedAt   time.Time
-// 	UpdatedAt   time.Time
-// }
-
 // type LearningPlanStructure struct {
 // 	ID         int64 `gorm:"primaryKey"`
 // 	UserID     int64 // nullable for generic plans
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// One-time code purposes. A code issued for one purpose is never accepted for another.
+const (
+	OTPPurposeVerification  = "verification"
+	OTPPurposePasswordReset = "password_reset"
+)
+
+// OneTimeCode is an emailed one-time code. Only a keyed hash of the code is
+// stored. A code is spent once ConsumedAt is set; too many wrong guesses lock
+// the user out of the purpose until LockedUntil.
+type OneTimeCode struct {
+	BaseModel
+	UserID      int64      `gorm:"index:idx_one_time_codes_user_purpose" json:"user_id"`
+	Purpose     string     `gorm:"index:idx_one_time_codes_user_purpose" json:"purpose"`
+	CodeHash    string     `gorm:"not null" json:"-"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	ConsumedAt  *time.Time `json:"consumed_at,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Placement levels, from least to most proficient. The middle three are also
+// the difficulties of placement questions.
+const (
+	PlacementLevelNovice       = "novice"
+	PlacementLevelBeginner     = "beginner"
+	PlacementLevelIntermediate = "intermediate"
+	PlacementLevelAdvanced     = "advanced"
+)
+
+// Placement diagnostic statuses
+const (
+	PlacementStatusInProgress = "in_progress"
+	PlacementStatusCompleted  = "completed"
+)
+
+// PlacementQuestion is a multiple choice question of a placement quiz
+type PlacementQuestion struct {
+	Question    string   `json:"question" example:"Which keyword declares a constant in Go?"`
+	Options     []string `json:"options" example:"[\"var\",\"const\",\"let\"]"`
+	Answer      string   `json:"answer,omitempty" example:"const"`
+	Explanation string   `json:"explanation,omitempty" example:"const declares a constant"`
+	Difficulty  string   `json:"difficulty" example:"beginner"` // beginner, intermediate, advanced
+	Concept     string   `json:"concept" example:"Constants"`
+}
+
+// PlacementAnswer is a learner's graded answer to a placement question
+type PlacementAnswer struct {
+	QuestionIndex int    `json:"question_index" example:"0"`
+	Answer        string `json:"answer" example:"const"`
+	Correct       bool   `json:"correct" example:"true"`
+}
+
+// PlacementDiagnostic is an adaptive placement quiz taken before a plan is
+// generated. Questions hold a pool for every difficulty; the quiz starts at
+// Stage and moves up or down depending on the answers. Once completed it
+// records the inferred level and the concepts the learner already knows, and
+// plans generated from it link back through PlacementDiagnosticID.
+type PlacementDiagnostic struct {
+	BaseModel
+	UserID        int64          `gorm:"index" json:"user_id" example:"1"`
+	Goal          string         `gorm:"not null" json:"goal" example:"Learn Go"`
+	Language      string         `gorm:"size:64" json:"language,omitempty" example:"Spanish"`
+	Questions     datatypes.JSON `json:"questions" swaggertype:"object"`
+	Answers       datatypes.JSON `json:"answers,omitempty" swaggertype:"object"`
+	Stage         string         `json:"stage,omitempty" example:"intermediate"` // difficulty being asked, empty once completed
+	Status        string         `gorm:"index" json:"status" example:"in_progress"`
+	Level         string         `json:"level,omitempty" example:"intermediate"`
+	KnownConcepts datatypes.JSON `json:"known_concepts,omitempty" swaggertype:"array,string"`
+	Score         *float64       `json:"score,omitempty" example:"0.75"`
+	Prompts       datatypes.JSON `json:"prompts,omitempty" swaggertype:"object"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+}
+
+// PlacementResult is the outcome of a placement diagnostic as used in
+// generation prompts
+type PlacementResult struct {
+	DiagnosticID  int64    `json:"diagnostic_id"`
+	Level         string   `json:"level"`
+	KnownConcepts []string `json:"known_concepts,omitempty"`
+}
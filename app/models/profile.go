@@ -0,0 +1,61 @@
+package models
+
+// Learner profile attributes, as recorded in GenerationBasis.ProfileAttributes
+const (
+	ProfileAge        = "age"
+	ProfileLevel      = "level"
+	ProfileBackground = "background"
+	ProfileInterests  = "interests"
+	ProfileCountry    = "country"
+	ProfileHistory    = "history"
+	ProfilePlacement  = "placement"
+)
+
+// LearnerProfile is the learner context injected into generation prompts.
+// Unset attributes are left out of the prompt.
+type LearnerProfile struct {
+	Age        *int            `json:"age,omitempty"`
+	Level      string          `json:"level,omitempty"`
+	Background string          `json:"background,omitempty"`
+	Interests  string          `json:"interests,omitempty"`
+	Country    string          `json:"country,omitempty"`
+	History    *LearnerHistory `json:"history,omitempty"`
+	// Placement is the result of the placement diagnostic taken for the goal
+	Placement *PlacementResult `json:"placement,omitempty"`
+}
+
+// LearnerHistory summarises a learner's other plans.
+type LearnerHistory struct {
+	OtherPlans       int      `json:"other_plans"`
+	PastGoals        []string `json:"past_goals,omitempty"`
+	LessonsCompleted int      `json:"lessons_completed"`
+	ExerciseAttempts int      `json:"exercise_attempts"`
+	ExerciseAccuracy *float64 `json:"exercise_accuracy,omitempty"`
+}
+
+// Attributes lists the profile attributes that are set, in prompt order.
+func (p LearnerProfile) Attributes() []string {
+	attributes := []string{}
+	if p.Age != nil {
+		attributes = append(attributes, ProfileAge)
+	}
+	if p.Level != "" {
+		attributes = append(attributes, ProfileLevel)
+	}
+	if p.Background != "" {
+		attributes = append(attributes, ProfileBackground)
+	}
+	if p.Interests != "" {
+		attributes = append(attributes, ProfileInterests)
+	}
+	if p.Country != "" {
+		attributes = append(attributes, ProfileCountry)
+	}
+	if p.History != nil {
+		attributes = append(attributes, ProfileHistory)
+	}
+	if p.Placement != nil {
+		attributes = append(attributes, ProfilePlacement)
+	}
+	return attributes
+}
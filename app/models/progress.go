@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+)
+
+// Lesson progress statuses
+const (
+	LessonStatusStarted   = "started"
+	LessonStatusCompleted = "completed"
+)
+
+// LessonProgress tracks a learner's progress through a single day of a plan
+type LessonProgress struct {
+	BaseModel
+	PlanID           int64      `gorm:"index:idx_lesson_progress_day,unique" json:"plan_id" example:"1"`
+	UserID           int64      `gorm:"index:idx_lesson_progress_day,unique" json:"user_id" example:"1"`
+	WeekNumber       int        `gorm:"index:idx_lesson_progress_day,unique" json:"week_number" example:"1"`
+	DayNumber        int        `gorm:"index:idx_lesson_progress_day,unique" json:"day_number" example:"1"`
+	Status           string     `json:"status" example:"started"` // started, completed
+	StartedAt        *time.Time `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	TimeSpentSeconds int        `json:"time_spent_seconds" example:"900"`
+	Confidence       *int       `json:"confidence" example:"4"` // self-rated, 1 (lost) to 5 (mastered)
+}
+
+// ExerciseAttempt records a learner's answer to one exercise of a day
+type ExerciseAttempt struct {
+	BaseModel
+	PlanID     int64 `gorm:"index:idx_exercise_attempt_day" json:"plan_id" example:"1"`
+	UserID     int64 `gorm:"index:idx_exercise_attempt_day" json:"user_id" example:"1"`
+	WeekNumber int   `gorm:"index:idx_exercise_attempt_day" json:"week_number" example:"1"`
+	DayNumber  int   `gorm:"index:idx_exercise_attempt_day" json:"day_number" example:"1"`
+	// DailyContentID and ExercisesGeneratedAt identify the exercises that were
+	// answered; each of them can be answered once per generation
+	DailyContentID       *int64     `gorm:"uniqueIndex:idx_exercise_attempt_once" json:"daily_content_id,omitempty" example:"1"`
+	ExercisesGeneratedAt *time.Time `gorm:"uniqueIndex:idx_exercise_attempt_once" json:"exercises_generated_at,omitempty"`
+	ExerciseIndex        int        `gorm:"uniqueIndex:idx_exercise_attempt_once" json:"exercise_index" example:"0"`
+	Answer               string     `json:"answer" example:"JavaScript XML"`
+	IsCorrect            bool       `json:"is_correct" example:"true"`
+	Score                float64    `json:"score" example:"1"` // 0..1
+	Feedback             string     `json:"feedback" example:"Correct!"`
+	GradingMode          string     `json:"grading_mode" example:"normalized"` // exact, normalized, llm
+}
+
+// DayPerformance summarises the exercise results of a single day
+type DayPerformance struct {
+	WeekNumber int     `json:"week_number"`
+	DayNumber  int     `json:"day_number"`
+	Attempts   int     `json:"attempts"`
+	Accuracy   float64 `json:"accuracy"`
+}
+
+// ProgressSnapshot is the aggregated view of a learner's progress in a plan.
+// It is fed to the generators and stored alongside generated content.
+type ProgressSnapshot struct {
+	PlanID                int64            `json:"plan_id"`
+	LessonsStarted        int              `json:"lessons_started"`
+	LessonsCompleted      int              `json:"lessons_completed"`
+	TotalTimeSpentMinutes int              `json:"total_time_spent_minutes"`
+	AverageConfidence     *float64         `json:"average_confidence,omitempty"`
+	ExerciseAttempts      int              `json:"exercise_attempts"`
+	ExerciseAccuracy      *float64         `json:"exercise_accuracy,omitempty"`
+	RecentWeekNumber      int              `json:"recent_week_number,omitempty"` // latest week with exercise attempts
+	RecentAccuracy        *float64         `json:"recent_accuracy,omitempty"`    // accuracy within RecentWeekNumber
+	LastWeekNumber        int              `json:"last_week_number"`
+	LastDayNumber         int              `json:"last_day_number"`
+	StrugglingDays        []DayPerformance `json:"struggling_days,omitempty"`
+	Flashcards            int              `json:"flashcards"`
+	FlashcardsDue         int              `json:"flashcards_due"`
+	FlashcardReviews      int              `json:"flashcard_reviews"`
+	FlashcardRecall       *float64         `json:"flashcard_recall,omitempty"` // share of reviews recalled (quality 3 or more)
+	FlashcardLapses       int              `json:"flashcard_lapses"`           // cards forgotten after having been learned
+	GeneratedAt           time.Time        `json:"generated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// PromptTemplate adds a version of a prompt template without a deploy. The
+// version must not be one of a bundled template file; a higher version than
+// the bundled ones becomes the one used for generation. Generated records
+// stamp the versions they used, so change a prompt by adding a version rather
+// than editing a row. The registry reloads the rows every minute.
+type PromptTemplate struct {
+	BaseModel
+	Name    string `gorm:"uniqueIndex:idx_prompt_templates_name_version;not null" json:"name" example:"daily_lesson"`
+	Version int    `gorm:"uniqueIndex:idx_prompt_templates_name_version;not null" json:"version" example:"2"`
+	Body    string `gorm:"type:text;not null" json:"body"`
+	// Inactive rows are ignored when the registry loads
+	Active bool `gorm:"default:true" json:"active" example:"true"`
+}
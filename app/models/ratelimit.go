@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// RateLimitCounter counts the requests or generations made under a key in one
+// fixed window. It backs the Postgres rate limit store.
+type RateLimitCounter struct {
+	Key         string    `gorm:"primaryKey"`
+	WindowStart time.Time `gorm:"primaryKey"`
+	Count       int       `gorm:"not null;default:0"`
+	ExpiresAt   time.Time `gorm:"index"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Session is a signed-in device. Access tokens carry the session ID and are
+// rejected once the session is revoked or expired. The refresh token is
+// rotated on every use and only its SHA-256 hash is stored.
+type Session struct {
+	BaseModel
+	UserID           int64  `gorm:"index" json:"user_id" example:"1"`
+	RefreshTokenHash string `gorm:"uniqueIndex;not null" json:"-"`
+	// PreviousTokenHash is the hash of the refresh token replaced by the last
+	// rotation. Presenting it again means the token was stolen.
+	PreviousTokenHash string     `gorm:"index" json:"-"`
+	UserAgent         string     `json:"user_agent" example:"Mozilla/5.0"`
+	IPAddress         string     `json:"ip_address" example:"203.0.113.7"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	RevokedAt         *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the session can still be used.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// AuthTokens is returned by every sign-in flow and by token refresh
+type AuthTokens struct {
+	Token            string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "gorm.io/datatypes"
+
+// TutorThread is a tutor conversation about one day of a plan. Turns older
+// than the recent context are folded into Summary; SummarizedThroughID is the
+// last message included in it.
+type TutorThread struct {
+	BaseModel
+	UserID              int64          `gorm:"index:idx_tutor_threads_day" json:"user_id" example:"1"`
+	PlanID              int64          `gorm:"index:idx_tutor_threads_day" json:"plan_id" example:"1"`
+	WeekNumber          int            `gorm:"index:idx_tutor_threads_day" json:"week_number" example:"1"`
+	DayNumber           int            `gorm:"index:idx_tutor_threads_day" json:"day_number" example:"1"`
+	Title               string         `json:"title" example:"Why does JSX need a build step?"`
+	Summary             string         `json:"summary,omitempty"`
+	SummarizedThroughID int64          `json:"-"`
+	Messages            []TutorMessage `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"messages,omitempty"`
+}
+
+// TutorMessage is one turn of a tutor conversation
+type TutorMessage struct {
+	BaseModel
+	ThreadID int64  `gorm:"index;not null" json:"thread_id" example:"1"`
+	Role     string `gorm:"size:16;not null" json:"role" example:"user"` // user, assistant
+	Content  string `gorm:"type:text;not null" json:"content" example:"Why does JSX need a build step?"`
+	// Prompts maps the prompt templates used to produce an assistant reply to their versions
+	Prompts datatypes.JSON `json:"prompts,omitempty" swaggertype:"object"`
+}
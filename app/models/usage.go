@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// LLMUsage is one entry in the token usage ledger: a single completion made
+// on behalf of a user, successful or not.
+type LLMUsage struct {
+	ID               int64     `gorm:"primaryKey" json:"id"`
+	UserID           *int64    `gorm:"index" json:"user_id"`
+	PlanID           *int64    `gorm:"index" json:"plan_id"`
+	Purpose          string    `gorm:"index" json:"purpose"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	LatencyMs        int64     `json:"latency_ms"`
+	Streamed         bool      `json:"streamed"`
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `gorm:"index" json:"created_at"`
+}
@@ -24,7 +24,37 @@ type User struct {
 	UpdatedAt         time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt         gorm.DeletedAt `gorm:"index"`
 	IsVerified        bool           `gorm:"default:false" json:"is_verified"`
-	OTP               *string        `json:"-"`
-	OTPExpiresAt      *time.Time     `json:"-"`
 	AuthProvider      string         `gorm:"default:'email'"` // 'email' or 'google'
+	Tier              string         `gorm:"default:'free'" json:"tier"`
+	Role              string         `gorm:"size:16;default:'learner';index" json:"role"`
+	SuspendedAt       *time.Time     `gorm:"default:null" json:"suspended_at,omitempty"`
+	SuspensionReason  *string        `gorm:"default:null" json:"suspension_reason,omitempty"`
+}
+
+// Suspended reports whether an admin has suspended the account.
+func (u User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// User tiers, which set the AI generation quotas
+const (
+	TierFree = "free"
+	TierPro  = "pro"
+)
+
+// User roles. Mentors can inspect users and plans through the admin API;
+// only admins can change them.
+const (
+	RoleLearner = "learner"
+	RoleMentor  = "mentor"
+	RoleAdmin   = "admin"
+)
+
+// ValidRole reports whether role is one of the user roles.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleLearner, RoleMentor, RoleAdmin:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,187 @@
+// Package otp issues and verifies emailed one-time codes.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const codeDigits = 6
+
+var (
+	// ErrInvalidCode is returned for a wrong code while attempts remain.
+	ErrInvalidCode = errors.New("invalid code")
+	// ErrExpired is returned when there is no live code for the purpose.
+	ErrExpired = errors.New("code has expired")
+)
+
+// CooldownError is returned when a code is requested again too soon.
+type CooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("a code was sent recently, try again in %d seconds", int(e.RetryAfter.Seconds()+0.5))
+}
+
+// LockedError is returned while the user is locked out after too many wrong guesses.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed attempts, try again after %s", e.Until.Format(time.RFC3339))
+}
+
+// Service manages one-time codes stored in the one_time_codes table.
+type Service struct {
+	db          *gorm.DB
+	secret      []byte
+	ttl         time.Duration
+	maxAttempts int
+	cooldown    time.Duration
+	lockout     time.Duration
+}
+
+// NewService creates a service with the given code lifetime, attempt limits
+// and hashing secret.
+func NewService(db *gorm.DB, config configs.OTPConfig) *Service {
+	return &Service{
+		db:          db,
+		secret:      []byte(config.Secret),
+		ttl:         config.TTL,
+		maxAttempts: config.MaxAttempts,
+		cooldown:    config.ResendCooldown,
+		lockout:     config.Lockout,
+	}
+}
+
+// TTL is how long an issued code stays valid.
+func (s *Service) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue creates a new code for the purpose and returns it in plain text for
+// emailing. Earlier unused codes for the purpose stop working.
+func (s *Service) Issue(userID int64, purpose string) (string, error) {
+	code, err := generateCode()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var last models.OneTimeCode
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND purpose = ?", userID, purpose).
+			Order("created_at DESC").First(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		if err == nil {
+			if last.LockedUntil != nil && now.Before(*last.LockedUntil) {
+				return &LockedError{Until: *last.LockedUntil}
+			}
+			if wait := last.CreatedAt.Add(s.cooldown).Sub(now); wait > 0 {
+				return &CooldownError{RetryAfter: wait}
+			}
+		}
+
+		if err := tx.Model(&models.OneTimeCode{}).
+			Where("user_id = ? AND purpose = ? AND consumed_at IS NULL", userID, purpose).
+			Update("consumed_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.OneTimeCode{
+			UserID:      userID,
+			Purpose:     purpose,
+			CodeHash:    s.hash(userID, purpose, code),
+			ExpiresAt:   now.Add(s.ttl),
+			MaxAttempts: s.maxAttempts,
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// Verify checks a code and consumes it on success. Every wrong guess counts
+// against the code; reaching the limit invalidates it and locks the purpose.
+func (s *Service) Verify(userID int64, purpose, code string) error {
+	// a wrong guess is reported after the transaction commits, since returning
+	// it from the transaction would roll back the attempt it records
+	var result error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var otp models.OneTimeCode
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND purpose = ?", userID, purpose).
+			Order("created_at DESC").First(&otp).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrExpired
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if otp.LockedUntil != nil && now.Before(*otp.LockedUntil) {
+			return &LockedError{Until: *otp.LockedUntil}
+		}
+		if otp.ConsumedAt != nil || now.After(otp.ExpiresAt) {
+			return ErrExpired
+		}
+
+		if hmac.Equal([]byte(otp.CodeHash), []byte(s.hash(userID, purpose, code))) {
+			otp.ConsumedAt = &now
+			return tx.Save(&otp).Error
+		}
+
+		otp.Attempts++
+		result = ErrInvalidCode
+		if otp.Attempts >= otp.MaxAttempts {
+			until := now.Add(s.lockout)
+			otp.ConsumedAt = &now
+			otp.LockedUntil = &until
+			result = &LockedError{Until: until}
+		}
+		return tx.Save(&otp).Error
+	})
+	if err != nil {
+		return err
+	}
+	return result
+}
+
+// hash binds the code to its user and purpose so a code can only be checked
+// against the purpose it was issued for.
+func (s *Service) hash(userID int64, purpose, code string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%d:%s:%s", userID, purpose, code)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
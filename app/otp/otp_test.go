@@ -0,0 +1,168 @@
+package otp
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const userID = 1
+
+func newTestService(t *testing.T, config configs.OTPConfig) *Service {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "otp.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.OneTimeCode{}); err != nil {
+		t.Fatal(err)
+	}
+	config.Secret = "otp-test-secret"
+	if config.TTL == 0 {
+		config.TTL = 10 * time.Minute
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 3
+	}
+	if config.Lockout == 0 {
+		config.Lockout = 15 * time.Minute
+	}
+	return NewService(db, config)
+}
+
+func issue(t *testing.T, s *Service) string {
+	t.Helper()
+	code, err := s.Issue(userID, models.OTPPurposeVerification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != codeDigits {
+		t.Fatalf("code = %q, want %d digits", code, codeDigits)
+	}
+	return code
+}
+
+// wrongCode returns a code that differs from code.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestVerifyConsumesCode(t *testing.T) {
+	s := newTestService(t, configs.OTPConfig{})
+	code := issue(t, s)
+
+	if err := s.Verify(userID, models.OTPPurposeVerification, code); err != nil {
+		t.Fatalf("Verify = %v", err)
+	}
+	if err := s.Verify(userID, models.OTPPurposeVerification, code); !errors.Is(err, ErrExpired) {
+		t.Fatalf("second Verify = %v, want ErrExpired", err)
+	}
+}
+
+func TestVerifyChecksPurposeAndExpiry(t *testing.T) {
+	s := newTestService(t, configs.OTPConfig{})
+	code := issue(t, s)
+	if err := s.Verify(userID, models.OTPPurposePasswordReset, code); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify for another purpose = %v, want ErrExpired", err)
+	}
+	if err := s.Verify(userID+1, models.OTPPurposeVerification, code); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify for another user = %v, want ErrExpired", err)
+	}
+
+	expired := newTestService(t, configs.OTPConfig{TTL: -time.Second})
+	code = issue(t, expired)
+	if err := expired.Verify(userID, models.OTPPurposeVerification, code); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify after the TTL = %v, want ErrExpired", err)
+	}
+}
+
+func TestVerifyLocksAfterMaxAttempts(t *testing.T) {
+	s := newTestService(t, configs.OTPConfig{MaxAttempts: 3})
+	code := issue(t, s)
+	wrong := wrongCode(code)
+
+	for i := 1; i < 3; i++ {
+		if err := s.Verify(userID, models.OTPPurposeVerification, wrong); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("wrong guess %d = %v, want ErrInvalidCode", i, err)
+		}
+	}
+
+	var locked *LockedError
+	if err := s.Verify(userID, models.OTPPurposeVerification, wrong); !errors.As(err, &locked) {
+		t.Fatalf("last wrong guess = %v, want LockedError", err)
+	}
+	if until := time.Until(locked.Until); until < 14*time.Minute || until > 15*time.Minute {
+		t.Errorf("locked for %v, want the 15 minute lockout", until)
+	}
+
+	// the right code no longer works, and no new one is issued while locked
+	if err := s.Verify(userID, models.OTPPurposeVerification, code); !errors.As(err, &locked) {
+		t.Errorf("Verify while locked = %v, want LockedError", err)
+	}
+	if _, err := s.Issue(userID, models.OTPPurposeVerification); !errors.As(err, &locked) {
+		t.Errorf("Issue while locked = %v, want LockedError", err)
+	}
+}
+
+func TestIssueAfterLockoutExpires(t *testing.T) {
+	s := newTestService(t, configs.OTPConfig{MaxAttempts: 1, Lockout: time.Millisecond})
+	code := issue(t, s)
+
+	var locked *LockedError
+	if err := s.Verify(userID, models.OTPPurposeVerification, wrongCode(code)); !errors.As(err, &locked) {
+		t.Fatalf("wrong guess = %v, want LockedError", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	code = issue(t, s)
+	if err := s.Verify(userID, models.OTPPurposeVerification, code); err != nil {
+		t.Fatalf("Verify after the lockout = %v", err)
+	}
+}
+
+func TestIssueCooldown(t *testing.T) {
+	s := newTestService(t, configs.OTPConfig{ResendCooldown: time.Minute})
+	issue(t, s)
+
+	_, err := s.Issue(userID, models.OTPPurposeVerification)
+	var cooldown *CooldownError
+	if !errors.As(err, &cooldown) {
+		t.Fatalf("second Issue = %v, want CooldownError", err)
+	}
+	if cooldown.RetryAfter <= 0 || cooldown.RetryAfter > time.Minute {
+		t.Errorf("retry after %v, want within the minute cooldown", cooldown.RetryAfter)
+	}
+
+	// the cooldown is per purpose
+	if _, err := s.Issue(userID, models.OTPPurposePasswordReset); err != nil {
+		t.Errorf("Issue for another purpose = %v", err)
+	}
+}
+
+func TestIssueReplacesEarlierCode(t *testing.T) {
+	s := newTestService(t, configs.OTPConfig{})
+	first := issue(t, s)
+	second := issue(t, s)
+	if first == second {
+		t.Skip("both codes are the same")
+	}
+
+	if err := s.Verify(userID, models.OTPPurposeVerification, first); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Verify with the replaced code = %v, want ErrInvalidCode", err)
+	}
+	if err := s.Verify(userID, models.OTPPurposeVerification, second); err != nil {
+		t.Errorf("Verify with the new code = %v", err)
+	}
+}
@@ -0,0 +1,385 @@
+// Package prompts holds the named, versioned text/template prompts sent to
+// the LLM. Templates are bundled from templates/<name>.v<version>.tmpl and
+// may be overridden or extended by rows in the prompt_templates table.
+//
+// A template's body is the user prompt; an optional {{define "system"}}
+// block supplies the system prompt. Partials in templates/_<name>.tmpl define
+// blocks that every template can use.
+package prompts
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Prompt names
+const (
+	PlanStructure  = "plan_structure"
+	WeeklyContent  = "weekly_content"
+	DailyLesson    = "daily_lesson"
+	DailyResources = "daily_resources"
+	Exercises      = "exercises"
+	ValidateGoal   = "validate_goal"
+	GradeAnswer    = "grade_answer"
+
+	TranslateDailyContent = "translate_daily_content"
+	PlacementQuiz         = "placement_quiz"
+	TutorChat             = "tutor_chat"
+	TutorSummary          = "tutor_summary"
+)
+
+// Template sources
+const (
+	SourceFile     = "file"
+	SourceDatabase = "database"
+)
+
+//go:embed templates/*.tmpl
+var bundled embed.FS
+
+var (
+	fileNamePattern    = regexp.MustCompile(`^([a-z0-9_]+)\.v([0-9]+)\.tmpl$`)
+	partialNamePattern = regexp.MustCompile(`^_([a-z0-9_]+)\.tmpl$`)
+)
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+	"percent": func(v interface{}) string {
+		switch f := v.(type) {
+		case float64:
+			return fmt.Sprintf("%.0f%%", f*100)
+		case *float64:
+			if f != nil {
+				return fmt.Sprintf("%.0f%%", *f*100)
+			}
+		}
+		return ""
+	},
+	// profileAttribute marks a learner profile attribute as written into the
+	// prompt. RenderVersion records the calls in Rendered.ProfileAttributes.
+	"profileAttribute": func(string) string { return "" },
+}
+
+// Template is one version of a named prompt.
+type Template struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Source  string `json:"source"`
+	Body    string `json:"body"`
+	tmpl    *template.Template
+}
+
+// Rendered is a prompt ready to send, with the template it came from.
+type Rendered struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	System  string `json:"system"`
+	User    string `json:"user"`
+	// ProfileAttributes names the learner profile attributes the prompt contains
+	ProfileAttributes []string `json:"profile_attributes,omitempty"`
+}
+
+// Stamp records the name and version of every prompt used to generate a record.
+type Stamp map[string]int
+
+// Add records a rendered prompt.
+func (s Stamp) Add(r Rendered) {
+	s[r.Name] = r.Version
+}
+
+// Merge adds the prompts of another stamp.
+func (s Stamp) Merge(other Stamp) {
+	for name, version := range other {
+		s[name] = version
+	}
+}
+
+// JSON is the stamp as stored on generated records.
+func (s Stamp) JSON() datatypes.JSON {
+	b, _ := json.Marshal(s)
+	return datatypes.JSON(b)
+}
+
+// Trace records the prompts used to generate a record and the learner
+// profile attributes they contained.
+type Trace struct {
+	Stamp             Stamp
+	ProfileAttributes []string
+}
+
+// Add records a rendered prompt.
+func (t *Trace) Add(r Rendered) {
+	if t.Stamp == nil {
+		t.Stamp = Stamp{}
+	}
+	t.Stamp.Add(r)
+	for _, attribute := range r.ProfileAttributes {
+		if !slices.Contains(t.ProfileAttributes, attribute) {
+			t.ProfileAttributes = append(t.ProfileAttributes, attribute)
+		}
+	}
+}
+
+// Summary lists the versions of a prompt and the one used for generation.
+type Summary struct {
+	Name          string     `json:"name"`
+	ActiveVersion int        `json:"active_version"`
+	Versions      []Template `json:"versions"`
+}
+
+// Registry is a concurrency safe set of prompt templates.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]map[int]*Template
+	// partials are parsed into every template, keyed by file name
+	partials map[string]string
+}
+
+// Default is the registry used by the generators, loaded from the bundled files.
+var Default = mustLoadBundled()
+
+func mustLoadBundled() *Registry {
+	r, err := NewRegistry()
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// NewRegistry creates a registry holding the bundled templates.
+func NewRegistry() (*Registry, error) {
+	r := &Registry{templates: map[string]map[int]*Template{}, partials: map[string]string{}}
+
+	files, err := fs.Glob(bundled, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	// partials first, so the templates using them parse
+	var versioned []string
+	for _, file := range files {
+		m := partialNamePattern.FindStringSubmatch(path.Base(file))
+		if m == nil {
+			versioned = append(versioned, file)
+			continue
+		}
+		body, err := bundled.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		r.partials[m[1]] = string(body)
+	}
+
+	for _, file := range versioned {
+		m := fileNamePattern.FindStringSubmatch(path.Base(file))
+		if m == nil {
+			return nil, fmt.Errorf("prompt template %s is not named <name>.v<version>.tmpl", file)
+		}
+		version, _ := strconv.Atoi(m[2])
+		body, err := bundled.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.Add(m[1], version, SourceFile, string(body)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Add parses and registers a template, replacing any with the same name and version.
+func (r *Registry) Add(name string, version int, source, body string) error {
+	if version < 1 {
+		return fmt.Errorf("prompt %s: version must be positive", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tmpl := template.New(name).Funcs(funcs).Option("missingkey=error")
+	for partial, partialBody := range r.partials {
+		if _, err := tmpl.New("_" + partial).Parse(partialBody); err != nil {
+			return fmt.Errorf("prompt partial %s: %w", partial, err)
+		}
+	}
+	if _, err := tmpl.Parse(body); err != nil {
+		return fmt.Errorf("prompt %s v%d: %w", name, version, err)
+	}
+
+	if r.templates[name] == nil {
+		r.templates[name] = map[int]*Template{}
+	}
+	r.templates[name][version] = &Template{Name: name, Version: version, Source: source, Body: body, tmpl: tmpl}
+	return nil
+}
+
+// LoadOverrides registers the active templates stored in the database. A
+// template that fails to parse, or that reuses the version of a bundled file,
+// is skipped and reported in the returned error so a bad row cannot take
+// generation down. Records only stamp the version of the prompts they were
+// generated with, so a version must always mean the same body.
+func (r *Registry) LoadOverrides(db *gorm.DB) error {
+	var rows []models.PromptTemplate
+	if err := db.Where("active = ?", true).Find(&rows).Error; err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, row := range rows {
+		if t, ok := r.Get(row.Name, row.Version); ok && t.Source == SourceFile {
+			errs = append(errs, fmt.Sprintf("prompt %s v%d is bundled, add the override as a new version", row.Name, row.Version))
+			continue
+		}
+		if err := r.Add(row.Name, row.Version, SourceDatabase, row.Body); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid prompt overrides: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Reload replaces the templates with the bundled ones and the active
+// overrides currently in the database, dropping overrides that were
+// deactivated or deleted. Errors are reported like LoadOverrides.
+func (r *Registry) Reload(db *gorm.DB) error {
+	fresh, err := NewRegistry()
+	if err != nil {
+		return err
+	}
+	loadErr := fresh.LoadOverrides(db)
+
+	r.mu.Lock()
+	r.templates = fresh.templates
+	r.mu.Unlock()
+	return loadErr
+}
+
+// StartReload reloads the templates every interval until ctx is cancelled,
+// so overrides changed in the database reach every replica without a restart.
+func (r *Registry) StartReload(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Reload(db); err != nil {
+					log.Printf("Failed to reload prompt overrides: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Get returns a version of a prompt, or the latest one when version is 0.
+func (r *Registry) Get(name string, version int) (*Template, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.templates[name]
+	if version == 0 {
+		for v := range versions {
+			if v > version {
+				version = v
+			}
+		}
+	}
+	t, ok := versions[version]
+	return t, ok
+}
+
+// List summarises every prompt, sorted by name.
+func (r *Registry) List() []Summary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summaries := make([]Summary, 0, len(r.templates))
+	for name, versions := range r.templates {
+		s := Summary{Name: name}
+		for v, t := range versions {
+			s.Versions = append(s.Versions, *t)
+			if v > s.ActiveVersion {
+				s.ActiveVersion = v
+			}
+		}
+		sort.Slice(s.Versions, func(i, j int) bool { return s.Versions[i].Version < s.Versions[j].Version })
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// Render renders the latest version of a prompt.
+func (r *Registry) Render(name string, data interface{}) (Rendered, error) {
+	return r.RenderVersion(name, 0, data)
+}
+
+// RenderVersion renders a specific version of a prompt, or the latest when version is 0.
+func (r *Registry) RenderVersion(name string, version int, data interface{}) (Rendered, error) {
+	t, ok := r.Get(name, version)
+	if !ok {
+		if version == 0 {
+			return Rendered{}, fmt.Errorf("unknown prompt %s", name)
+		}
+		return Rendered{}, fmt.Errorf("unknown prompt %s v%d", name, version)
+	}
+
+	// Record the profile attributes on a clone so concurrent renders of the
+	// same template do not share them.
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return Rendered{}, fmt.Errorf("render prompt %s v%d: %w", name, t.Version, err)
+	}
+	var attributes []string
+	tmpl.Funcs(template.FuncMap{"profileAttribute": func(attribute string) string {
+		if !slices.Contains(attributes, attribute) {
+			attributes = append(attributes, attribute)
+		}
+		return ""
+	}})
+
+	var user strings.Builder
+	if err := tmpl.Execute(&user, data); err != nil {
+		return Rendered{}, fmt.Errorf("render prompt %s v%d: %w", name, t.Version, err)
+	}
+
+	var system strings.Builder
+	if tmpl.Lookup("system") != nil {
+		if err := tmpl.ExecuteTemplate(&system, "system", data); err != nil {
+			return Rendered{}, fmt.Errorf("render prompt %s v%d system: %w", name, t.Version, err)
+		}
+	}
+
+	return Rendered{
+		Name:              t.Name,
+		Version:           t.Version,
+		System:            strings.TrimSpace(system.String()),
+		User:              strings.TrimSpace(user.String()),
+		ProfileAttributes: attributes,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package prompts
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T, rows ...models.PromptTemplate) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "prompts.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.PromptTemplate{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := db.Create(&row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestLoadOverridesRejectsBundledVersions(t *testing.T) {
+	bundledLatest, ok := Default.Get(ValidateGoal, 0)
+	if !ok {
+		t.Fatal("validate_goal is not bundled")
+	}
+	db := newTestDB(t,
+		models.PromptTemplate{Name: ValidateGoal, Version: bundledLatest.Version, Body: "edited", Active: true},
+		models.PromptTemplate{Name: ValidateGoal, Version: bundledLatest.Version + 1, Body: "Is {{.Goal}} a goal?", Active: true},
+	)
+
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.LoadOverrides(db)
+	if err == nil || !strings.Contains(err.Error(), "is bundled") {
+		t.Fatalf("LoadOverrides = %v, want the bundled version rejected", err)
+	}
+
+	kept, _ := r.Get(ValidateGoal, bundledLatest.Version)
+	if kept.Source != SourceFile || kept.Body != bundledLatest.Body {
+		t.Errorf("bundled v%d was replaced by %s row %q", kept.Version, kept.Source, kept.Body)
+	}
+	latest, _ := r.Get(ValidateGoal, 0)
+	if latest.Version != bundledLatest.Version+1 || latest.Source != SourceDatabase {
+		t.Errorf("latest = v%d from %s, want the database v%d", latest.Version, latest.Source, bundledLatest.Version+1)
+	}
+}
+
+func TestReloadDropsDeactivatedOverrides(t *testing.T) {
+	bundledLatest, _ := Default.Get(GradeAnswer, 0)
+	override := models.PromptTemplate{Name: GradeAnswer, Version: bundledLatest.Version + 1, Body: "Grade it.", Active: true}
+	db := newTestDB(t, override)
+
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(db); err != nil {
+		t.Fatal(err)
+	}
+	if latest, _ := r.Get(GradeAnswer, 0); latest.Version != override.Version {
+		t.Fatalf("latest = v%d, want the override v%d", latest.Version, override.Version)
+	}
+
+	if err := db.Model(&models.PromptTemplate{}).Where("name = ?", GradeAnswer).Update("active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(db); err != nil {
+		t.Fatal(err)
+	}
+	if latest, _ := r.Get(GradeAnswer, 0); latest.Version != bundledLatest.Version || latest.Source != SourceFile {
+		t.Errorf("latest = v%d from %s, want the bundled v%d", latest.Version, latest.Source, bundledLatest.Version)
+	}
+}
+
+func TestRenderRecordsProfileAttributes(t *testing.T) {
+	age := 30
+	data := struct {
+		Goal            string
+		TotalWeeks      int
+		DailyCommitment int
+		Language        string
+		Profile         models.LearnerProfile
+	}{
+		Goal:            "Learn Go",
+		TotalWeeks:      4,
+		DailyCommitment: 30,
+		Language:        "en",
+		Profile:         models.LearnerProfile{Age: &age, Interests: "chess"},
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		want    []string
+	}{
+		{name: "profile partial", version: 3, want: []string{models.ProfileAge, models.ProfileInterests}},
+		{name: "no profile partial", version: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Default.RenderVersion(PlanStructure, tt.version, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(rendered.ProfileAttributes, tt.want) {
+				t.Errorf("profile attributes = %v, want %v", rendered.ProfileAttributes, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceMergesProfileAttributes(t *testing.T) {
+	var trace Trace
+	trace.Add(Rendered{Name: DailyLesson, Version: 3, ProfileAttributes: []string{models.ProfileAge, models.ProfileLevel}})
+	trace.Add(Rendered{Name: DailyResources, Version: 3, ProfileAttributes: []string{models.ProfileLevel, models.ProfileCountry}})
+
+	if want := (Stamp{DailyLesson: 3, DailyResources: 3}); !maps.Equal(trace.Stamp, want) {
+		t.Errorf("stamp = %v, want %v", trace.Stamp, want)
+	}
+	if want := []string{models.ProfileAge, models.ProfileLevel, models.ProfileCountry}; !slices.Equal(trace.ProfileAttributes, want) {
+		t.Errorf("profile attributes = %v, want %v", trace.ProfileAttributes, want)
+	}
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/surahj/ai-mentor-backend/app/library"
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// Rule allows Limit requests per Window.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// KeyFunc returns the identity a request is counted against.
+type KeyFunc func(c echo.Context) string
+
+// KeyByIP counts requests per client IP.
+func KeyByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// KeyByUser counts requests per authenticated user, falling back to the
+// client IP. Handlers must be wrapped by auth.Authenticate first.
+func KeyByUser(c echo.Context) string {
+	if userID, err := library.GetUserIDFronContext(c); err == nil && userID != 0 {
+		return "user:" + strconv.FormatInt(userID, 10)
+	}
+	return KeyByIP(c)
+}
+
+// Limit returns middleware allowing rule.Limit requests per window for each
+// key. name separates the counters of different limits. Responses carry
+// X-RateLimit-* headers; rejected requests get 429 with Retry-After. If the
+// store fails the request is let through.
+func Limit(store Store, name string, rule Rule, key KeyFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			now := time.Now()
+			windowStart := now.Truncate(rule.Window)
+			resetAt := windowStart.Add(rule.Window)
+
+			count, err := store.Incr("rl:"+name+":"+key(c), windowStart, resetAt)
+			if err != nil {
+				log.Printf("Rate limit store error: %v", err)
+				return next(c)
+			}
+
+			remaining := rule.Limit - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(rule.Limit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			h.Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+			if count > rule.Limit {
+				h.Set("Retry-After", retryAfter(resetAt.Sub(now)))
+				return c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+					ErrorCode:    http.StatusTooManyRequests,
+					ErrorMessage: "Too many requests. Please try again later.",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func retryAfter(d time.Duration) string {
+	return strconv.Itoa(int(math.Max(1, math.Ceil(d.Seconds()))))
+}
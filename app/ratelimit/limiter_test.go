@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// failingStore is a Store whose counters are unavailable.
+type failingStore struct{}
+
+func (failingStore) Incr(string, time.Time, time.Time) (int, error) {
+	return 0, errors.New("store unavailable")
+}
+func (failingStore) Get(string, time.Time) (int, error) { return 0, errors.New("store unavailable") }
+func (failingStore) Decr(string, time.Time) error       { return errors.New("store unavailable") }
+
+func limitedServer(store Store, rule Rule) *echo.Echo {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, Limit(store, "test", rule, KeyByIP))
+	return e
+}
+
+func request(e *echo.Echo, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	// a client supplied header must not change the key
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimitFixedWindow(t *testing.T) {
+	e := limitedServer(NewMemoryStore(), Rule{Limit: 2, Window: time.Hour})
+
+	for i := 1; i <= 2; i++ {
+		rec := request(e, "192.0.2.1:1234")
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d", i, rec.Code)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != strconv.Itoa(2-i) {
+			t.Errorf("request %d: remaining = %q, want %d", i, got, 2-i)
+		}
+	}
+
+	rec := request(e, "192.0.2.1:5678")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over the limit: status = %d", rec.Code)
+	}
+	if rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("remaining = %q, want 0", rec.Header().Get("X-RateLimit-Remaining"))
+	}
+	retry, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retry < 1 || retry > 3600 {
+		t.Errorf("Retry-After = %q", rec.Header().Get("Retry-After"))
+	}
+	reset, _ := strconv.ParseInt(rec.Header().Get("X-RateLimit-Reset"), 10, 64)
+	if want := time.Now().Truncate(time.Hour).Add(time.Hour).Unix(); reset != want {
+		t.Errorf("reset = %d, want the end of the window %d", reset, want)
+	}
+
+	// other clients have their own counter
+	if rec := request(e, "192.0.2.2:1234"); rec.Code != http.StatusNoContent {
+		t.Errorf("another client: status = %d", rec.Code)
+	}
+}
+
+func TestLimitNextWindow(t *testing.T) {
+	window := 50 * time.Millisecond
+	e := limitedServer(NewMemoryStore(), Rule{Limit: 1, Window: window})
+
+	// start at the beginning of a window so both requests fall in it
+	time.Sleep(time.Until(time.Now().Truncate(window).Add(window)))
+	if rec := request(e, "192.0.2.1:1234"); rec.Code != http.StatusNoContent {
+		t.Fatalf("first request: status = %d", rec.Code)
+	}
+	if rec := request(e, "192.0.2.1:1234"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status = %d", rec.Code)
+	}
+
+	time.Sleep(window)
+	if rec := request(e, "192.0.2.1:1234"); rec.Code != http.StatusNoContent {
+		t.Errorf("next window: status = %d", rec.Code)
+	}
+}
+
+func TestLimitLetsRequestsThroughWhenStoreFails(t *testing.T) {
+	e := limitedServer(failingStore{}, Rule{Limit: 1, Window: time.Minute})
+	for i := 0; i < 3; i++ {
+		if rec := request(e, "192.0.2.1:1234"); rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d", i, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// QuotaLimits caps the AI generations a tier may run per calendar day and
+// month (UTC). Zero means unlimited.
+type QuotaLimits struct {
+	Daily   int `json:"daily"`
+	Monthly int `json:"monthly"`
+}
+
+// defaultQuotas apply unless the quotas setting overrides them.
+var defaultQuotas = map[string]QuotaLimits{
+	models.TierFree: {Daily: 20, Monthly: 300},
+	models.TierPro:  {Daily: 200, Monthly: 5000},
+}
+
+// QuotaStatus is a user's generation usage in the current day and month.
+type QuotaStatus struct {
+	Tier           string    `json:"tier"`
+	DailyLimit     int       `json:"daily_limit"`
+	DailyUsed      int       `json:"daily_used"`
+	DailyResetAt   time.Time `json:"daily_reset_at"`
+	MonthlyLimit   int       `json:"monthly_limit"`
+	MonthlyUsed    int       `json:"monthly_used"`
+	MonthlyResetAt time.Time `json:"monthly_reset_at"`
+}
+
+func remaining(limit, used int) int {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+// Exceeded reports whether another generation would go over a limit.
+func (s QuotaStatus) Exceeded() bool {
+	return (s.DailyLimit > 0 && s.DailyUsed >= s.DailyLimit) ||
+		(s.MonthlyLimit > 0 && s.MonthlyUsed >= s.MonthlyLimit)
+}
+
+// RetryAfter is how long until the exhausted limit resets.
+func (s QuotaStatus) RetryAfter(now time.Time) time.Duration {
+	if s.MonthlyLimit > 0 && s.MonthlyUsed >= s.MonthlyLimit {
+		return s.MonthlyResetAt.Sub(now)
+	}
+	return s.DailyResetAt.Sub(now)
+}
+
+// SetHeaders exposes the remaining quota on a response.
+func (s QuotaStatus) SetHeaders(h http.Header) {
+	if s.DailyLimit > 0 {
+		h.Set("X-Quota-Daily-Limit", strconv.Itoa(s.DailyLimit))
+		h.Set("X-Quota-Daily-Remaining", strconv.Itoa(remaining(s.DailyLimit, s.DailyUsed)))
+		h.Set("X-Quota-Daily-Reset", strconv.FormatInt(s.DailyResetAt.Unix(), 10))
+	}
+	if s.MonthlyLimit > 0 {
+		h.Set("X-Quota-Monthly-Limit", strconv.Itoa(s.MonthlyLimit))
+		h.Set("X-Quota-Monthly-Remaining", strconv.Itoa(remaining(s.MonthlyLimit, s.MonthlyUsed)))
+		h.Set("X-Quota-Monthly-Reset", strconv.FormatInt(s.MonthlyResetAt.Unix(), 10))
+	}
+}
+
+// SetRetryAfter sets the Retry-After header for a rejected generation.
+func (s QuotaStatus) SetRetryAfter(h http.Header) {
+	h.Set("Retry-After", retryAfter(s.RetryAfter(time.Now())))
+}
+
+// Quota tracks AI generations per user against the limits of their tier.
+type Quota struct {
+	store Store
+	tiers map[string]QuotaLimits
+}
+
+// NewQuota creates a quota using store. overrides replaces the limits of
+// the tiers it names, leaving the defaults of the others.
+func NewQuota(store Store, overrides map[string]configs.QuotaLimits) *Quota {
+	tiers := map[string]QuotaLimits{}
+	for tier, limits := range defaultQuotas {
+		tiers[tier] = limits
+	}
+	for tier, limits := range overrides {
+		tiers[tier] = QuotaLimits(limits)
+	}
+	return &Quota{store: store, tiers: tiers}
+}
+
+func (q *Quota) limits(tier string) QuotaLimits {
+	if limits, ok := q.tiers[tier]; ok {
+		return limits
+	}
+	return q.tiers[models.TierFree]
+}
+
+func quotaWindows(now time.Time) (day, nextDay, month, nextMonth time.Time) {
+	now = now.UTC()
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return day, day.AddDate(0, 0, 1), month, month.AddDate(0, 1, 0)
+}
+
+func quotaKey(period string, userID int64) string {
+	return fmt.Sprintf("quota:%s:%d", period, userID)
+}
+
+// Check returns the user's current usage without consuming any quota.
+func (q *Quota) Check(userID int64, tier string) (QuotaStatus, error) {
+	limits := q.limits(tier)
+	day, nextDay, month, nextMonth := quotaWindows(time.Now())
+
+	daily, err := q.store.Get(quotaKey("daily", userID), day)
+	if err != nil {
+		return QuotaStatus{}, err
+	}
+	monthly, err := q.store.Get(quotaKey("monthly", userID), month)
+	if err != nil {
+		return QuotaStatus{}, err
+	}
+
+	return QuotaStatus{
+		Tier:           tier,
+		DailyLimit:     limits.Daily,
+		DailyUsed:      daily,
+		DailyResetAt:   nextDay,
+		MonthlyLimit:   limits.Monthly,
+		MonthlyUsed:    monthly,
+		MonthlyResetAt: nextMonth,
+	}, nil
+}
+
+// Reserve consumes one generation unless that would go over a limit, in
+// which case nothing is consumed and false is returned with the current usage.
+// Consuming before generating stops concurrent requests that each passed a
+// check from all going through; Refund gives the generation back if it is
+// not used.
+func (q *Quota) Reserve(userID int64, tier string) (QuotaStatus, bool, error) {
+	limits := q.limits(tier)
+	day, nextDay, month, nextMonth := quotaWindows(time.Now())
+
+	daily, err := q.store.Incr(quotaKey("daily", userID), day, nextDay)
+	if err != nil {
+		return QuotaStatus{}, false, err
+	}
+	monthly, err := q.store.Incr(quotaKey("monthly", userID), month, nextMonth)
+	if err != nil {
+		_ = q.store.Decr(quotaKey("daily", userID), day)
+		return QuotaStatus{}, false, err
+	}
+
+	status := QuotaStatus{
+		Tier:           tier,
+		DailyLimit:     limits.Daily,
+		DailyUsed:      daily,
+		DailyResetAt:   nextDay,
+		MonthlyLimit:   limits.Monthly,
+		MonthlyUsed:    monthly,
+		MonthlyResetAt: nextMonth,
+	}
+	if (limits.Daily > 0 && daily > limits.Daily) || (limits.Monthly > 0 && monthly > limits.Monthly) {
+		if err := q.Refund(userID, status); err != nil {
+			return QuotaStatus{}, false, err
+		}
+		status.DailyUsed--
+		status.MonthlyUsed--
+		return status, false, nil
+	}
+	return status, true, nil
+}
+
+// Refund gives back a generation reserved with the returned status. It is
+// taken from the windows the reservation counted in, even if they have
+// since ended.
+func (q *Quota) Refund(userID int64, status QuotaStatus) error {
+	if err := q.store.Decr(quotaKey("daily", userID), status.DailyResetAt.AddDate(0, 0, -1)); err != nil {
+		return err
+	}
+	return q.store.Decr(quotaKey("monthly", userID), status.MonthlyResetAt.AddDate(0, -1, 0))
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stores returns each Store implementation, the database one on SQLite.
+func stores(t *testing.T) map[string]Store {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "ratelimit.db")+"?_busy_timeout=5000"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.RateLimitCounter{}); err != nil {
+		t.Fatal(err)
+	}
+	return map[string]Store{"memory": NewMemoryStore(), "postgres": NewPostgresStore(db)}
+}
+
+func TestStoreCounters(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			window := time.Now().Truncate(time.Minute)
+			expires := window.Add(time.Minute)
+
+			for want := 1; want <= 3; want++ {
+				if got, err := store.Incr("k", window, expires); err != nil || got != want {
+					t.Fatalf("Incr = %d, %v, want %d", got, err, want)
+				}
+			}
+			if err := store.Decr("k", window); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := store.Get("k", window); err != nil || got != 2 {
+				t.Errorf("Get = %d, %v, want 2", got, err)
+			}
+
+			// windows and keys are counted apart, and counters never go negative
+			if got, _ := store.Get("k", window.Add(-time.Minute)); got != 0 {
+				t.Errorf("previous window = %d, want 0", got)
+			}
+			if err := store.Decr("other", window); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := store.Incr("other", window, expires); got != 1 {
+				t.Errorf("other key = %d, want 1", got)
+			}
+
+			// expired counters start over
+			past := window.Add(-time.Hour)
+			if _, err := store.Incr("old", past, past.Add(time.Minute)); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := store.Get("old", past); got != 0 {
+				t.Errorf("expired counter = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestQuotaReserve(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			quota := NewQuota(store, map[string]configs.QuotaLimits{models.TierFree: {Daily: 2, Monthly: 10}})
+
+			for i := 1; i <= 2; i++ {
+				status, ok, err := quota.Reserve(1, models.TierFree)
+				if err != nil || !ok {
+					t.Fatalf("reservation %d: ok = %v, err = %v", i, ok, err)
+				}
+				if status.DailyUsed != i || status.MonthlyUsed != i {
+					t.Errorf("reservation %d: used %d today, %d this month", i, status.DailyUsed, status.MonthlyUsed)
+				}
+			}
+
+			status, ok, err := quota.Reserve(1, models.TierFree)
+			if err != nil || ok {
+				t.Fatalf("over the daily limit: ok = %v, err = %v", ok, err)
+			}
+			if !status.Exceeded() || status.DailyUsed != 2 {
+				t.Errorf("status = %+v, want the daily limit reached", status)
+			}
+			if checked, _ := quota.Check(1, models.TierFree); checked.DailyUsed != 2 || checked.MonthlyUsed != 2 {
+				t.Errorf("a refused reservation was consumed: %+v", checked)
+			}
+
+			// a refund makes room for another generation
+			if err := quota.Refund(1, status); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok, _ := quota.Reserve(1, models.TierFree); !ok {
+				t.Error("reservation after a refund was refused")
+			}
+
+			// tiers without a limit are not capped
+			pro := NewQuota(store, map[string]configs.QuotaLimits{models.TierPro: {}})
+			for i := 0; i < 5; i++ {
+				if _, ok, err := pro.Reserve(2, models.TierPro); err != nil || !ok {
+					t.Fatalf("unlimited reservation %d: ok = %v, err = %v", i, ok, err)
+				}
+			}
+		})
+	}
+}
+
+func TestQuotaReserveConcurrently(t *testing.T) {
+	quota := NewQuota(NewMemoryStore(), map[string]configs.QuotaLimits{models.TierFree: {Daily: 5}})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok, err := quota.Reserve(1, models.TierFree); err == nil && ok {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if granted != 5 {
+		t.Errorf("granted %d generations, want the daily limit of 5", granted)
+	}
+}
@@ -0,0 +1,168 @@
+// Package ratelimit provides fixed-window request limits and per-user
+// generation quotas backed by an in-memory or Postgres counter store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store keeps counters per key and window. A counter is discarded once its
+// expiry has passed.
+type Store interface {
+	// Incr adds one to the counter and returns the new value.
+	Incr(key string, windowStart, expiresAt time.Time) (int, error)
+	// Get returns the counter value, zero when it does not exist.
+	Get(key string, windowStart time.Time) (int, error)
+	// Decr takes one from the counter, never going below zero.
+	Decr(key string, windowStart time.Time) error
+}
+
+// NewStore creates the store selected by config.Store: "memory" (the
+// default) or "postgres", which shares counters between replicas.
+func NewStore(db *gorm.DB, config configs.RateLimitConfig) (Store, error) {
+	switch v := config.Store; v {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store '%s'", v)
+	}
+}
+
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store local to the process.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+	calls    int
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: map[string]*memoryCounter{}}
+}
+
+func memoryKey(key string, windowStart time.Time) string {
+	return fmt.Sprintf("%s@%d", key, windowStart.Unix())
+}
+
+// Incr implements Store.
+func (s *MemoryStore) Incr(key string, windowStart, expiresAt time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	// sweep expired counters now and then so the map does not grow forever
+	s.calls++
+	if s.calls%1000 == 0 {
+		for k, c := range s.counters {
+			if now.After(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+	}
+
+	k := memoryKey(key, windowStart)
+	c, ok := s.counters[k]
+	if !ok || now.After(c.expiresAt) {
+		c = &memoryCounter{expiresAt: expiresAt}
+		s.counters[k] = c
+	}
+	c.count++
+	return c.count, nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(key string, windowStart time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[memoryKey(key, windowStart)]
+	if !ok || time.Now().After(c.expiresAt) {
+		return 0, nil
+	}
+	return c.count, nil
+}
+
+// Decr implements Store.
+func (s *MemoryStore) Decr(key string, windowStart time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.counters[memoryKey(key, windowStart)]; ok && c.count > 0 {
+		c.count--
+	}
+	return nil
+}
+
+// PostgresStore is a Store in the rate_limit_counters table.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a store using db.
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Incr implements Store with a single upsert so concurrent requests count correctly.
+func (s *PostgresStore) Incr(key string, windowStart, expiresAt time.Time) (int, error) {
+	counter := models.RateLimitCounter{Key: key, WindowStart: windowStart, Count: 1, ExpiresAt: expiresAt}
+	err := s.db.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}, {Name: "window_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("rate_limit_counters.count + 1")}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "count"}}},
+	).Create(&counter).Error
+	if err != nil {
+		return 0, err
+	}
+	return counter.Count, nil
+}
+
+// Get implements Store.
+func (s *PostgresStore) Get(key string, windowStart time.Time) (int, error) {
+	var counter models.RateLimitCounter
+	err := s.db.Where("key = ? AND window_start = ? AND expires_at > ?", key, windowStart, time.Now()).Limit(1).Find(&counter).Error
+	return counter.Count, err
+}
+
+// Decr implements Store.
+func (s *PostgresStore) Decr(key string, windowStart time.Time) error {
+	return s.db.Model(&models.RateLimitCounter{}).
+		Where("key = ? AND window_start = ? AND count > 0", key, windowStart).
+		Update("count", gorm.Expr("count - 1")).Error
+}
+
+// StartCleanup deletes expired counters every interval until ctx is cancelled.
+func (s *PostgresStore) StartCleanup(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.db.Where("expires_at < ?", time.Now()).Delete(&models.RateLimitCounter{}).Error; err != nil {
+					log.Printf("Failed to delete expired rate limit counters: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// NewMemory returns empty repositories local to the process, for tests.
+// Deleting a plan also deletes its weeks and days from the returned
+// repositories.
+func NewMemory() Repositories {
+	weeks := NewMemoryWeeks()
+	days := NewMemoryDays()
+	return Repositories{
+		Users: NewMemoryUsers(),
+		Plans: &MemoryPlans{plans: map[int64]models.LearningPlanStructure{}, weeks: weeks, days: days},
+		Weeks: weeks,
+		Days:  days,
+	}
+}
+
+// MemoryUsers is a UserRepository local to the process.
+type MemoryUsers struct {
+	mu     sync.Mutex
+	users  map[int64]models.User
+	nextID int64
+}
+
+// NewMemoryUsers creates an empty in-memory user repository.
+func NewMemoryUsers() *MemoryUsers {
+	return &MemoryUsers{users: map[int64]models.User{}}
+}
+
+// FindByID implements UserRepository.
+func (r *MemoryUsers) FindByID(id int64) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+// FindByEmail implements UserRepository.
+func (r *MemoryUsers) FindByEmail(email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Create implements UserRepository.
+func (r *MemoryUsers) Create(user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == 0 {
+		r.nextID++
+		user.ID = r.nextID
+	} else if user.ID > r.nextID {
+		r.nextID = user.ID
+	}
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	r.users[user.ID] = *user
+	return nil
+}
+
+// Save implements UserRepository.
+func (r *MemoryUsers) Save(user *models.User) error {
+	if user.ID == 0 {
+		return r.Create(user)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+	r.users[user.ID] = *user
+	return nil
+}
+
+// MemoryPlans is a PlanRepository local to the process.
+type MemoryPlans struct {
+	mu     sync.Mutex
+	plans  map[int64]models.LearningPlanStructure
+	nextID int64
+	weeks  *MemoryWeeks
+	days   *MemoryDays
+}
+
+// FindOwned implements PlanRepository.
+func (r *MemoryPlans) FindOwned(userID, planID int64) (*models.LearningPlanStructure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	plan, ok := r.plans[planID]
+	if !ok || plan.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return &plan, nil
+}
+
+// FindByGoal implements PlanRepository.
+func (r *MemoryPlans) FindByGoal(userID int64, goal string) (*models.LearningPlanStructure, error) {
+	for _, plan := range r.sorted() {
+		if plan.UserID == userID && plan.Goal == goal {
+			return &plan, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// FindShared implements PlanRepository.
+func (r *MemoryPlans) FindShared(excludeUserID int64, goal string, totalWeeks int) (*models.LearningPlanStructure, error) {
+	plans := r.sorted()
+	for i := len(plans) - 1; i >= 0; i-- {
+		plan := plans[i]
+		if plan.Shared && plan.UserID != excludeUserID && strings.EqualFold(plan.Goal, goal) && plan.TotalWeeks == totalWeeks {
+			return &plan, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListByUser implements PlanRepository.
+func (r *MemoryPlans) ListByUser(userID int64) ([]models.LearningPlanStructure, error) {
+	var plans []models.LearningPlanStructure
+	for _, plan := range r.sorted() {
+		if plan.UserID == userID {
+			plans = append(plans, plan)
+		}
+	}
+	return plans, nil
+}
+
+// Create implements PlanRepository.
+func (r *MemoryPlans) Create(plan *models.LearningPlanStructure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	plan.ID = r.nextID
+	plan.CreatedAt = time.Now()
+	plan.UpdatedAt = plan.CreatedAt
+	r.plans[plan.ID] = *plan
+	return nil
+}
+
+// Delete implements PlanRepository.
+func (r *MemoryPlans) Delete(userID, planID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	plan, ok := r.plans[planID]
+	if !ok || plan.UserID != userID {
+		return ErrNotFound
+	}
+	delete(r.plans, planID)
+	if r.weeks != nil {
+		r.weeks.deletePlan(userID, planID)
+	}
+	if r.days != nil {
+		r.days.deletePlan(userID, planID)
+	}
+	return nil
+}
+
+// sorted returns the plans in the order they were created.
+func (r *MemoryPlans) sorted() []models.LearningPlanStructure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	plans := make([]models.LearningPlanStructure, 0, len(r.plans))
+	for _, plan := range r.plans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].ID < plans[j].ID })
+	return plans
+}
+
+// MemoryWeeks is a WeeklyContentRepository local to the process.
+type MemoryWeeks struct {
+	mu     sync.Mutex
+	weeks  []models.GeneratedWeeklyContent
+	flags  []models.ContentAdaptationFlag
+	nextID int64
+}
+
+// NewMemoryWeeks creates an empty in-memory weekly content repository.
+func NewMemoryWeeks() *MemoryWeeks {
+	return &MemoryWeeks{}
+}
+
+// FindCurrent implements WeeklyContentRepository.
+func (r *MemoryWeeks) FindCurrent(userID, planID int64, week int) (*models.GeneratedWeeklyContent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, content := range r.weeks {
+		if content.UserID == userID && content.PlanID == planID && content.WeekNumber == week && content.SupersededAt == nil {
+			return &content, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Create implements WeeklyContentRepository.
+func (r *MemoryWeeks) Create(content *models.GeneratedWeeklyContent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	content.ID = r.nextID
+	content.CreatedAt = time.Now()
+	r.weeks = append(r.weeks, *content)
+	return nil
+}
+
+// OpenAdaptationFlag implements WeeklyContentRepository.
+func (r *MemoryWeeks) OpenAdaptationFlag(userID, planID int64, week int) (*models.ContentAdaptationFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.flags) - 1; i >= 0; i-- {
+		flag := r.flags[i]
+		if flag.UserID == userID && flag.PlanID == planID && flag.WeekNumber == week && flag.NeedsRegeneration && flag.ResolvedAt == nil {
+			return &flag, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateAdaptationFlag implements WeeklyContentRepository.
+func (r *MemoryWeeks) CreateAdaptationFlag(flag *models.ContentAdaptationFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flag.ID = int64(len(r.flags) + 1)
+	flag.CreatedAt = time.Now()
+	flag.UpdatedAt = flag.CreatedAt
+	r.flags = append(r.flags, *flag)
+	return nil
+}
+
+func (r *MemoryWeeks) deletePlan(userID, planID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	weeks := r.weeks[:0]
+	for _, content := range r.weeks {
+		if content.UserID != userID || content.PlanID != planID {
+			weeks = append(weeks, content)
+		}
+	}
+	r.weeks = weeks
+
+	flags := r.flags[:0]
+	for _, flag := range r.flags {
+		if flag.UserID != userID || flag.PlanID != planID {
+			flags = append(flags, flag)
+		}
+	}
+	r.flags = flags
+}
+
+// MemoryDays is a DailyContentRepository local to the process. It does not
+// record exercise attempts, so no exercise counts as submitted.
+type MemoryDays struct {
+	mu     sync.Mutex
+	days   map[int64]models.DailyContent
+	nextID int64
+}
+
+// NewMemoryDays creates an empty in-memory daily content repository.
+func NewMemoryDays() *MemoryDays {
+	return &MemoryDays{days: map[int64]models.DailyContent{}}
+}
+
+// Find implements DailyContentRepository.
+func (r *MemoryDays) Find(userID, planID int64, week, day int) (*models.DailyContent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, daily := range r.days {
+		if daily.UserID == userID && daily.PlanID == planID && daily.WeekNumber == week && daily.DayNumber == day {
+			return &daily, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Create implements DailyContentRepository.
+func (r *MemoryDays) Create(daily *models.DailyContent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	daily.ID = r.nextID
+	daily.CreatedAt = time.Now()
+	daily.UpdatedAt = daily.CreatedAt
+	r.days[daily.ID] = *daily
+	return nil
+}
+
+// Save implements DailyContentRepository.
+func (r *MemoryDays) Save(daily *models.DailyContent) error {
+	if daily.ID == 0 {
+		return r.Create(daily)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	daily.UpdatedAt = time.Now()
+	r.days[daily.ID] = *daily
+	return nil
+}
+
+// Delete implements DailyContentRepository.
+func (r *MemoryDays) Delete(userID, planID int64, week, day int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, daily := range r.days {
+		if daily.UserID == userID && daily.PlanID == planID && daily.WeekNumber == week && daily.DayNumber == day {
+			delete(r.days, id)
+		}
+	}
+	return nil
+}
+
+// SubmittedExercises implements DailyContentRepository.
+func (r *MemoryDays) SubmittedExercises(daily models.DailyContent) ([]int, error) {
+	return nil, nil
+}
+
+func (r *MemoryDays) deletePlan(userID, planID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, daily := range r.days {
+		if daily.UserID == userID && daily.PlanID == planID {
+			delete(r.days, id)
+		}
+	}
+}
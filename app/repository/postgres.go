@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"gorm.io/gorm"
+)
+
+// NewPostgres returns the repositories backed by the database.
+func NewPostgres(db *gorm.DB) Repositories {
+	return Repositories{
+		Users: &PostgresUsers{db: db},
+		Plans: &PostgresPlans{db: db},
+		Weeks: &PostgresWeeks{db: db},
+		Days:  &PostgresDays{db: db},
+	}
+}
+
+// notFound translates gorm's missing record error to ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// PostgresUsers is a UserRepository in the users table.
+type PostgresUsers struct {
+	db *gorm.DB
+}
+
+// FindByID implements UserRepository.
+func (r *PostgresUsers) FindByID(id int64) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// FindByEmail implements UserRepository.
+func (r *PostgresUsers) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// Create implements UserRepository.
+func (r *PostgresUsers) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+// Save implements UserRepository.
+func (r *PostgresUsers) Save(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+// PostgresPlans is a PlanRepository in the learning_plan_structures table.
+type PostgresPlans struct {
+	db *gorm.DB
+}
+
+// FindOwned implements PlanRepository.
+func (r *PostgresPlans) FindOwned(userID, planID int64) (*models.LearningPlanStructure, error) {
+	var plan models.LearningPlanStructure
+	if err := r.db.Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &plan, nil
+}
+
+// FindByGoal implements PlanRepository.
+func (r *PostgresPlans) FindByGoal(userID int64, goal string) (*models.LearningPlanStructure, error) {
+	var plan models.LearningPlanStructure
+	if err := r.db.Where("user_id = ? AND goal = ?", userID, goal).First(&plan).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &plan, nil
+}
+
+// FindShared implements PlanRepository.
+func (r *PostgresPlans) FindShared(excludeUserID int64, goal string, totalWeeks int) (*models.LearningPlanStructure, error) {
+	var plan models.LearningPlanStructure
+	err := r.db.Where("shared = ? AND user_id <> ? AND LOWER(goal) = LOWER(?) AND total_weeks = ?", true, excludeUserID, goal, totalWeeks).
+		Order("created_at DESC").First(&plan).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &plan, nil
+}
+
+// ListByUser implements PlanRepository.
+func (r *PostgresPlans) ListByUser(userID int64) ([]models.LearningPlanStructure, error) {
+	var plans []models.LearningPlanStructure
+	if err := r.db.Where("user_id = ?", userID).Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// Create implements PlanRepository.
+func (r *PostgresPlans) Create(plan *models.LearningPlanStructure) error {
+	return r.db.Create(plan).Error
+}
+
+// Delete implements PlanRepository.
+func (r *PostgresPlans) Delete(userID, planID int64) error {
+	// Use a transaction to ensure all or nothing is deleted
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// First, verify the plan exists and belongs to the user
+		var plan models.LearningPlanStructure
+		if err := tx.Where("id = ? AND user_id = ?", planID, userID).First(&plan).Error; err != nil {
+			return notFound(err)
+		}
+
+		// tutor thread messages cascade with their threads
+		for _, model := range []interface{}{
+			&models.ExerciseAttempt{},
+			&models.LessonProgress{},
+			&models.DailyContent{},
+			&models.TutorThread{},
+			&models.PlanRating{},
+			&models.ContentAdaptationFlag{},
+			&models.GeneratedWeeklyContent{},
+		} {
+			if err := tx.Where("plan_id = ? AND user_id = ?", planID, userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Finally, delete the plan structure itself
+		return tx.Where("id = ? AND user_id = ?", planID, userID).Delete(&models.LearningPlanStructure{}).Error
+	})
+}
+
+// PostgresWeeks is a WeeklyContentRepository in the generated_weekly_contents
+// and content_adaptation_flags tables.
+type PostgresWeeks struct {
+	db *gorm.DB
+}
+
+// FindCurrent implements WeeklyContentRepository.
+func (r *PostgresWeeks) FindCurrent(userID, planID int64, week int) (*models.GeneratedWeeklyContent, error) {
+	var content models.GeneratedWeeklyContent
+	err := r.db.Where("plan_id = ? AND week_number = ? AND user_id = ? AND superseded_at IS NULL", planID, week, userID).
+		First(&content).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &content, nil
+}
+
+// Create implements WeeklyContentRepository.
+func (r *PostgresWeeks) Create(content *models.GeneratedWeeklyContent) error {
+	return r.db.Create(content).Error
+}
+
+// OpenAdaptationFlag implements WeeklyContentRepository.
+func (r *PostgresWeeks) OpenAdaptationFlag(userID, planID int64, week int) (*models.ContentAdaptationFlag, error) {
+	var flag models.ContentAdaptationFlag
+	err := r.db.Where("plan_id = ? AND user_id = ? AND week_number = ? AND needs_regeneration = ? AND resolved_at IS NULL", planID, userID, week, true).
+		Order("created_at DESC").First(&flag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// CreateAdaptationFlag implements WeeklyContentRepository.
+func (r *PostgresWeeks) CreateAdaptationFlag(flag *models.ContentAdaptationFlag) error {
+	return r.db.Create(flag).Error
+}
+
+// PostgresDays is a DailyContentRepository in the daily_contents table.
+type PostgresDays struct {
+	db *gorm.DB
+}
+
+// Find implements DailyContentRepository.
+func (r *PostgresDays) Find(userID, planID int64, week, day int) (*models.DailyContent, error) {
+	var daily models.DailyContent
+	err := r.db.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ?", planID, userID, week, day).
+		First(&daily).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &daily, nil
+}
+
+// Create implements DailyContentRepository.
+func (r *PostgresDays) Create(daily *models.DailyContent) error {
+	return r.db.Create(daily).Error
+}
+
+// Save implements DailyContentRepository.
+func (r *PostgresDays) Save(daily *models.DailyContent) error {
+	return r.db.Save(daily).Error
+}
+
+// Delete implements DailyContentRepository.
+func (r *PostgresDays) Delete(userID, planID int64, week, day int) error {
+	return r.db.Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ?", planID, userID, week, day).
+		Delete(&models.DailyContent{}).Error
+}
+
+// SubmittedExercises implements DailyContentRepository.
+func (r *PostgresDays) SubmittedExercises(daily models.DailyContent) ([]int, error) {
+	if daily.ExercisesGeneratedAt == nil {
+		return nil, nil
+	}
+	var submitted []int
+	err := r.db.Model(&models.ExerciseAttempt{}).
+		Where("plan_id = ? AND user_id = ? AND week_number = ? AND day_number = ? AND created_at >= ?", daily.PlanID, daily.UserID, daily.WeekNumber, daily.DayNumber, *daily.ExercisesGeneratedAt).
+		Distinct().Pluck("exercise_index", &submitted).Error
+	return submitted, err
+}
@@ -0,0 +1,68 @@
+// Package repository holds the queries for users, plans and their generated
+// content behind interfaces, with a Postgres implementation used by the API
+// and an in-memory one used by tests.
+package repository
+
+import (
+	"errors"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// ErrNotFound is returned when a record does not exist or belongs to another
+// user.
+var ErrNotFound = errors.New("record not found")
+
+// UserRepository stores user accounts.
+type UserRepository interface {
+	FindByID(id int64) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	Create(user *models.User) error
+	Save(user *models.User) error
+}
+
+// PlanRepository stores learning plan structures.
+type PlanRepository interface {
+	// FindOwned returns a plan only if it belongs to the user.
+	FindOwned(userID, planID int64) (*models.LearningPlanStructure, error)
+	// FindByGoal returns the user's plan for a goal.
+	FindByGoal(userID int64, goal string) (*models.LearningPlanStructure, error)
+	// FindShared returns the newest plan another user has shared for the
+	// same goal, compared case-insensitively, and number of weeks.
+	FindShared(excludeUserID int64, goal string, totalWeeks int) (*models.LearningPlanStructure, error)
+	ListByUser(userID int64) ([]models.LearningPlanStructure, error)
+	Create(plan *models.LearningPlanStructure) error
+	// Delete deletes a plan and everything generated or recorded for it.
+	Delete(userID, planID int64) error
+}
+
+// WeeklyContentRepository stores the generated versions of a plan's weeks and
+// the adaptation flags asking for a week to be regenerated.
+type WeeklyContentRepository interface {
+	// FindCurrent returns the version of a week that has not been superseded.
+	FindCurrent(userID, planID int64, week int) (*models.GeneratedWeeklyContent, error)
+	Create(content *models.GeneratedWeeklyContent) error
+	// OpenAdaptationFlag returns the newest unresolved flag asking for the
+	// week to be regenerated, or nil when there is none.
+	OpenAdaptationFlag(userID, planID int64, week int) (*models.ContentAdaptationFlag, error)
+	CreateAdaptationFlag(flag *models.ContentAdaptationFlag) error
+}
+
+// DailyContentRepository stores the lessons and exercises of a plan's days.
+type DailyContentRepository interface {
+	Find(userID, planID int64, week, day int) (*models.DailyContent, error)
+	Create(daily *models.DailyContent) error
+	Save(daily *models.DailyContent) error
+	Delete(userID, planID int64, week, day int) error
+	// SubmittedExercises returns the indexes of the exercises the user has
+	// answered since the day's exercises were last changed.
+	SubmittedExercises(daily models.DailyContent) ([]int, error)
+}
+
+// Repositories groups the repositories of one storage backend.
+type Repositories struct {
+	Users UserRepository
+	Plans PlanRepository
+	Weeks WeeklyContentRepository
+	Days  DailyContentRepository
+}
@@ -0,0 +1,138 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary List Users
+// @Description Search users (admin only)
+// @Tags Admin
+// @Param q query string false "Search email, first name and last name"
+// @Param role query string false "learner, mentor or admin"
+// @Param status query string false "active or suspended"
+// @Param page query int false "Page, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users [get]
+func (a *App) ListUsers(c echo.Context) error {
+	return a.Controller.ListUsers(c)
+}
+
+// @Summary Get User
+// @Description Retrieve a user and their plans (admin only)
+// @Tags Admin
+// @Param id path int true "User ID"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{id} [get]
+func (a *App) GetUser(c echo.Context) error {
+	return a.Controller.GetUser(c)
+}
+
+// @Summary Update User Role
+// @Description Set the role of a user (admin only)
+// @Tags Admin
+// @Param id path int true "User ID"
+// @Param request body controllers.UpdateRoleRequest true "Role"
+// @Accept json
+// @Produce json
+// @Success 200 {object} controllers.AdminUser
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (a *App) UpdateUserRole(c echo.Context) error {
+	return a.Controller.UpdateUserRole(c)
+}
+
+// @Summary Suspend User
+// @Description Suspend a user and revoke their sessions (admin only)
+// @Tags Admin
+// @Param id path int true "User ID"
+// @Param request body controllers.SuspendUserRequest false "Reason"
+// @Accept json
+// @Produce json
+// @Success 200 {object} controllers.AdminUser
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{id}/suspend [post]
+func (a *App) SuspendUser(c echo.Context) error {
+	return a.Controller.SuspendUser(c)
+}
+
+// @Summary Unsuspend User
+// @Description Lift the suspension of a user (admin only)
+// @Tags Admin
+// @Param id path int true "User ID"
+// @Produce json
+// @Success 200 {object} controllers.AdminUser
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{id}/unsuspend [post]
+func (a *App) UnsuspendUser(c echo.Context) error {
+	return a.Controller.UnsuspendUser(c)
+}
+
+// @Summary List Plans
+// @Description List the plans of all users (admin only)
+// @Tags Admin
+// @Param user_id query int false "Owner user ID"
+// @Param q query string false "Search the goal"
+// @Param page query int false "Page, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/plans [get]
+func (a *App) AdminListPlans(c echo.Context) error {
+	return a.Controller.AdminListPlans(c)
+}
+
+// @Summary Inspect Plan
+// @Description Retrieve a plan with its weeks, days, progress and adaptation flags (admin only)
+// @Tags Admin
+// @Param id path int true "Plan ID"
+// @Produce json
+// @Success 200 {object} controllers.AdminPlan
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/plans/{id} [get]
+func (a *App) AdminGetPlan(c echo.Context) error {
+	return a.Controller.AdminGetPlan(c)
+}
+
+// @Summary Delete Plan
+// @Description Delete any user's plan and all its associated data (admin only)
+// @Tags Admin
+// @Param id path int true "Plan ID"
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/plans/{id} [delete]
+func (a *App) AdminDeletePlan(c echo.Context) error {
+	return a.Controller.AdminDeletePlan(c)
+}
+
+// @Summary Regenerate Plan Content
+// @Description Force a day, a week or every generated week of a plan to be generated again, without using the owner's quota (admin only)
+// @Tags Admin
+// @Param id path int true "Plan ID"
+// @Param request body controllers.RegenerateRequest false "What to regenerate"
+// @Accept json
+// @Produce json
+// @Success 202 {object} models.GenerationJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/plans/{id}/regenerate [post]
+func (a *App) RegeneratePlanContent(c echo.Context) error {
+	return a.Controller.RegeneratePlanContent(c)
+}
@@ -11,6 +11,7 @@ import "github.com/labstack/echo/v4"
 // @Success      201  {object}  models.SuccessResponse "User created, OTP was send, show the verification page"
 // @Success      202  {object}  models.UserResponse "Status 202 will be returned if the signup was successfully, DONT show verification page"
 // @Failure      400  {object}  models.ErrorResponse
+// @Failure      429  {object}  models.ErrorResponse "Resend cooldown or too many failed attempts; see Retry-After"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router /signup [post]
 func (a *App) SignUp(c echo.Context) error {
@@ -40,6 +41,7 @@ func (a *App) Login(c echo.Context) error {
 // @Success      200  {object}  models.UserResponse "Status 200 will be returned if the verification was successful"
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      404  {object}  models.ErrorResponse
+// @Failure      429  {object}  models.ErrorResponse "Resend cooldown or too many failed attempts; see Retry-After"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router /verify-otp [post]
 func (a *App) VerifyOTP(c echo.Context) error {
@@ -55,6 +57,7 @@ func (a *App) VerifyOTP(c echo.Context) error {
 // @Success      200  {object}  models.SuccessResponse "Status 200 will be returned if the OTP was resent successfully"
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      404  {object}  models.ErrorResponse
+// @Failure      429  {object}  models.ErrorResponse "Resend cooldown or too many failed attempts; see Retry-After"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router /resend-otp [post]
 func (a *App) ResendOTP(c echo.Context) error {
@@ -69,6 +72,7 @@ func (a *App) ResendOTP(c echo.Context) error {
 // @Produce json
 // @Success      200  {object}  models.SuccessResponse "Status 200 will be returned if the password reset OTP was sent successfully"
 // @Failure      400  {object}  models.ErrorResponse
+// @Failure      429  {object}  models.ErrorResponse "Resend cooldown or too many failed attempts; see Retry-After"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router /forgot-password [post]
 func (a *App) ForgotPassword(c echo.Context) error {
@@ -84,6 +88,7 @@ func (a *App) ForgotPassword(c echo.Context) error {
 // @Success      200  {object}  models.SuccessResponse "Status 200 will be returned if the password was reset successfully"
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      404  {object}  models.ErrorResponse
+// @Failure      429  {object}  models.ErrorResponse "Resend cooldown or too many failed attempts; see Retry-After"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router /reset-password [post]
 func (a *App) ResetPassword(c echo.Context) error {
@@ -0,0 +1,211 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary List Categories
+// @Description List the categories of the plan template catalog
+// @Tags Catalog
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 500 {object} models.ErrorResponse
+// @Router /catalog/categories [get]
+func (a *App) ListCategories(c echo.Context) error {
+	return a.Controller.ListCategories(c)
+}
+
+// @Summary Browse Plan Templates
+// @Description Browse published plan templates
+// @Tags Catalog
+// @Param category_id query int false "Category ID"
+// @Param level query string false "beginner, intermediate or advanced"
+// @Param min_weeks query int false "Minimum total weeks"
+// @Param max_weeks query int false "Maximum total weeks"
+// @Param q query string false "Search title, goal and description"
+// @Produce json
+// @Success 200 {array} models.PlanTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /catalog/templates [get]
+func (a *App) ListTemplates(c echo.Context) error {
+	return a.Controller.ListTemplates(c)
+}
+
+// @Summary Get Plan Template
+// @Description Retrieve a published plan template
+// @Tags Catalog
+// @Param id path int true "Template ID"
+// @Produce json
+// @Success 200 {object} models.PlanTemplate
+// @Failure 404 {object} models.ErrorResponse
+// @Router /catalog/templates/{id} [get]
+func (a *App) GetTemplate(c echo.Context) error {
+	return a.Controller.GetTemplate(c)
+}
+
+// @Summary Start Plan Template
+// @Description Start a new private plan from a published template with your own daily commitment
+// @Tags Catalog
+// @Param id path int true "Template ID"
+// @Param request body controllers.StartTemplateRequest false "Start Template Request"
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /catalog/templates/{id}/start [post]
+func (a *App) StartTemplate(c echo.Context) error {
+	return a.Controller.StartTemplate(c)
+}
+
+// @Summary Rate Plan
+// @Description Rate one of your plans from 1 to 5
+// @Tags LearningPlan
+// @Param id path int true "Plan Structure ID"
+// @Param request body controllers.RatePlanRequest true "Rating"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PlanRating
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/structure/{id}/rating [post]
+func (a *App) RatePlan(c echo.Context) error {
+	return a.Controller.RatePlan(c)
+}
+
+// @Summary Create Category
+// @Description Create a catalog category
+// @Tags Admin
+// @Param request body controllers.CategoryRequest true "Category"
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Category
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories [post]
+func (a *App) CreateCategory(c echo.Context) error {
+	return a.Controller.CreateCategory(c)
+}
+
+// @Summary Update Category
+// @Description Update a catalog category
+// @Tags Admin
+// @Param id path int true "Category ID"
+// @Param request body controllers.CategoryRequest true "Category"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Category
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories/{id} [put]
+func (a *App) UpdateCategory(c echo.Context) error {
+	return a.Controller.UpdateCategory(c)
+}
+
+// @Summary Delete Category
+// @Description Delete a catalog category; its templates become uncategorised
+// @Tags Admin
+// @Param id path int true "Category ID"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/categories/{id} [delete]
+func (a *App) DeleteCategory(c echo.Context) error {
+	return a.Controller.DeleteCategory(c)
+}
+
+// @Summary List Template Candidates
+// @Description Rank generated plan structures by learner ratings to seed templates from
+// @Tags Admin
+// @Param min_rating query number false "Minimum average rating (default 4)"
+// @Param min_ratings query int false "Minimum number of ratings (default 1)"
+// @Produce json
+// @Success 200 {array} models.TemplateCandidate
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/templates/candidates [get]
+func (a *App) ListTemplateCandidates(c echo.Context) error {
+	return a.Controller.ListTemplateCandidates(c)
+}
+
+// @Summary List All Templates
+// @Description List published and unpublished plan templates
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} models.PlanTemplate
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/templates [get]
+func (a *App) AdminListTemplates(c echo.Context) error {
+	return a.Controller.AdminListTemplates(c)
+}
+
+// @Summary Create Template
+// @Description Create an unpublished plan template, optionally seeded from a plan structure
+// @Tags Admin
+// @Param request body controllers.PlanTemplateRequest true "Template"
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.PlanTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/templates [post]
+func (a *App) CreateTemplate(c echo.Context) error {
+	return a.Controller.CreateTemplate(c)
+}
+
+// @Summary Update Template
+// @Description Update a plan template
+// @Tags Admin
+// @Param id path int true "Template ID"
+// @Param request body controllers.PlanTemplateRequest true "Template"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PlanTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/templates/{id} [put]
+func (a *App) UpdateTemplate(c echo.Context) error {
+	return a.Controller.UpdateTemplate(c)
+}
+
+// @Summary Publish Template
+// @Description Publish a template to the public catalog or withdraw it
+// @Tags Admin
+// @Param id path int true "Template ID"
+// @Param request body controllers.PublishTemplateRequest true "Publish"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PlanTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/templates/{id}/publish [put]
+func (a *App) PublishTemplate(c echo.Context) error {
+	return a.Controller.PublishTemplate(c)
+}
+
+// @Summary Delete Template
+// @Description Delete a plan template; plans started from it are kept
+// @Tags Admin
+// @Param id path int true "Template ID"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/templates/{id} [delete]
+func (a *App) DeleteTemplate(c echo.Context) error {
+	return a.Controller.DeleteTemplate(c)
+}
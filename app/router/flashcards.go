@@ -0,0 +1,31 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Get Due Flashcards
+// @Description List the flashcards due for review by the end of today (UTC), most overdue first. Cards are derived from the key points and exercises of generated days
+// @Tags Flashcards
+// @Param plan_id query int false "Only cards of this plan"
+// @Param limit query int false "Maximum cards returned (default 20, max 100)"
+// @Produce json
+// @Success 200 {object} controllers.DueFlashcardsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /learnings/flashcards/due [get]
+func (a *App) GetDueFlashcards(c echo.Context) error {
+	return a.Controller.GetDueFlashcards(c)
+}
+
+// @Summary Review Flashcard
+// @Description Record a review with an SM-2 quality from 0 (blackout) to 5 (perfect recall) and schedule the next one
+// @Tags Flashcards
+// @Param id path int true "Flashcard ID"
+// @Param request body controllers.ReviewFlashcardRequest true "Review"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Flashcard
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /learnings/flashcards/{id}/review [post]
+func (a *App) ReviewFlashcard(c echo.Context) error {
+	return a.Controller.ReviewFlashcard(c)
+}
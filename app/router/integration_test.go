@@ -0,0 +1,833 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/auth"
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/controllers"
+	"github.com/surahj/ai-mentor-backend/app/database"
+	"github.com/surahj/ai-mentor-backend/app/jobs"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/otp"
+	"github.com/surahj/ai-mentor-backend/app/ratelimit"
+	"github.com/surahj/ai-mentor-backend/app/repository"
+	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/usage"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sentEmail is an email captured by captureEmail.
+type sentEmail struct {
+	To, Subject, Body string
+}
+
+// captureEmail is an EmailServiceProvider that keeps emails instead of
+// sending them.
+type captureEmail struct {
+	mu   sync.Mutex
+	sent []sentEmail
+}
+
+func (s *captureEmail) SendEmail(to, subject, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, sentEmail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+var otpPattern = regexp.MustCompile(`\b\d{6}\b`)
+
+// lastOTP returns the code in the newest email sent to an address.
+func (s *captureEmail) lastOTP(t *testing.T, to string) string {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.sent) - 1; i >= 0; i-- {
+		if s.sent[i].To == to {
+			if code := otpPattern.FindString(s.sent[i].Body); code != "" {
+				return code
+			}
+		}
+	}
+	t.Fatalf("no OTP was emailed to %s", to)
+	return ""
+}
+
+type testApp struct {
+	*App
+	email *captureEmail
+	llm   *services.FakeLLMProvider
+}
+
+// newTestApp builds the API on an empty SQLite database, with a fake LLM,
+// captured emails and job workers polling every few milliseconds.
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_busy_timeout=5000"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the job workers and the handlers share one connection, which keeps
+	// SQLite from failing writes with "database is locked"
+	sqlDB.SetMaxOpenConns(1)
+	// the migrations are written for Postgres, so the schema is created from
+	// the models instead
+	if err := db.AutoMigrate(database.Models...); err != nil {
+		t.Fatal(err)
+	}
+	// auth loads the signed in user through the package level instance
+	database.SetDB(db)
+
+	// a literal rather than configs.Load, so settings in the environment of
+	// the test run do not change the app under test
+	config := &configs.Config{
+		Server: configs.ServerConfig{RequestTimeout: 30 * time.Second},
+		CORS:   configs.CORSConfig{AllowOrigins: []string{"*"}},
+		JWT: configs.JWTConfig{
+			Secret:     "integration-test-jwt-secret",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 720 * time.Hour,
+		},
+		OTP: configs.OTPConfig{
+			Secret:         "integration-test-otp-secret",
+			TTL:            10 * time.Minute,
+			MaxAttempts:    5,
+			ResendCooldown: time.Minute,
+			Lockout:        15 * time.Minute,
+		},
+		LLM: configs.LLMConfig{
+			Provider:       "fake",
+			JSONMaxRetries: 2,
+			GradingMode:    "normalized",
+		},
+		Jobs: configs.JobsConfig{
+			Workers:      2,
+			PollInterval: 10 * time.Millisecond,
+			Lease:        10 * time.Minute,
+			RetryBackoff: 10 * time.Millisecond,
+			MaxAttempts:  3,
+		},
+		RateLimit: configs.RateLimitConfig{
+			Store:      "memory",
+			Auth:       configs.RateRule{Limit: 10, Window: time.Minute},
+			Generation: configs.RateRule{Limit: 30, Window: time.Minute},
+		},
+	}
+	auth.Configure(config.JWT)
+
+	email := &captureEmail{}
+	llm, err := services.NewFakeLLMProvider("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := repository.NewPostgres(db)
+	queue := jobs.NewQueue(db, config.Jobs)
+	store := ratelimit.NewMemoryStore()
+	controller := &controllers.Controller{
+		DB:          db,
+		EmailClient: email,
+		LLM:         llm,
+		Config:      config,
+		Jobs:        queue,
+		OTP:         otp.NewService(db, config.OTP),
+		Quota:       ratelimit.NewQuota(ratelimit.NewPostgresStore(db), config.Quotas),
+		Usage:       usage.NewLedger(db, config.LLM.Prices),
+		Users:       repos.Users,
+		Learning:    learning.NewService(repos, queue),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	controller.RegisterJobHandlers()
+	queue.Start(ctx)
+
+	app := &App{DB: db, Controller: controller, RateLimits: store}
+	app.setRouters()
+	return &testApp{App: app, email: email, llm: llm}
+}
+
+// do sends a request with a JSON body, when set, as the holder of token and
+// decodes the JSON response into out, when set.
+func (app *testApp) do(t *testing.T, method, path, token string, body, out interface{}) int {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	app.E.ServeHTTP(rec, req)
+
+	if out != nil && rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decoding %s: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// expect sends a request like do and fails the test unless it gets status.
+func (app *testApp) expect(t *testing.T, status int, method, path, token string, body, out interface{}) {
+	t.Helper()
+	var raw json.RawMessage
+	code := app.do(t, method, path, token, body, &raw)
+	if code != status {
+		t.Fatalf("%s %s: status = %d, want %d: %s", method, path, code, status, raw)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			t.Fatalf("%s %s: decoding %s: %v", method, path, raw, err)
+		}
+	}
+}
+
+// signUp registers an account, verifies it with the emailed OTP and logs in,
+// returning the access token.
+func (app *testApp) signUp(t *testing.T, email string) string {
+	t.Helper()
+	app.expect(t, http.StatusCreated, http.MethodPost, "/signup", "", map[string]interface{}{
+		"email":            email,
+		"password":         "correct-horse-battery",
+		"first_name":       "Ada",
+		"last_name":        "Lovelace",
+		"daily_commitment": 30,
+		"learning_goal":    "Learn Go",
+	}, nil)
+
+	login := map[string]string{"email": email, "password": "correct-horse-battery"}
+	app.expect(t, http.StatusUnauthorized, http.MethodPost, "/login", "", login, nil)
+
+	app.expect(t, http.StatusOK, http.MethodPost, "/verify-otp", "", map[string]string{
+		"email": email,
+		"otp":   app.email.lastOTP(t, email),
+	}, nil)
+
+	var res struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodPost, "/login", "", login, &res)
+	if res.Data.Token == "" {
+		t.Fatal("login returned no token")
+	}
+	return res.Data.Token
+}
+
+// waitForJob polls a generation job until it finishes and returns it. The
+// test fails if the job fails or does not finish in time.
+func (app *testApp) waitForJob(t *testing.T, token string, accepted models.GenerationJob) models.GenerationJob {
+	t.Helper()
+	path := "/jobs/" + strconv.FormatInt(accepted.ID, 10)
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var res struct {
+			Data models.GenerationJob `json:"data"`
+		}
+		app.expect(t, http.StatusOK, http.MethodGet, path, token, nil, &res)
+		switch res.Data.Status {
+		case models.JobStatusSucceeded:
+			return res.Data
+		case models.JobStatusFailed:
+			t.Fatalf("%s job failed: %s", res.Data.Kind, res.Data.LastError)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s job %d did not finish", accepted.Kind, accepted.ID)
+	return models.GenerationJob{}
+}
+
+type jobResponse struct {
+	Data models.GenerationJob `json:"data"`
+}
+
+// generatePlan generates a plan through the API and returns its ID.
+func (app *testApp) generatePlan(t *testing.T, token, goal string) int64 {
+	t.Helper()
+	var accepted jobResponse
+	app.expect(t, http.StatusAccepted, http.MethodPost, "/learnings/structure", token, map[string]interface{}{
+		"goal":             goal,
+		"total_weeks":      4,
+		"daily_commitment": 30,
+	}, &accepted)
+
+	job := app.waitForJob(t, token, accepted.Data)
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(job.Result, &result); err != nil || result.ID == 0 {
+		t.Fatalf("plan job result %s has no plan ID", job.Result)
+	}
+	return result.ID
+}
+
+func TestLearnerFlow(t *testing.T) {
+	app := newTestApp(t)
+	token := app.signUp(t, "ada@example.com")
+
+	var profile struct {
+		Data struct {
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, "/profile", token, nil, &profile)
+	if profile.Data.Email != "ada@example.com" {
+		t.Fatalf("profile email = %q", profile.Data.Email)
+	}
+
+	planID := app.generatePlan(t, token, "Learn Go")
+	plan := "/learnings/structure/" + strconv.FormatInt(planID, 10)
+
+	var structure struct {
+		Data struct {
+			Plan models.CompleteLearningPlan `json:"plan"`
+		} `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, plan, token, nil, &structure)
+	if len(structure.Data.Plan.WeeklyThemes) == 0 {
+		t.Fatal("generated plan has no weekly themes")
+	}
+
+	// requesting the same plan again returns it instead of generating another
+	var existing struct {
+		Data models.LearningPlanStructure `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodPost, "/learnings/structure", token, map[string]interface{}{
+		"goal": "Learn Go", "total_weeks": 4, "daily_commitment": 30,
+	}, &existing)
+	if existing.Data.ID != planID {
+		t.Fatalf("plan ID = %d, want %d", existing.Data.ID, planID)
+	}
+
+	// week
+	weekPath := "/learnings/weekly-content/1/" + strconv.FormatInt(planID, 10)
+	app.expect(t, http.StatusNotFound, http.MethodGet, weekPath, token, nil, nil)
+
+	var accepted jobResponse
+	app.expect(t, http.StatusAccepted, http.MethodPost, "/learnings/weekly-content", token, map[string]interface{}{
+		"plan_id": planID, "week_number": 1,
+	}, &accepted)
+	app.waitForJob(t, token, accepted.Data)
+
+	var week struct {
+		Data models.WeeklyContent `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, weekPath, token, nil, &week)
+	if week.Data.Theme == "" || len(week.Data.DailyMilestones) == 0 {
+		t.Fatalf("unexpected week content: %+v", week.Data)
+	}
+
+	// day
+	dayPath := "/learnings/daily-content/1/1/" + strconv.FormatInt(planID, 10)
+	accepted = jobResponse{}
+	app.expect(t, http.StatusAccepted, http.MethodGet, dayPath, token, nil, &accepted)
+	app.waitForJob(t, token, accepted.Data)
+
+	var day struct {
+		Data models.DailyContent `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, dayPath, token, nil, &day)
+	if len(day.Data.Content) == 0 {
+		t.Fatal("generated day has no lesson")
+	}
+
+	// exercises
+	var exercises struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, dayPath+"/exercises", token, nil, &exercises)
+	if len(exercises.Data) == 0 {
+		t.Fatal("no exercises were generated")
+	}
+	for i, exercise := range exercises.Data {
+		if _, ok := exercise["answer"]; ok {
+			t.Fatalf("exercise %d reveals its answer before it is submitted", i)
+		}
+	}
+
+	var graded struct {
+		Data controllers.SubmitExercisesResponse `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodPost, dayPath+"/submissions", token, map[string]interface{}{
+		"answers": []map[string]interface{}{
+			{"exercise_index": 0, "answer": "main"},
+			{"exercise_index": 1, "answer": "go vet"},
+		},
+	}, &graded)
+	if len(graded.Data.Results) != 2 || graded.Data.CorrectCount != 1 {
+		t.Fatalf("unexpected grading: %+v", graded.Data)
+	}
+
+	// answers are revealed once submitted, so they cannot be submitted again
+	app.expect(t, http.StatusConflict, http.MethodPost, dayPath+"/submissions", token, map[string]interface{}{
+		"answers": []map[string]interface{}{{"exercise_index": 1, "answer": "go run"}},
+	}, nil)
+
+	app.expect(t, http.StatusOK, http.MethodGet, dayPath, token, nil, &day)
+	var revealed []map[string]interface{}
+	if err := json.Unmarshal(day.Data.Exercises, &revealed); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := revealed[0]["answer"]; !ok {
+		t.Fatal("submitted exercise does not reveal its answer")
+	}
+	if _, ok := revealed[2]["answer"]; ok {
+		t.Fatal("unsubmitted exercise reveals its answer")
+	}
+
+	generated := map[string]bool{}
+	for _, req := range app.llm.Requests {
+		generated[req.Purpose] = true
+	}
+	for _, purpose := range []string{services.LLMPurposePlan, services.LLMPurposeWeek, services.LLMPurposeLesson, services.LLMPurposeExercises} {
+		if !generated[purpose] {
+			t.Errorf("the LLM was not asked for %s content", purpose)
+		}
+	}
+
+	// delete
+	app.expect(t, http.StatusOK, http.MethodDelete, "/learnings/plan/"+strconv.FormatInt(planID, 10), token, nil, nil)
+	app.expect(t, http.StatusNotFound, http.MethodGet, plan, token, nil, nil)
+	app.expect(t, http.StatusNotFound, http.MethodGet, weekPath, token, nil, nil)
+
+	var plans struct {
+		Data []models.LearningPlanStructure `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, "/learnings", token, nil, &plans)
+	if len(plans.Data) != 0 {
+		t.Fatalf("%d plans remain after deleting the only one", len(plans.Data))
+	}
+}
+
+func TestVerifyOTPRejectsWrongCode(t *testing.T) {
+	app := newTestApp(t)
+	app.expect(t, http.StatusCreated, http.MethodPost, "/signup", "", map[string]interface{}{
+		"email":            "grace@example.com",
+		"password":         "correct-horse-battery",
+		"first_name":       "Grace",
+		"last_name":        "Hopper",
+		"daily_commitment": 30,
+		"learning_goal":    "Learn Go",
+	}, nil)
+
+	code := app.email.lastOTP(t, "grace@example.com")
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if status := app.do(t, http.MethodPost, "/verify-otp", "", map[string]string{"email": "grace@example.com", "otp": wrong}, nil); status == http.StatusOK {
+		t.Fatal("a wrong OTP verified the account")
+	}
+	app.expect(t, http.StatusUnauthorized, http.MethodPost, "/login", "", map[string]string{
+		"email": "grace@example.com", "password": "correct-horse-battery",
+	}, nil)
+}
+
+func TestRegisterDuringCooldownKeepsAccount(t *testing.T) {
+	app := newTestApp(t)
+	signup := map[string]interface{}{
+		"email":            "grace@example.com",
+		"password":         "correct-horse-battery",
+		"first_name":       "Grace",
+		"last_name":        "Hopper",
+		"daily_commitment": 30,
+		"learning_goal":    "Learn Go",
+	}
+	app.expect(t, http.StatusCreated, http.MethodPost, "/signup", "", signup, nil)
+	code := app.email.lastOTP(t, "grace@example.com")
+
+	// signing up again before a new code may be sent must not take over the
+	// unverified account
+	signup["password"] = "someone-elses-password"
+	app.expect(t, http.StatusTooManyRequests, http.MethodPost, "/signup", "", signup, nil)
+
+	app.expect(t, http.StatusOK, http.MethodPost, "/verify-otp", "", map[string]string{
+		"email": "grace@example.com", "otp": code,
+	}, nil)
+	app.expect(t, http.StatusOK, http.MethodPost, "/login", "", map[string]string{
+		"email": "grace@example.com", "password": "correct-horse-battery",
+	}, nil)
+	app.expect(t, http.StatusUnauthorized, http.MethodPost, "/login", "", map[string]string{
+		"email": "grace@example.com", "password": "someone-elses-password",
+	}, nil)
+}
+
+func TestPlansAreScopedToTheirOwner(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.signUp(t, "owner@example.com")
+	other := app.signUp(t, "other@example.com")
+
+	planID := app.generatePlan(t, owner, "Learn Go")
+	id := strconv.FormatInt(planID, 10)
+
+	app.expect(t, http.StatusNotFound, http.MethodGet, "/learnings/structure/"+id, other, nil, nil)
+	app.expect(t, http.StatusNotFound, http.MethodPost, "/learnings/weekly-content", other, map[string]interface{}{
+		"plan_id": planID, "week_number": 1,
+	}, nil)
+	app.expect(t, http.StatusNotFound, http.MethodDelete, "/learnings/plan/"+id, other, nil, nil)
+	app.expect(t, http.StatusOK, http.MethodGet, "/learnings/structure/"+id, owner, nil, nil)
+	app.expect(t, http.StatusUnauthorized, http.MethodGet, "/learnings/structure/"+id, "", nil, nil)
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	app := newTestApp(t)
+	owner := app.signUp(t, "owner@example.com")
+	mentor := app.signUp(t, "mentor@example.com")
+	admin := app.signUp(t, "admin@example.com")
+	for email, role := range map[string]string{"mentor@example.com": models.RoleMentor, "admin@example.com": models.RoleAdmin} {
+		if err := database.GetDB().Model(&models.User{}).Where("email = ?", email).Update("role", role).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	planID := app.generatePlan(t, owner, "Learn Go")
+	var user models.User
+	if err := database.GetDB().Where("email = ?", "owner@example.com").First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		"/admin/users",
+		"/admin/users/" + strconv.FormatInt(user.ID, 10),
+		"/admin/plans",
+		"/admin/plans/" + strconv.FormatInt(planID, 10),
+	} {
+		app.expect(t, http.StatusForbidden, http.MethodGet, path, mentor, nil, nil)
+		app.expect(t, http.StatusForbidden, http.MethodGet, path, owner, nil, nil)
+		app.expect(t, http.StatusOK, http.MethodGet, path, admin, nil, nil)
+	}
+}
+
+// sseEvent is a server-sent event read by readSSE.
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+// readSSE splits a server-sent event stream into its events.
+func readSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var event sseEvent
+		for _, line := range strings.Split(block, "\n") {
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event.Name = name
+			} else if data, ok := strings.CutPrefix(line, "data: "); ok {
+				event.Data = data
+			}
+		}
+		if event.Name == "" {
+			t.Fatalf("malformed event %q in stream %q", block, body)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// stream sends a GET request to a server-sent event endpoint through the full
+// middleware stack and returns the events.
+func (app *testApp) stream(t *testing.T, path, token string) []sseEvent {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	app.E.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d: %s", path, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("GET %s: content type = %q", path, got)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("GET %s: stream is encoded with %s", path, got)
+	}
+	return readSSE(t, rec.Body.String())
+}
+
+func TestStreamDailyContent(t *testing.T) {
+	app := newTestApp(t)
+	token := app.signUp(t, "ada@example.com")
+	planID := app.generatePlan(t, token, "Learn Go")
+
+	var accepted jobResponse
+	app.expect(t, http.StatusAccepted, http.MethodPost, "/learnings/weekly-content", token, map[string]interface{}{
+		"plan_id": planID, "week_number": 1,
+	}, &accepted)
+	app.waitForJob(t, token, accepted.Data)
+
+	dayPath := "/learnings/daily-content/1/1/" + strconv.FormatInt(planID, 10)
+	events := app.stream(t, dayPath+"/stream", token)
+
+	last := events[len(events)-1]
+	if last.Name != "done" {
+		t.Fatalf("stream ended with %q: %s", last.Name, last.Data)
+	}
+	deltas := 0
+	for _, event := range events[:len(events)-1] {
+		if event.Name != "delta" {
+			t.Fatalf("unexpected %q event: %s", event.Name, event.Data)
+		}
+		deltas++
+	}
+	if deltas == 0 {
+		t.Fatal("the lesson was not streamed in deltas")
+	}
+
+	var done struct {
+		Data models.DailyContent `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(last.Data), &done); err != nil {
+		t.Fatal(err)
+	}
+	if done.Data.ID == 0 || len(done.Data.Content) == 0 {
+		t.Fatalf("done event has no saved lesson: %s", last.Data)
+	}
+
+	// the streamed day is the one served afterwards, without another job
+	var day struct {
+		Data models.DailyContent `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodGet, dayPath, token, nil, &day)
+	if day.Data.ID != done.Data.ID {
+		t.Fatalf("day ID = %d, want the streamed %d", day.Data.ID, done.Data.ID)
+	}
+
+	// streaming an existing day sends it without generating it again
+	lessons := 0
+	for _, req := range app.llm.Requests {
+		if req.Purpose == services.LLMPurposeLesson {
+			lessons++
+		}
+	}
+	events = app.stream(t, dayPath+"/stream", token)
+	if len(events) != 1 || events[0].Name != "done" {
+		t.Fatalf("events = %+v, want a single done event", events)
+	}
+	for _, req := range app.llm.Requests {
+		if req.Purpose == services.LLMPurposeLesson {
+			lessons--
+		}
+	}
+	if lessons != 0 {
+		t.Fatal("streaming an existing day generated it again")
+	}
+}
+
+// generateDay generates a plan, its first week and first day with exercises,
+// and returns the path of the day.
+func (app *testApp) generateDay(t *testing.T, token string) string {
+	t.Helper()
+	planID := app.generatePlan(t, token, "Learn Go")
+
+	var accepted jobResponse
+	app.expect(t, http.StatusAccepted, http.MethodPost, "/learnings/weekly-content", token, map[string]interface{}{
+		"plan_id": planID, "week_number": 1,
+	}, &accepted)
+	app.waitForJob(t, token, accepted.Data)
+
+	dayPath := "/learnings/daily-content/1/1/" + strconv.FormatInt(planID, 10)
+	accepted = jobResponse{}
+	app.expect(t, http.StatusAccepted, http.MethodGet, dayPath, token, nil, &accepted)
+	app.waitForJob(t, token, accepted.Data)
+	app.expect(t, http.StatusOK, http.MethodGet, dayPath+"/exercises", token, nil, nil)
+	return dayPath
+}
+
+func TestSubmitTranslatedExercises(t *testing.T) {
+	app := newTestApp(t)
+	token := app.signUp(t, "ada@example.com")
+	dayPath := app.generateDay(t, token)
+
+	app.llm.SetFixture(services.LLMPurposeTranslate, `{
+		"lesson": {"title": "Hola, mundo", "summary": "Tu primer programa.", "key_points": ["package main"], "explanation": "<p>Hola</p>"},
+		"exercises": [
+			{"type": "multiple_choice", "question": "¿Qué paquete?", "options": ["main", "app", "init", "cmd"], "answer": "main", "explanation": "package main.", "difficulty": "beginner"},
+			{"type": "multiple_choice", "question": "¿Qué comando ejecuta?", "options": ["compilar", "ejecutar", "revisar", "formatear"], "answer": "ejecutar", "explanation": "go run.", "difficulty": "beginner"},
+			{"type": "short_answer", "question": "¿Dónde empieza?", "answer": "la función main", "explanation": "func main.", "difficulty": "beginner"}
+		],
+		"resources": [
+			{"type": "article", "title": "Un tour por Go", "url": "https://go.dev/tour/", "description": "Tour."},
+			{"type": "article", "title": "Cómo escribir código Go", "url": "https://go.dev/doc/code", "description": "Guía."},
+			{"type": "book", "title": "The Go Programming Language", "url": "https://www.gopl.io/", "description": "Libro."}
+		]
+	}`)
+
+	app.expect(t, http.StatusBadRequest, http.MethodPost, dayPath+"/translate", token, map[string]string{"language": "Klingon"}, nil)
+	app.expect(t, http.StatusOK, http.MethodPost, dayPath+"/translate", token, map[string]string{"language": "es"}, nil)
+
+	var graded struct {
+		Data controllers.SubmitExercisesResponse `json:"data"`
+	}
+	app.expect(t, http.StatusOK, http.MethodPost, dayPath+"/submissions", token, map[string]interface{}{
+		"language": "Español",
+		"answers": []map[string]interface{}{
+			// an option of the original maps to the translated one
+			{"exercise_index": 1, "answer": "go run"},
+			{"exercise_index": 2, "answer": "La función main"},
+		},
+	}, &graded)
+	if graded.Data.CorrectCount != 2 {
+		t.Fatalf("unexpected grading: %+v", graded.Data)
+	}
+	if got := graded.Data.Results[0].CorrectAnswer; got != "ejecutar" {
+		t.Errorf("correct answer = %q, want the translated option", got)
+	}
+}
+
+func TestSubmitExercisesOnce(t *testing.T) {
+	app := newTestApp(t)
+	token := app.signUp(t, "ada@example.com")
+	dayPath := app.generateDay(t, token)
+	answer := map[string]interface{}{
+		"answers": []map[string]interface{}{{"exercise_index": 2, "answer": "The main function"}},
+	}
+
+	app.expect(t, http.StatusOK, http.MethodPost, dayPath+"/submissions", token, answer, nil)
+	app.expect(t, http.StatusConflict, http.MethodPost, dayPath+"/submissions", token, answer, nil)
+
+	// a submission that raced past the check is rejected by the unique index;
+	// backdating the attempt hides it from the check
+	var daily models.DailyContent
+	if err := database.GetDB().Where("week_number = 1 AND day_number = 1").First(&daily).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.GetDB().Model(&models.ExerciseAttempt{}).Where("daily_content_id = ?", daily.ID).
+		Update("created_at", daily.ExercisesGeneratedAt.Add(-time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	app.expect(t, http.StatusConflict, http.MethodPost, dayPath+"/submissions", token, answer, nil)
+
+	var attempts int64
+	database.GetDB().Model(&models.ExerciseAttempt{}).Where("daily_content_id = ?", daily.ID).Count(&attempts)
+	if attempts != 1 {
+		t.Errorf("%d attempts saved, want 1", attempts)
+	}
+}
+
+func TestAdaptationRegeneratesWeekOnce(t *testing.T) {
+	app := newTestApp(t)
+	token := app.signUp(t, "ada@example.com")
+	dayPath := app.generateDay(t, token)
+	planPath := strings.TrimPrefix(dayPath, "/learnings/daily-content/1/1/")
+	var daily models.DailyContent
+	if err := database.GetDB().First(&daily).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var accepted jobResponse
+	app.expect(t, http.StatusAccepted, http.MethodPost, "/learnings/weekly-content", token, map[string]interface{}{
+		"plan_id": daily.PlanID, "week_number": 2,
+	}, &accepted)
+	app.waitForJob(t, token, accepted.Data)
+
+	wrong := map[string]interface{}{
+		"answers": []map[string]interface{}{
+			{"exercise_index": 0, "answer": "app"},
+			{"exercise_index": 1, "answer": "go vet"},
+		},
+	}
+	submitDay := func(day int) {
+		t.Helper()
+		path := "/learnings/daily-content/" + strconv.Itoa(day) + "/1/" + planPath
+		if day > 1 {
+			var accepted jobResponse
+			app.expect(t, http.StatusAccepted, http.MethodGet, path, token, nil, &accepted)
+			app.waitForJob(t, token, accepted.Data)
+			app.expect(t, http.StatusOK, http.MethodGet, path+"/exercises", token, nil, nil)
+		}
+		app.expect(t, http.StatusOK, http.MethodPost, path+"/submissions", token, wrong, nil)
+	}
+	regenerations := func() []models.GenerationJob {
+		t.Helper()
+		var jobs []models.GenerationJob
+		if err := database.GetDB().Where("kind = ?", models.JobKindWeeklyContent).Order("id").Offset(2).Find(&jobs).Error; err != nil {
+			t.Fatal(err)
+		}
+		return jobs
+	}
+
+	// the second day brings the attempts over the minimum and the low
+	// accuracy flags the second week, which is already generated
+	submitDay(1)
+	submitDay(2)
+	jobs := regenerations()
+	if len(jobs) != 1 {
+		t.Fatalf("%d regenerations queued, want 1", len(jobs))
+	}
+	app.waitForJob(t, token, jobs[0])
+
+	// the accuracy is still low, but the week was just adapted for it
+	submitDay(3)
+	submitDay(4)
+	if jobs := regenerations(); len(jobs) != 1 {
+		t.Errorf("%d regenerations queued, want 1", len(jobs))
+	}
+}
+
+func TestRetriedPlanJobKeepsOnePlan(t *testing.T) {
+	app := newTestApp(t)
+	token := app.signUp(t, "ada@example.com")
+	planID := app.generatePlan(t, token, "Learn Go")
+
+	// the attempt saved the plan but its outcome was lost, so it runs again
+	var job models.GenerationJob
+	if err := database.GetDB().Where("kind = ?", models.JobKindPlanStructure).First(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.GetDB().Model(&job).Updates(map[string]interface{}{
+		"status": models.JobStatusQueued, "result": nil, "completed_at": nil, "run_at": time.Now(),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	requests := len(app.llm.Requests)
+	job = app.waitForJob(t, token, job)
+
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(job.Result, &result); err != nil || result.ID != planID {
+		t.Errorf("retried job result %s, want plan %d", job.Result, planID)
+	}
+	var plans int64
+	database.GetDB().Model(&models.LearningPlanStructure{}).Count(&plans)
+	if plans != 1 {
+		t.Errorf("%d plans saved, want 1", plans)
+	}
+	if len(app.llm.Requests) != requests {
+		t.Error("the retried job generated the plan again")
+	}
+}
@@ -0,0 +1,28 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Get Generation Job
+// @Description Poll the status of a background generation job; the result is included once it has succeeded
+// @Tags Jobs
+// @Param id path int true "Job ID"
+// @Produce json
+// @Success 200 {object} models.GenerationJob
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /jobs/{id} [get]
+func (a *App) GetJob(c echo.Context) error {
+	return a.Controller.GetJob(c)
+}
+
+// @Summary Get Generation Quota
+// @Description Show the AI generations used and remaining for the current day and month
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} ratelimit.QuotaStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /quota [get]
+func (a *App) GetQuota(c echo.Context) error {
+	return a.Controller.GetQuota(c)
+}
@@ -9,8 +9,11 @@ import "github.com/labstack/echo/v4"
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.LearningPlanStructure
+// @Success 201 {object} models.SuccessResponse
+// @Success 202 {object} models.GenerationJob
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
 // @Router /learnings/structure [post]
 func (a *App) GeneratePlanStructure(c echo.Context) error {
 	return a.Controller.GeneratePlanStructure(c)
@@ -37,6 +40,7 @@ func (a *App) GetPlanStructure(c echo.Context) error {
 // @Success 200 {object} controllers.ValidateGoalResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
 // @Router /learnings/validate-goal [post]
 func (a *App) ValidateGoal(c echo.Context) error {
 	return a.Controller.ValidateGoal(c)
@@ -49,8 +53,10 @@ func (a *App) ValidateGoal(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.GeneratedWeeklyContent
+// @Success 202 {object} models.GenerationJob
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
 // @Router /learnings/weekly-content [post]
 func (a *App) GenerateWeekContent(c echo.Context) error {
 	return a.Controller.GenerateWeekContent(c)
@@ -62,7 +68,7 @@ func (a *App) GenerateWeekContent(c echo.Context) error {
 // @Param plan_id path int true "Plan ID"
 // @Param week_number path int true "Week Number"
 // @Produce json
-// @Success 200 {object} models.GeneratedWeeklyContent
+// @Success 200 {object} controllers.WeekContentResponse "stale is set while the week is regenerated to adapt to the learner's progress"
 // @Failure 404 {object} models.ErrorResponse
 // @Router /learnings/weekly-content/{week_number}/{plan_id} [get]
 func (a *App) GetWeekContent(c echo.Context) error {
@@ -89,13 +95,31 @@ func (a *App) GetLearnings(c echo.Context) error {
 // @Param day_number path int true "Day Number"
 // @Produce json
 // @Success 200 {object} models.SuccessResponse
+// @Success 202 {object} models.GenerationJob
 // @Failure 404 {object} models.ErrorResponse
 
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
 // @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id} [get]
 func (a *App) GetDailyContent(c echo.Context) error {
 	return a.Controller.GetDailyContent(c)
 }
 
+// @Summary Stream Daily Content
+// @Description Generate daily content and stream the lesson explanation as server-sent events. "delta" events carry {"html"} fragments, a final "done" event carries the saved daily content and an "error" event reports a failure
+// @Tags LearningPlan
+// @Param plan_id path int true "Plan ID"
+// @Param week_number path int true "Week Number"
+// @Param day_number path int true "Day Number"
+// @Produce text/event-stream
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
+// @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id}/stream [get]
+func (a *App) StreamDailyContent(c echo.Context) error {
+	return a.Controller.StreamDailyContent(c)
+}
+
 // @Summary Generate Exercises for Daily Content
 // @Description Generate exercises for a specific day of a learning plan
 // @Tags LearningPlan
@@ -106,6 +130,7 @@ func (a *App) GetDailyContent(c echo.Context) error {
 // @Success 200 {object} models.SuccessResponse
 // @Failure 404 {object} models.ErrorResponse
 
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
 // @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id}/exercises [get]
 func (a *App) GenerateDailyExercises(c echo.Context) error {
 	return a.Controller.GenerateDailyExercises(c)
@@ -124,3 +149,41 @@ func (a *App) GenerateDailyExercises(c echo.Context) error {
 func (a *App) DeletePlan(c echo.Context) error {
 	return a.Controller.DeletePlan(c)
 }
+
+// @Summary Submit Exercise Answers
+// @Description Grade answers to the exercises of a day, store the attempts and return per-question feedback. Answers to a translated day are graded against the translation in the learner's preferred language, or in the given language
+// @Tags LearningPlan
+// @Param plan_id path int true "Plan ID"
+// @Param week_number path int true "Week Number"
+// @Param day_number path int true "Day Number"
+// @Param request body controllers.SubmitExercisesRequest true "Answers"
+// @Accept json
+// @Produce json
+// @Success 200 {object} controllers.SubmitExercisesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "An exercise was already submitted"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id}/submissions [post]
+func (a *App) SubmitExercises(c echo.Context) error {
+	return a.Controller.SubmitExercises(c)
+}
+
+// @Summary Translate Daily Content
+// @Description Translate a day's lesson, exercises and resources into another language without regenerating the curriculum. Translations are cached until the day's content changes
+// @Tags LearningPlan
+// @Param plan_id path int true "Plan ID"
+// @Param week_number path int true "Week Number"
+// @Param day_number path int true "Day Number"
+// @Param request body controllers.TranslateRequest true "Target language"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id}/translate [post]
+func (a *App) TranslateDailyContent(c echo.Context) error {
+	return a.Controller.TranslateDailyContent(c)
+}
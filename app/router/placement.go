@@ -0,0 +1,46 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Start Placement Quiz
+// @Description Generate an adaptive placement quiz for a goal. The response lists the first (intermediate) stage of questions
+// @Tags Placement
+// @Param request body controllers.PlacementRequest true "Placement Request"
+// @Accept json
+// @Produce json
+// @Success 201 {object} controllers.PlacementResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/placement [post]
+func (a *App) StartPlacement(c echo.Context) error {
+	return a.Controller.StartPlacement(c)
+}
+
+// @Summary Get Placement Diagnostic
+// @Description Retrieve a placement diagnostic with its current questions, graded answers and, once completed, the inferred level and known concepts
+// @Tags Placement
+// @Param id path int true "Placement Diagnostic ID"
+// @Produce json
+// @Success 200 {object} controllers.PlacementResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /learnings/placement/{id} [get]
+func (a *App) GetPlacement(c echo.Context) error {
+	return a.Controller.GetPlacement(c)
+}
+
+// @Summary Submit Placement Answers
+// @Description Grade the answers to the current stage. Passing the intermediate stage continues with advanced questions, failing it with beginner ones; the second stage completes the diagnostic. Unanswered questions count as wrong
+// @Tags Placement
+// @Param id path int true "Placement Diagnostic ID"
+// @Param request body controllers.SubmitPlacementRequest true "Answers"
+// @Accept json
+// @Produce json
+// @Success 200 {object} controllers.PlacementResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /learnings/placement/{id}/answers [post]
+func (a *App) SubmitPlacementAnswers(c echo.Context) error {
+	return a.Controller.SubmitPlacementAnswers(c)
+}
@@ -0,0 +1,43 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Share Plan Structure
+// @Description Publish one of your plan structures as a template other users can clone, or withdraw it
+// @Tags LearningPlan
+// @Param id path int true "Plan Structure ID"
+// @Param request body controllers.SharePlanRequest true "Share Plan Request"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.LearningPlanStructure
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /learnings/structure/{id}/share [put]
+func (a *App) SharePlan(c echo.Context) error {
+	return a.Controller.SharePlan(c)
+}
+
+// @Summary List Shared Plans
+// @Description List plan structures other users have shared as templates
+// @Tags LearningPlan
+// @Param goal query string false "Filter by goal"
+// @Produce json
+// @Success 200 {array} models.SharedPlanTemplate
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/shared [get]
+func (a *App) GetSharedPlans(c echo.Context) error {
+	return a.Controller.GetSharedPlans(c)
+}
+
+// @Summary Clone Plan Structure
+// @Description Copy a shared plan structure into your account as a new private plan
+// @Tags LearningPlan
+// @Param id path int true "Shared Plan Structure ID"
+// @Produce json
+// @Success 201 {object} models.SuccessResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/structure/{id}/clone [post]
+func (a *App) ClonePlan(c echo.Context) error {
+	return a.Controller.ClonePlan(c)
+}
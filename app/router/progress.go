@@ -0,0 +1,45 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Update Lesson Progress
+// @Description Record that a lesson was started or completed, time spent on it and self-rated confidence
+// @Tags Progress
+// @Param request body controllers.LessonProgressRequest true "Lesson Progress"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/progress/lesson [post]
+func (a *App) UpdateLessonProgress(c echo.Context) error {
+	return a.Controller.UpdateLessonProgress(c)
+}
+
+// @Summary Get Progress
+// @Description Retrieve the aggregated progress of the authenticated user in a learning plan
+// @Tags Progress
+// @Param plan_id path int true "Plan ID"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/progress/{plan_id} [get]
+func (a *App) GetProgress(c echo.Context) error {
+	return a.Controller.GetProgress(c)
+}
+
+// @Summary Get Adaptations
+// @Description List the adaptation flags raised for a learning plan from exercise scores and pacing
+// @Tags Progress
+// @Param plan_id path int true "Plan ID"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/adaptations/{plan_id} [get]
+func (a *App) GetAdaptations(c echo.Context) error {
+	return a.Controller.GetAdaptations(c)
+}
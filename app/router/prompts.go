@@ -0,0 +1,38 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary List Prompt Templates
+// @Description List every prompt template with its versions and the version used for generation (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} prompts.Summary
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/prompts [get]
+func (a *App) ListPrompts(c echo.Context) error {
+	return a.Controller.ListPrompts(c)
+}
+
+// @Summary Preview Prompt
+// @Description Render a prompt template with the data generation would use for a plan, week and day, without calling the LLM (admin only)
+// @Tags Admin
+// @Param name path string true "Prompt name, e.g. daily_lesson"
+// @Param version query int false "Template version (default: active version)"
+// @Param plan_id query int false "Plan ID"
+// @Param week query int false "Week number"
+// @Param day query int false "Day number"
+// @Param goal query string false "Goal, for plan_structure and validate_goal without a plan"
+// @Param total_weeks query int false "Total weeks, for plan_structure without a plan"
+// @Param daily_commitment query int false "Daily commitment in minutes, for plan_structure without a plan"
+// @Param exercise query int false "Exercise index, for grade_answer"
+// @Param answer query string false "Learner answer, for grade_answer"
+// @Produce json
+// @Success 200 {object} prompts.Rendered
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/prompts/{name}/preview [get]
+func (a *App) PreviewPrompt(c echo.Context) error {
+	return a.Controller.PreviewPrompt(c)
+}
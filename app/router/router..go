@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -13,7 +13,14 @@ import (
 	"github.com/surahj/ai-mentor-backend/app/auth"
 	"github.com/surahj/ai-mentor-backend/app/configs"
 	"github.com/surahj/ai-mentor-backend/app/controllers"
+	"github.com/surahj/ai-mentor-backend/app/jobs"
+	"github.com/surahj/ai-mentor-backend/app/learning"
+	"github.com/surahj/ai-mentor-backend/app/otp"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/ratelimit"
+	"github.com/surahj/ai-mentor-backend/app/repository"
 	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/usage"
 	_ "github.com/surahj/ai-mentor-backend/docs" // docs is generated by Swag CLI, you have to import it.
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"gorm.io/gorm"
@@ -24,38 +31,85 @@ type App struct {
 	DB         *gorm.DB
 	E          *echo.Echo
 	Controller *controllers.Controller
+	RateLimits ratelimit.Store
 }
 
 // Initialize initializes the app with predefined configuration
 func (a *App) Initialize(ctx context.Context, dbInstance *gorm.DB, config *configs.Config) {
 
 	a.DB = dbInstance
+	auth.Configure(config.JWT)
 
-	emailService, err := services.NewEmailService()
+	emailService, err := services.NewEmailService(config.Email)
 	if err != nil {
 		log.Fatalf("Failed to initialize email service: %v", err)
 	}
 
+	llmProvider, err := services.NewLLMProvider(config.LLM)
+	if err != nil {
+		log.Fatalf("Failed to initialize LLM provider: %v", err)
+	}
+
+	// prompt templates stored in the database add versions to the bundled files
+	if err := prompts.Default.LoadOverrides(dbInstance); err != nil {
+		log.Printf("Failed to load prompt overrides: %v", err)
+	}
+	prompts.Default.StartReload(ctx, dbInstance, time.Minute)
+
+	rateLimitStore, err := ratelimit.NewStore(dbInstance, config.RateLimit)
+	if err != nil {
+		log.Fatalf("Failed to initialize rate limit store: %v", err)
+	}
+	a.RateLimits = rateLimitStore
+
+	// quotas span days and months, so they are counted in the database even
+	// when request limits are kept in memory; the cleanup covers both
+	quotaStore := ratelimit.NewPostgresStore(dbInstance)
+	quotaStore.StartCleanup(ctx, 10*time.Minute)
+
+	repos := repository.NewPostgres(dbInstance)
+	queue := jobs.NewQueue(dbInstance, config.Jobs)
+
 	controller := controllers.Controller{
 		DB:          dbInstance,
 		EmailClient: emailService,
+		LLM:         llmProvider,
 		Config:      config,
+		Jobs:        queue,
+		OTP:         otp.NewService(dbInstance, config.OTP),
+		Quota:       ratelimit.NewQuota(quotaStore, config.Quotas),
+		Usage:       usage.NewLedger(dbInstance, config.LLM.Prices),
+		Users:       repos.Users,
+		Learning:    learning.NewService(repos, queue),
 	}
 
 	a.Controller = &controller
 
+	// generation runs in background workers that poll the jobs table
+	controller.RegisterJobHandlers()
+	controller.Jobs.Start(ctx)
+
 	a.setRouters()
 }
 
 // setRouters sets the all required router
 func (a *App) setRouters() {
+	config := a.Controller.Config
 
 	// init webserver
 	a.E = echo.New()
+	// rate limits key on the client IP, so it must come from the connection
+	// rather than headers the client can set
+	a.E.IPExtractor = echo.ExtractIPDirect()
 	a.E.Static("/doc", "api")
 
 	// rest compression middleware
-	a.E.Use(middleware.Gzip())
+	a.E.Use(middleware.GzipWithConfig(middleware.GzipConfig{
+		// compressing server-sent events would buffer them
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/stream")
+		},
+	}))
 
 	// add recovery middleware to make the system null safe
 	a.E.Use(middleware.Recover())
@@ -63,11 +117,10 @@ func (a *App) setRouters() {
 
 	//setup CORS
 	allowedMethods := []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete}
-	AllowOrigins := []string{"*"}
 
 	corsConfig := middleware.CORSConfig{
-		AllowOrigins: AllowOrigins, // in production limit this to only known hosts
-		AllowHeaders: AllowOrigins,
+		AllowOrigins: config.CORS.AllowOrigins, // in production limit this to only known hosts
+		AllowHeaders: []string{"*"},
 		AllowMethods: allowedMethods,
 	}
 
@@ -75,12 +128,15 @@ func (a *App) setRouters() {
 
 	// request timeout middleware
 	a.E.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
-		Skipper:      middleware.DefaultSkipper,
+		// the timeout writer cannot flush, and streams outlive the timeout
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/stream")
+		},
 		ErrorMessage: "custom timeout error message returns to client",
 		OnTimeoutRouteErrorHandler: func(err error, c echo.Context) {
 			log.Printf("timeout on handler %s ", c.Path())
 		},
-		Timeout: 240 * time.Second,
+		Timeout: config.Server.RequestTimeout,
 	}))
 
 	// logging miffleware
@@ -89,14 +145,26 @@ func (a *App) setRouters() {
 		Output: log.Writer(),
 	}))
 
+	// rate limits: credential endpoints per client IP, AI generation per user
+	authRule := ratelimit.Rule(config.RateLimit.Auth)
+	authLimit := func(name string) echo.MiddlewareFunc {
+		return ratelimit.Limit(a.RateLimits, name, authRule, ratelimit.KeyByIP)
+	}
+	generationLimit := ratelimit.Limit(a.RateLimits, "generation",
+		ratelimit.Rule(config.RateLimit.Generation), ratelimit.KeyByUser)
+
 	// auth routes
-	a.E.POST("/signup", a.SignUp)
-	a.E.POST("/login", a.Login)
-	a.E.POST("/verify-otp", a.VerifyOTP)
-	a.E.POST("/resend-otp", a.ResendOTP)
-	a.E.POST("/forgot-password", a.ForgotPassword)
-	a.E.POST("/reset-password", a.ResetPassword)
-	a.E.POST("/auth/google/login", a.GoogleLogin)
+	a.E.POST("/signup", a.SignUp, authLimit("signup"))
+	a.E.POST("/login", a.Login, authLimit("login"))
+	a.E.POST("/verify-otp", a.VerifyOTP, authLimit("verify-otp"))
+	a.E.POST("/resend-otp", a.ResendOTP, authLimit("resend-otp"))
+	a.E.POST("/forgot-password", a.ForgotPassword, authLimit("forgot-password"))
+	a.E.POST("/reset-password", a.ResetPassword, authLimit("reset-password"))
+	a.E.POST("/auth/google/login", a.GoogleLogin, authLimit("google-login"))
+	a.E.POST("/auth/refresh", a.RefreshToken, authLimit("refresh"))
+	a.E.POST("/auth/logout", auth.Authenticate(a.Logout))
+	a.E.POST("/auth/logout-all", auth.Authenticate(a.LogoutAll))
+	a.E.GET("/auth/sessions", auth.Authenticate(a.ListSessions))
 	// a.E.POST("/token/resend", a.ResendToken)
 
 	// a.E.PATCH("/password/forgot", a.ForgotPassword)
@@ -110,15 +178,83 @@ func (a *App) setRouters() {
 	a.E.GET("/profile", auth.Authenticate(a.GetProfile))
 
 	// Learning Plan Structure routes (protected)
-	a.E.POST("/learnings/structure", auth.Authenticate(a.GeneratePlanStructure))
+	a.E.POST("/learnings/structure", auth.Authenticate(generationLimit(a.GeneratePlanStructure)))
 	a.E.GET("/learnings/structure/:id", auth.Authenticate(a.GetPlanStructure))
-	a.E.POST("/learnings/weekly-content", auth.Authenticate(a.GenerateWeekContent))
+	a.E.PUT("/learnings/structure/:id/share", auth.Authenticate(a.SharePlan))
+	a.E.POST("/learnings/structure/:id/clone", auth.Authenticate(a.ClonePlan))
+	a.E.GET("/learnings/shared", auth.Authenticate(a.GetSharedPlans))
+	a.E.POST("/learnings/structure/:id/rating", auth.Authenticate(a.RatePlan))
+	a.E.POST("/learnings/weekly-content", auth.Authenticate(generationLimit(a.GenerateWeekContent)))
 	a.E.GET("/learnings/weekly-content/:week_number/:plan_id", auth.Authenticate(a.GetWeekContent))
 	a.E.GET("/learnings", auth.Authenticate(a.GetLearnings))
-	a.E.GET("/learnings/daily-content/:day_number/:week_number/:plan_id", auth.Authenticate(a.GetDailyContent))
-	a.E.GET("/learnings/daily-content/:day_number/:week_number/:plan_id/exercises", auth.Authenticate(a.GenerateDailyExercises))
-
-	a.E.POST("/learnings/validate-goal", auth.Authenticate(a.ValidateGoal))
+	a.E.GET("/learnings/daily-content/:day_number/:week_number/:plan_id", auth.Authenticate(generationLimit(a.GetDailyContent)))
+	a.E.GET("/learnings/daily-content/:day_number/:week_number/:plan_id/stream", auth.Authenticate(generationLimit(a.StreamDailyContent)))
+	a.E.GET("/learnings/daily-content/:day_number/:week_number/:plan_id/exercises", auth.Authenticate(generationLimit(a.GenerateDailyExercises)))
+	a.E.POST("/learnings/daily-content/:day_number/:week_number/:plan_id/submissions", auth.Authenticate(a.SubmitExercises))
+	a.E.POST("/learnings/daily-content/:day_number/:week_number/:plan_id/translate", auth.Authenticate(generationLimit(a.TranslateDailyContent)))
+
+	// Progress routes (protected)
+	a.E.POST("/learnings/progress/lesson", auth.Authenticate(a.UpdateLessonProgress))
+	a.E.GET("/learnings/progress/:plan_id", auth.Authenticate(a.GetProgress))
+	a.E.GET("/learnings/adaptations/:plan_id", auth.Authenticate(a.GetAdaptations))
+
+	// Placement diagnostic routes (protected)
+	a.E.POST("/learnings/placement", auth.Authenticate(generationLimit(a.StartPlacement)))
+	a.E.GET("/learnings/placement/:id", auth.Authenticate(a.GetPlacement))
+	a.E.POST("/learnings/placement/:id/answers", auth.Authenticate(a.SubmitPlacementAnswers))
+
+	// Tutor chat routes (protected)
+	a.E.POST("/learnings/daily-content/:day_number/:week_number/:plan_id/tutor/threads", auth.Authenticate(a.CreateTutorThread))
+	a.E.GET("/learnings/daily-content/:day_number/:week_number/:plan_id/tutor/threads", auth.Authenticate(a.ListTutorThreads))
+	a.E.GET("/learnings/tutor/threads/:id", auth.Authenticate(a.GetTutorThread))
+	a.E.DELETE("/learnings/tutor/threads/:id", auth.Authenticate(a.DeleteTutorThread))
+	a.E.POST("/learnings/tutor/threads/:id/messages", auth.Authenticate(generationLimit(a.SendTutorMessage)))
+	a.E.POST("/learnings/tutor/threads/:id/messages/stream", auth.Authenticate(generationLimit(a.StreamTutorMessage)))
+
+	// Flashcard routes (protected)
+	a.E.GET("/learnings/flashcards/due", auth.Authenticate(a.GetDueFlashcards))
+	a.E.POST("/learnings/flashcards/:id/review", auth.Authenticate(a.ReviewFlashcard))
+
+	// Plan template catalog (public browsing)
+	a.E.GET("/catalog/categories", a.ListCategories)
+	a.E.GET("/catalog/templates", a.ListTemplates)
+	a.E.GET("/catalog/templates/:id", a.GetTemplate)
+	a.E.POST("/catalog/templates/:id/start", auth.Authenticate(a.StartTemplate))
+
+	// Admin routes. They expose every user's data, so all of them require the
+	// admin role.
+	admin := a.E.Group("/admin", auth.Authenticate, auth.RequireAdmin)
+	admin.GET("/users", a.ListUsers)
+	admin.GET("/users/:id", a.GetUser)
+	admin.PUT("/users/:id/role", a.UpdateUserRole)
+	admin.POST("/users/:id/suspend", a.SuspendUser)
+	admin.POST("/users/:id/unsuspend", a.UnsuspendUser)
+	admin.GET("/plans", a.AdminListPlans)
+	admin.GET("/plans/:id", a.AdminGetPlan)
+	admin.DELETE("/plans/:id", a.AdminDeletePlan)
+	admin.POST("/plans/:id/regenerate", a.RegeneratePlanContent)
+
+	// Catalog curation (admin)
+	admin.POST("/categories", a.CreateCategory)
+	admin.PUT("/categories/:id", a.UpdateCategory)
+	admin.DELETE("/categories/:id", a.DeleteCategory)
+	admin.GET("/templates", a.AdminListTemplates)
+	admin.GET("/templates/candidates", a.ListTemplateCandidates)
+	admin.POST("/templates", a.CreateTemplate)
+	admin.PUT("/templates/:id", a.UpdateTemplate)
+	admin.PUT("/templates/:id/publish", a.PublishTemplate)
+	admin.DELETE("/templates/:id", a.DeleteTemplate)
+
+	// LLM usage and prompts (admin)
+	admin.GET("/usage/costs", a.GetUsageCosts)
+	admin.GET("/prompts", a.ListPrompts)
+	admin.GET("/prompts/:name/preview", a.PreviewPrompt)
+
+	// generation jobs and quota
+	a.E.GET("/jobs/:id", auth.Authenticate(a.GetJob))
+	a.E.GET("/quota", auth.Authenticate(a.GetQuota))
+
+	a.E.POST("/learnings/validate-goal", auth.Authenticate(generationLimit(a.ValidateGoal)))
 	a.E.DELETE("/learnings/plan/:id", auth.Authenticate(a.DeletePlan))
 
 	//status
@@ -137,25 +273,14 @@ func (a *App) GetStatus(c echo.Context) error {
 // Run the app on it's router
 func (a *App) Run() {
 
-	host := os.Getenv("SYSTEM_HOST")
-	if host == "" {
-		host = "0.0.0.0"
-	}
-
-	port := os.Getenv("PORT")
-
-	if port == "" {
-		port = os.Getenv("SYSTEM_PORT")
-		if port == "" {
-			port = "80"
-		}
-	}
-
-	server := fmt.Sprintf("%s:%s", host, port)
+	config := a.Controller.Config.Server
+	server := fmt.Sprintf("%s:%d", config.Host, config.Port)
 
 	log.Printf(" listening on %s ", server)
 
 	a.E.HideBanner = true
+	a.E.Server.ReadTimeout = config.ReadTimeout
+	a.E.Server.WriteTimeout = config.WriteTimeout
 
 	a.E.Logger.Fatal(a.E.Start(server))
 }
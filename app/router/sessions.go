@@ -0,0 +1,50 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Refresh Token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated; reusing an old one revokes the session
+// @Tags Authentication
+// @Param request body models.RefreshTokenRequest true "Refresh Token"
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.AuthTokens
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /auth/refresh [post]
+func (a *App) RefreshToken(c echo.Context) error {
+	return a.Controller.RefreshToken(c)
+}
+
+// @Summary Logout
+// @Description Revoke the current session
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /auth/logout [post]
+func (a *App) Logout(c echo.Context) error {
+	return a.Controller.Logout(c)
+}
+
+// @Summary Logout All Devices
+// @Description Revoke every session of the current user
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /auth/logout-all [post]
+func (a *App) LogoutAll(c echo.Context) error {
+	return a.Controller.LogoutAll(c)
+}
+
+// @Summary List Sessions
+// @Description List the active sessions (signed-in devices) of the current user
+// @Tags Authentication
+// @Produce json
+// @Success 200 {array} models.Session
+// @Failure 401 {object} models.ErrorResponse
+// @Router /auth/sessions [get]
+func (a *App) ListSessions(c echo.Context) error {
+	return a.Controller.ListSessions(c)
+}
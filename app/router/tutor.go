@@ -0,0 +1,91 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary Create Tutor Thread
+// @Description Start a tutor conversation about a generated day
+// @Tags Tutor
+// @Param day_number path int true "Day Number"
+// @Param week_number path int true "Week Number"
+// @Param plan_id path int true "Plan ID"
+// @Param request body controllers.CreateTutorThreadRequest false "Thread"
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.TutorThread
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id}/tutor/threads [post]
+func (a *App) CreateTutorThread(c echo.Context) error {
+	return a.Controller.CreateTutorThread(c)
+}
+
+// @Summary List Tutor Threads
+// @Description List the tutor conversations about a day, most recent first
+// @Tags Tutor
+// @Param day_number path int true "Day Number"
+// @Param week_number path int true "Week Number"
+// @Param plan_id path int true "Plan ID"
+// @Produce json
+// @Success 200 {array} models.TutorThread
+// @Failure 400 {object} models.ErrorResponse
+// @Router /learnings/daily-content/{day_number}/{week_number}/{plan_id}/tutor/threads [get]
+func (a *App) ListTutorThreads(c echo.Context) error {
+	return a.Controller.ListTutorThreads(c)
+}
+
+// @Summary Get Tutor Thread
+// @Description Retrieve a tutor conversation with its messages
+// @Tags Tutor
+// @Param id path int true "Thread ID"
+// @Produce json
+// @Success 200 {object} models.TutorThread
+// @Failure 404 {object} models.ErrorResponse
+// @Router /learnings/tutor/threads/{id} [get]
+func (a *App) GetTutorThread(c echo.Context) error {
+	return a.Controller.GetTutorThread(c)
+}
+
+// @Summary Delete Tutor Thread
+// @Description Delete a tutor conversation and its messages
+// @Tags Tutor
+// @Param id path int true "Thread ID"
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /learnings/tutor/threads/{id} [delete]
+func (a *App) DeleteTutorThread(c echo.Context) error {
+	return a.Controller.DeleteTutorThread(c)
+}
+
+// @Summary Ask the Tutor
+// @Description Ask a question in a tutor thread. The tutor is grounded in the day's lesson, exercises and the learner's attempts; older turns are summarized to bound the context
+// @Tags Tutor
+// @Param id path int true "Thread ID"
+// @Param request body controllers.TutorMessageRequest true "Question"
+// @Accept json
+// @Produce json
+// @Success 200 {object} controllers.TutorTurnResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /learnings/tutor/threads/{id}/messages [post]
+func (a *App) SendTutorMessage(c echo.Context) error {
+	return a.Controller.SendTutorMessage(c)
+}
+
+// @Summary Ask the Tutor (streaming)
+// @Description Like Ask the Tutor, but the reply is sent as server-sent events: "delta" events with {"text": "..."} to append, then a "done" event with the persisted turn or an "error" event
+// @Tags Tutor
+// @Param id path int true "Thread ID"
+// @Param request body controllers.TutorMessageRequest true "Question"
+// @Accept json
+// @Produce text/event-stream
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse "Rate limit or generation quota exceeded; see Retry-After"
+// @Router /learnings/tutor/threads/{id}/messages/stream [post]
+func (a *App) StreamTutorMessage(c echo.Context) error {
+	return a.Controller.StreamTutorMessage(c)
+}
@@ -0,0 +1,18 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+// @Summary LLM Usage Costs
+// @Description Report LLM token usage and cost by user, day and purpose, priced with the LLM_PRICES table (admin only)
+// @Tags Admin
+// @Param from query string false "First day, YYYY-MM-DD (default 29 days ago)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Produce json
+// @Success 200 {object} usage.CostReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/usage/costs [get]
+func (a *App) GetUsageCosts(c echo.Context) error {
+	return a.Controller.GetUsageCosts(c)
+}
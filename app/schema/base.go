@@ -1,13 +0,0 @@
-package schema
-
-import (
-	"time"
-
-)
-
-// BaseModel is the base model for all models
-type BaseModel struct {
-	ID        int64 		`gorm:"primaryKey"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
-}
\ No newline at end of file
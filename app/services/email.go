@@ -3,8 +3,8 @@ package services
 import (
 	"crypto/tls"
 	"fmt"
-	"os"
 
+	"github.com/surahj/ai-mentor-backend/app/configs"
 	"gopkg.in/gomail.v2"
 )
 
@@ -18,13 +18,13 @@ type GmailService struct {
 	dialer *gomail.Dialer
 }
 
-// NewEmailService creates a new email service provider based on the environment configuration.
-func NewEmailService() (EmailServiceProvider, error) {
-	provider := os.Getenv("EMAIL_PROVIDER")
+// NewEmailService creates the email service provider selected by config.Provider.
+func NewEmailService(config configs.EmailConfig) (EmailServiceProvider, error) {
+	provider := config.Provider
 	switch provider {
 	case "gmail":
-		email := os.Getenv("GMAIL_SENDER_EMAIL")
-		password := os.Getenv("GMAIL_APP_PASSWORD")
+		email := config.GmailSenderEmail
+		password := string(config.GmailAppPassword)
 		if email == "" || password == "" {
 			return nil, fmt.Errorf("GMAIL_SENDER_EMAIL and GMAIL_APP_PASSWORD must be set for gmail provider")
 		}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// Email kinds
+const (
+	EmailVerification  = "verification"
+	EmailResendOTP     = "resend_otp"
+	EmailPasswordReset = "password_reset"
+)
+
+// EmailData is substituted into the localized email templates.
+type EmailData struct {
+	FirstName    string
+	OTP          string
+	ValidMinutes int
+}
+
+type emailMessage struct {
+	subject string
+	body    *template.Template
+}
+
+func message(subject, body string) emailMessage {
+	return emailMessage{subject: subject, body: template.Must(template.New(subject).Parse(body))}
+}
+
+// emailMessages holds the emails by English language name and kind. English
+// is the fallback for languages without translations.
+var emailMessages = map[string]map[string]emailMessage{
+	"English": {
+		EmailVerification:  message("Your AI-Mentor OTP", "Hi {{.FirstName}}, <br><br>Your One-Time Password (OTP) for AI-Mentor is: <strong>{{.OTP}}</strong>.<br><br>This OTP is valid for {{.ValidMinutes}} minutes. Please use it to complete your registration.<br><br>Thanks,<br>The AI-Mentor Team"),
+		EmailResendOTP:     message("Your New AI-Mentor OTP", "Your new AI-Mentor OTP is: {{.OTP}}"),
+		EmailPasswordReset: message("Your Password Reset OTP", "Your password reset OTP is: {{.OTP}}"),
+	},
+	"Spanish": {
+		EmailVerification:  message("Tu código OTP de AI-Mentor", "Hola {{.FirstName}}, <br><br>Tu contraseña de un solo uso (OTP) para AI-Mentor es: <strong>{{.OTP}}</strong>.<br><br>Este código es válido durante {{.ValidMinutes}} minutos. Úsalo para completar tu registro.<br><br>Gracias,<br>El equipo de AI-Mentor"),
+		EmailResendOTP:     message("Tu nuevo código OTP de AI-Mentor", "Tu nuevo código OTP de AI-Mentor es: {{.OTP}}"),
+		EmailPasswordReset: message("Tu código para restablecer la contraseña", "Tu código para restablecer la contraseña es: {{.OTP}}"),
+	},
+	"French": {
+		EmailVerification:  message("Votre code OTP AI-Mentor", "Bonjour {{.FirstName}}, <br><br>Votre mot de passe à usage unique (OTP) pour AI-Mentor est : <strong>{{.OTP}}</strong>.<br><br>Ce code est valable {{.ValidMinutes}} minutes. Utilisez-le pour terminer votre inscription.<br><br>Merci,<br>L'équipe AI-Mentor"),
+		EmailResendOTP:     message("Votre nouveau code OTP AI-Mentor", "Votre nouveau code OTP AI-Mentor est : {{.OTP}}"),
+		EmailPasswordReset: message("Votre code de réinitialisation du mot de passe", "Votre code de réinitialisation du mot de passe est : {{.OTP}}"),
+	},
+	"German": {
+		EmailVerification:  message("Dein AI-Mentor-Einmalcode", "Hallo {{.FirstName}}, <br><br>Dein Einmalpasswort (OTP) für AI-Mentor lautet: <strong>{{.OTP}}</strong>.<br><br>Der Code ist {{.ValidMinutes}} Minuten gültig. Bitte nutze ihn, um deine Registrierung abzuschließen.<br><br>Danke,<br>Dein AI-Mentor-Team"),
+		EmailResendOTP:     message("Dein neuer AI-Mentor-Einmalcode", "Dein neuer AI-Mentor-Einmalcode lautet: {{.OTP}}"),
+		EmailPasswordReset: message("Dein Code zum Zurücksetzen des Passworts", "Dein Code zum Zurücksetzen des Passworts lautet: {{.OTP}}"),
+	},
+	"Portuguese": {
+		EmailVerification:  message("Seu código OTP do AI-Mentor", "Olá {{.FirstName}}, <br><br>Sua senha de uso único (OTP) para o AI-Mentor é: <strong>{{.OTP}}</strong>.<br><br>Este código é válido por {{.ValidMinutes}} minutos. Use-o para concluir seu cadastro.<br><br>Obrigado,<br>Equipe AI-Mentor"),
+		EmailResendOTP:     message("Seu novo código OTP do AI-Mentor", "Seu novo código OTP do AI-Mentor é: {{.OTP}}"),
+		EmailPasswordReset: message("Seu código para redefinir a senha", "Seu código para redefinir a senha é: {{.OTP}}"),
+	},
+}
+
+// RenderEmail returns the subject and HTML body of an email in language, given
+// as an English language name, falling back to English.
+func RenderEmail(kind, language string, data EmailData) (string, string, error) {
+	messages, ok := emailMessages[language]
+	if !ok {
+		messages = emailMessages["English"]
+	}
+	msg, ok := messages[kind]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email '%s'", kind)
+	}
+
+	var body bytes.Buffer
+	if err := msg.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	return msg.subject, body.String(), nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// fakeStreamChunkSize is the number of runes the fake provider sends per streamed delta.
+const fakeStreamChunkSize = 24
+
+//go:embed fixtures/llm/*.json fixtures/llm/*.txt
+var defaultLLMFixtures embed.FS
+
+// FakeLLMProvider is an implementation of LLMProvider that returns canned
+// responses keyed by request purpose. It is deterministic and never leaves the
+// process, which makes it suitable for tests and local development.
+type FakeLLMProvider struct {
+	mu       sync.Mutex
+	fixtures map[string]string
+	// Requests records every request received, in order.
+	Requests []ChatRequest
+}
+
+// NewFakeLLMProvider creates a fake provider seeded with the embedded fixtures.
+// Any <purpose>.json file in dir, or <purpose>.txt for plain text completions,
+// overrides the matching embedded fixture.
+func NewFakeLLMProvider(dir string) (*FakeLLMProvider, error) {
+	p := &FakeLLMProvider{fixtures: map[string]string{}}
+
+	entries, err := defaultLLMFixtures.ReadDir("fixtures/llm")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := defaultLLMFixtures.ReadFile("fixtures/llm/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		p.fixtures[fixturePurpose(entry.Name())] = string(data)
+	}
+
+	if dir == "" {
+		return p, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	texts, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, texts...)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %v", file, err)
+		}
+		p.fixtures[fixturePurpose(filepath.Base(file))] = string(data)
+	}
+
+	return p, nil
+}
+
+// fixturePurpose is the purpose a fixture file answers, its name without extension.
+func fixturePurpose(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// SetFixture replaces the canned response for a purpose.
+func (p *FakeLLMProvider) SetFixture(purpose, content string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fixtures[purpose] = content
+}
+
+// CreateChatCompletion returns the fixture registered for the request purpose.
+func (p *FakeLLMProvider) CreateChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.Requests = append(p.Requests, req)
+
+	purpose := req.Purpose
+	if purpose == "" {
+		purpose = LLMPurposeGeneric
+	}
+	content, ok := p.fixtures[purpose]
+	if !ok {
+		return nil, fmt.Errorf("no fake LLM fixture for purpose '%s'", purpose)
+	}
+
+	promptTokens := 0
+	for _, m := range req.Messages {
+		promptTokens += len(strings.Fields(m.Content))
+	}
+
+	return &ChatResponse{
+		Content:          content,
+		Model:            "fake",
+		PromptTokens:     promptTokens,
+		CompletionTokens: len(strings.Fields(content)),
+	}, nil
+}
+
+// StreamChatCompletion returns the same fixture as CreateChatCompletion,
+// delivered to onDelta in fixed size chunks.
+func (p *FakeLLMProvider) StreamChatCompletion(ctx context.Context, req ChatRequest, onDelta StreamHandler) (*ChatResponse, error) {
+	resp, err := p.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := resp.Content
+	for rest != "" {
+		n, size := 0, 0
+		for n < fakeStreamChunkSize && size < len(rest) {
+			_, w := utf8.DecodeRuneInString(rest[size:])
+			size += w
+			n++
+		}
+		if err := onDelta(rest[:size]); err != nil {
+			return nil, err
+		}
+		rest = rest[size:]
+	}
+
+	return resp, nil
+}
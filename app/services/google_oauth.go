@@ -7,8 +7,8 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
 
+	"github.com/surahj/ai-mentor-backend/app/configs"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	googleoauth2 "google.golang.org/api/oauth2/v2"
@@ -32,10 +32,10 @@ type GoogleOAuthService struct {
 }
 
 // NewGoogleOAuthService creates a new Google OAuth service
-func NewGoogleOAuthService() (*GoogleOAuthService, error) {
-	clientID := os.Getenv("GOOGLE_CLIENT_ID")
-	clientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
-	redirectURL := os.Getenv("GOOGLE_REDIRECT_URL")
+func NewGoogleOAuthService(settings configs.GoogleConfig) (*GoogleOAuthService, error) {
+	clientID := settings.ClientID
+	clientSecret := string(settings.ClientSecret)
+	redirectURL := settings.RedirectURL
 
 	if clientID == "" || clientSecret == "" {
 		return nil, fmt.Errorf("GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET must be set")
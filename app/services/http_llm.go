@@ -0,0 +1,215 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPLLMProvider is an implementation of LLMProvider for self-hosted endpoints
+// that speak the OpenAI compatible /chat/completions protocol (vLLM, Ollama, LiteLLM, ...).
+type HTTPLLMProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewHTTPLLMProvider creates a new OpenAI compatible HTTP provider. When model is
+// set it overrides the model requested by the generators.
+func NewHTTPLLMProvider(baseURL, apiKey, model string) *HTTPLLMProvider {
+	return &HTTPLLMProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: 180 * time.Second},
+	}
+}
+
+type httpChatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []ChatMessage     `json:"messages"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+	Stream         bool              `json:"stream,omitempty"`
+	StreamOptions  map[string]bool   `json:"stream_options,omitempty"`
+}
+
+type httpUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type httpChatStreamChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta ChatMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *httpUsage `json:"usage"`
+}
+
+type httpChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message ChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage httpUsage `json:"usage"`
+}
+
+// CreateChatCompletion posts a chat completion request to the configured endpoint.
+func (p *HTTPLLMProvider) CreateChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	body := httpChatRequest{
+		Model:    p.resolveModel(req.Model),
+		Messages: req.Messages,
+	}
+	if req.JSONMode {
+		body.ResponseFormat = map[string]string{"type": "json_object"}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %v", err)
+	}
+
+	httpReq, err := p.newRequest(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("llm endpoint returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var out httpChatResponse
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat response: %v", err)
+	}
+	if len(out.Choices) == 0 {
+		return nil, errors.New("llm endpoint returned no choices")
+	}
+
+	return &ChatResponse{
+		Content:          out.Choices[0].Message.Content,
+		Model:            out.Model,
+		PromptTokens:     out.Usage.PromptTokens,
+		CompletionTokens: out.Usage.CompletionTokens,
+	}, nil
+}
+
+// StreamChatCompletion posts a streaming chat completion request and reads the
+// server-sent events the endpoint answers with.
+func (p *HTTPLLMProvider) StreamChatCompletion(ctx context.Context, req ChatRequest, onDelta StreamHandler) (*ChatResponse, error) {
+	body := httpChatRequest{
+		Model:         p.resolveModel(req.Model),
+		Messages:      req.Messages,
+		Stream:        true,
+		StreamOptions: map[string]bool{"include_usage": true},
+	}
+	if req.JSONMode {
+		body.ResponseFormat = map[string]string{"type": "json_object"}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %v", err)
+	}
+
+	httpReq, err := p.newRequest(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("llm endpoint returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var content strings.Builder
+	out := &ChatResponse{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk httpChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stream chunk: %v", err)
+		}
+
+		if chunk.Model != "" {
+			out.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			out.PromptTokens = chunk.Usage.PromptTokens
+			out.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		content.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %v", err)
+	}
+
+	out.Content = content.String()
+	return out, nil
+}
+
+func (p *HTTPLLMProvider) resolveModel(requested string) string {
+	if p.model != "" {
+		return p.model
+	}
+	if requested == "" {
+		return LLMModelPrimary
+	}
+	return requested
+}
+
+func (p *HTTPLLMProvider) newRequest(ctx context.Context, payload []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	return req, nil
+}
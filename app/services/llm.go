@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/surahj/ai-mentor-backend/app/configs"
+)
+
+// Chat message roles understood by every LLMProvider.
+const (
+	LLMRoleSystem    = "system"
+	LLMRoleUser      = "user"
+	LLMRoleAssistant = "assistant"
+)
+
+// Model tiers requested by the generators. Providers that talk to a single
+// self-hosted model are free to ignore them.
+const (
+	LLMModelPrimary = openai.GPT4
+	LLMModelFast    = openai.GPT3Dot5Turbo
+)
+
+// Purposes describe what a completion is used for. The fake provider uses them
+// to pick a fixture.
+const (
+	LLMPurposePlan      = "plan"
+	LLMPurposeWeek      = "week"
+	LLMPurposeLesson    = "lesson"
+	LLMPurposeResources = "resources"
+	LLMPurposeExercises = "exercises"
+	LLMPurposeValidate  = "validate"
+	LLMPurposeGrading   = "grading"
+	LLMPurposeTranslate = "translate"
+	LLMPurposePlacement = "placement"
+	LLMPurposeTutor     = "tutor"
+	LLMPurposeSummary   = "summary"
+	LLMPurposeGeneric   = "generic"
+)
+
+// ChatMessage is a single message in a chat completion request.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is a vendor neutral chat completion request.
+type ChatRequest struct {
+	Model    string
+	Purpose  string
+	Messages []ChatMessage
+	// JSONMode asks the provider to constrain the output to a JSON object when supported.
+	JSONMode bool
+}
+
+// ChatResponse is a vendor neutral chat completion response.
+type ChatResponse struct {
+	Content          string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// StreamHandler receives each piece of content as the model produces it.
+// Returning an error aborts the stream.
+type StreamHandler func(delta string) error
+
+// LLMProvider defines the interface for talking to a large language model.
+type LLMProvider interface {
+	CreateChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	// StreamChatCompletion calls onDelta with the content as it is generated and
+	// returns the complete response once the stream ends.
+	StreamChatCompletion(ctx context.Context, req ChatRequest, onDelta StreamHandler) (*ChatResponse, error)
+}
+
+// NewLLMProvider creates the LLM provider selected by config.Provider.
+func NewLLMProvider(config configs.LLMConfig) (LLMProvider, error) {
+	switch config.Provider {
+	case "", "openai":
+		// The key is checked lazily so the service can boot without it.
+		return NewOpenAIProvider(string(config.OpenAIAPIKey)), nil
+	case "http":
+		if config.BaseURL == "" {
+			return nil, fmt.Errorf("LLM_BASE_URL must be set for http provider")
+		}
+		return NewHTTPLLMProvider(config.BaseURL, string(config.APIKey), config.Model), nil
+	case "fake":
+		return NewFakeLLMProvider(config.FixturesDir)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider '%s'", config.Provider)
+	}
+}
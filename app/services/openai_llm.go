@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAIProvider is an implementation of LLMProvider backed by the OpenAI API.
+type OpenAIProvider struct {
+	client *openai.Client
+}
+
+// NewOpenAIProvider creates a new OpenAI provider. An empty key yields a provider
+// whose calls fail, mirroring the previous lazy client initialization.
+func NewOpenAIProvider(apiKey string) *OpenAIProvider {
+	if apiKey == "" {
+		return &OpenAIProvider{}
+	}
+	return &OpenAIProvider{client: openai.NewClient(apiKey)}
+}
+
+// CreateChatCompletion sends a chat completion request to OpenAI.
+func (p *OpenAIProvider) CreateChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if p.client == nil {
+		return nil, errors.New("OPENAI_API_KEY not set")
+	}
+
+	resp, err := p.client.CreateChatCompletion(ctx, toOpenAIRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("openai returned no choices")
+	}
+
+	return &ChatResponse{
+		Content:          resp.Choices[0].Message.Content,
+		Model:            resp.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// StreamChatCompletion streams a chat completion from OpenAI.
+func (p *OpenAIProvider) StreamChatCompletion(ctx context.Context, req ChatRequest, onDelta StreamHandler) (*ChatResponse, error) {
+	if p.client == nil {
+		return nil, errors.New("OPENAI_API_KEY not set")
+	}
+
+	streamReq := toOpenAIRequest(req)
+	streamReq.Stream = true
+	streamReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	stream, err := p.client.CreateChatCompletionStream(ctx, streamReq)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	out := &ChatResponse{}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		out.Model = chunk.Model
+		if chunk.Usage != nil {
+			out.PromptTokens = chunk.Usage.PromptTokens
+			out.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		content.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return nil, err
+		}
+	}
+
+	out.Content = content.String()
+	return out, nil
+}
+
+func toOpenAIRequest(req ChatRequest) openai.ChatCompletionRequest {
+	model := req.Model
+	if model == "" {
+		model = LLMModelPrimary
+	}
+
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, openai.ChatCompletionMessage{Role: m.Role, Content: m.Content})
+	}
+
+	out := openai.ChatCompletionRequest{
+		Model:    model,
+		Messages: messages,
+	}
+	if req.JSONMode {
+		out.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+	return out
+}
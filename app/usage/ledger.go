@@ -0,0 +1,93 @@
+package usage
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/services"
+	"gorm.io/gorm"
+)
+
+// Ledger persists the token usage of LLM calls and reports their cost.
+type Ledger struct {
+	db     *gorm.DB
+	Prices PriceTable
+}
+
+// NewLedger creates a ledger priced from the default table and overrides.
+func NewLedger(db *gorm.DB, overrides map[string]configs.ModelPrice) *Ledger {
+	return &Ledger{db: db, Prices: Prices(overrides)}
+}
+
+// Record stores a usage entry. Failures are logged rather than returned so
+// that bookkeeping never fails a generation.
+func (l *Ledger) Record(entry *models.LLMUsage) {
+	if err := l.db.Create(entry).Error; err != nil {
+		log.Printf("Failed to record LLM usage for %s: %v", entry.Purpose, err)
+	}
+}
+
+// Provider wraps an LLM provider so that every completion it makes is
+// recorded against the given user and plan. Zero IDs are stored as NULL.
+func (l *Ledger) Provider(inner services.LLMProvider, userID, planID int64) services.LLMProvider {
+	if inner == nil {
+		return nil
+	}
+	return &recordingProvider{inner: inner, ledger: l, userID: optionalID(userID), planID: optionalID(planID)}
+}
+
+func optionalID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+type recordingProvider struct {
+	inner  services.LLMProvider
+	ledger *Ledger
+	userID *int64
+	planID *int64
+}
+
+func (p *recordingProvider) CreateChatCompletion(ctx context.Context, req services.ChatRequest) (*services.ChatResponse, error) {
+	start := time.Now()
+	resp, err := p.inner.CreateChatCompletion(ctx, req)
+	p.record(req, resp, err, start, false)
+	return resp, err
+}
+
+func (p *recordingProvider) StreamChatCompletion(ctx context.Context, req services.ChatRequest, onDelta services.StreamHandler) (*services.ChatResponse, error) {
+	start := time.Now()
+	resp, err := p.inner.StreamChatCompletion(ctx, req, onDelta)
+	p.record(req, resp, err, start, true)
+	return resp, err
+}
+
+func (p *recordingProvider) record(req services.ChatRequest, resp *services.ChatResponse, err error, start time.Time, streamed bool) {
+	entry := models.LLMUsage{
+		UserID:    p.userID,
+		PlanID:    p.planID,
+		Purpose:   req.Purpose,
+		Model:     req.Model,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Streamed:  streamed,
+	}
+	if entry.Purpose == "" {
+		entry.Purpose = services.LLMPurposeGeneric
+	}
+	if resp != nil {
+		if resp.Model != "" {
+			entry.Model = resp.Model
+		}
+		entry.PromptTokens = resp.PromptTokens
+		entry.CompletionTokens = resp.CompletionTokens
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	p.ledger.Record(&entry)
+}
@@ -0,0 +1,67 @@
+package usage
+
+import (
+	"strings"
+
+	"github.com/surahj/ai-mentor-backend/app/configs"
+)
+
+// Price is the USD cost of 1,000 tokens of a model.
+type Price struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// PriceTable maps a model name, or a model name prefix, to its price.
+type PriceTable map[string]Price
+
+// defaultPrices apply unless the llm.prices setting overrides them.
+var defaultPrices = PriceTable{
+	"gpt-4":         {Prompt: 0.03, Completion: 0.06},
+	"gpt-4-32k":     {Prompt: 0.06, Completion: 0.12},
+	"gpt-4-turbo":   {Prompt: 0.01, Completion: 0.03},
+	"gpt-4o":        {Prompt: 0.0025, Completion: 0.01},
+	"gpt-4o-mini":   {Prompt: 0.00015, Completion: 0.0006},
+	"gpt-3.5-turbo": {Prompt: 0.0005, Completion: 0.0015},
+	"fake":          {},
+}
+
+// Prices returns the default price table with overrides layered on top.
+func Prices(overrides map[string]configs.ModelPrice) PriceTable {
+	prices := PriceTable{}
+	for model, price := range defaultPrices {
+		prices[model] = price
+	}
+	for model, price := range overrides {
+		prices[model] = Price(price)
+	}
+	return prices
+}
+
+// Lookup finds the price of a model. Versioned names such as gpt-4-0613 fall
+// back to the longest matching prefix.
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+
+	best := ""
+	for name := range t {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t[best], true
+}
+
+// Cost is the USD cost of a completion, and whether the model had a price.
+func (t PriceTable) Cost(model string, promptTokens, completionTokens int) (float64, bool) {
+	price, ok := t.Lookup(model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1000, true
+}
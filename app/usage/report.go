@@ -0,0 +1,140 @@
+package usage
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CostLine aggregates the calls and cost of one group in a report.
+type CostLine struct {
+	Key              string  `json:"key"`
+	Calls            int     `json:"calls"`
+	FailedCalls      int     `json:"failed_calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+func (l *CostLine) add(row usageRow, cost float64) {
+	l.Calls += row.Calls
+	l.FailedCalls += row.FailedCalls
+	l.PromptTokens += row.PromptTokens
+	l.CompletionTokens += row.CompletionTokens
+	l.Cost += cost
+}
+
+// CostReport breaks down the cost of LLM usage between From (inclusive) and To
+// (exclusive) by user, by UTC day and by purpose.
+type CostReport struct {
+	From      time.Time  `json:"from"`
+	To        time.Time  `json:"to"`
+	Total     CostLine   `json:"total"`
+	ByUser    []CostLine `json:"by_user"`
+	ByDay     []CostLine `json:"by_day"`
+	ByPurpose []CostLine `json:"by_purpose"`
+	// UnpricedModels lists models missing from the price table; their
+	// tokens are counted but cost nothing.
+	UnpricedModels []string   `json:"unpriced_models"`
+	Prices         PriceTable `json:"prices"`
+}
+
+type usageRow struct {
+	UserID           *int64
+	Day              time.Time
+	Purpose          string
+	Model            string
+	Calls            int
+	FailedCalls      int
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Report prices the usage recorded in [from, to). Cost is computed from the
+// current price table so that a price change applies to historical usage too.
+func (l *Ledger) Report(from, to time.Time) (*CostReport, error) {
+	var rows []usageRow
+	err := l.db.Raw(`
+		SELECT user_id, date_trunc('day', created_at AT TIME ZONE 'UTC') AS day, purpose, model,
+			COUNT(*) AS calls,
+			COUNT(*) FILTER (WHERE error <> '') AS failed_calls,
+			COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) AS completion_tokens
+		FROM llm_usages
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY 1, 2, 3, 4`, from, to).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CostReport{From: from, To: to, Total: CostLine{Key: "total"}, Prices: l.Prices}
+	byUser := map[string]*CostLine{}
+	byDay := map[string]*CostLine{}
+	byPurpose := map[string]*CostLine{}
+	unpriced := map[string]bool{}
+
+	for _, row := range rows {
+		cost, ok := l.Prices.Cost(row.Model, row.PromptTokens, row.CompletionTokens)
+		if !ok && row.Model != "" {
+			unpriced[row.Model] = true
+		}
+
+		user := "system"
+		if row.UserID != nil {
+			user = formatID(*row.UserID)
+		}
+		report.Total.add(row, cost)
+		line(byUser, user).add(row, cost)
+		line(byDay, row.Day.Format("2006-01-02")).add(row, cost)
+		line(byPurpose, row.Purpose).add(row, cost)
+	}
+
+	report.ByUser = sortedByCost(byUser)
+	report.ByDay = sortedByKey(byDay)
+	report.ByPurpose = sortedByCost(byPurpose)
+	report.UnpricedModels = make([]string, 0, len(unpriced))
+	for model := range unpriced {
+		report.UnpricedModels = append(report.UnpricedModels, model)
+	}
+	sort.Strings(report.UnpricedModels)
+
+	return report, nil
+}
+
+func line(lines map[string]*CostLine, key string) *CostLine {
+	l, ok := lines[key]
+	if !ok {
+		l = &CostLine{Key: key}
+		lines[key] = l
+	}
+	return l
+}
+
+func flatten(lines map[string]*CostLine) []CostLine {
+	out := make([]CostLine, 0, len(lines))
+	for _, l := range lines {
+		out = append(out, *l)
+	}
+	return out
+}
+
+func sortedByCost(lines map[string]*CostLine) []CostLine {
+	out := flatten(lines)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Cost != out[j].Cost {
+			return out[i].Cost > out[j].Cost
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func sortedByKey(lines map[string]*CostLine) []CostLine {
+	out := flatten(lines)
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
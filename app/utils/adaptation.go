@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// Defaults used when a plan's adaptive rules do not state a threshold
+const (
+	defaultRemedialAccuracy    = 0.5
+	defaultAcceleratedAccuracy = 0.85
+	minAttemptsForAdaptation   = 3
+	// a learner completing lessons this many times faster than one per day is moving fast
+	acceleratedPace = 1.5
+	// a learner completing fewer lessons than this fraction of the days elapsed is falling behind
+	remedialPace = 0.5
+)
+
+var percentPattern = regexp.MustCompile(`(\d{1,3}(?:\.\d+)?)\s*%`)
+
+// Keywords are matched as whole words, so that "low" does not match "follow"
+// or "slow" and "high" does not match "highlight".
+var (
+	remedialKeywords    = regexp.MustCompile(`\b(struggl\w*|low|lower|below|fail\w*|remedial|difficult\w*|slow\w*)\b`)
+	acceleratedKeywords = regexp.MustCompile(`\b(excel\w*|high|higher|above|fast\w*|accelerat\w*|advanced|master\w*)\b`)
+	ruleWordBreak       = regexp.MustCompile(`([a-z])([A-Z])|[_-]`)
+)
+
+// AdaptationDecision is the outcome of evaluating a plan's adaptive rules
+type AdaptationDecision struct {
+	Kind   string
+	Reason string
+}
+
+// adaptiveThreshold returns the threshold stated by the first rule whose key
+// or description contains one of the keywords, along with that rule's
+// description. A percentage in a rule belongs to the keyword nearest to it, so
+// a rule stating both thresholds ("repeat below 50%, skip ahead above 90%")
+// gives each kind its own percentage. The fallback is used when the matching
+// rule states no percentage for the keywords.
+func adaptiveThreshold(rules map[string]string, keywords, others *regexp.Regexp, fallback float64) (float64, string) {
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// split keys such as low_score and lowScore into words
+		text := strings.ToLower(ruleWordBreak.ReplaceAllString(k, "$1 $2") + ": " + rules[k])
+		own := keywords.FindAllStringIndex(text, -1)
+		if len(own) == 0 {
+			continue
+		}
+		other := others.FindAllStringIndex(text, -1)
+
+		for _, m := range percentPattern.FindAllStringSubmatchIndex(text, -1) {
+			if nearest(m[0], m[1], own) > nearest(m[0], m[1], other) {
+				continue
+			}
+			if v, err := strconv.ParseFloat(text[m[2]:m[3]], 64); err == nil && v <= 100 {
+				return v / 100, rules[k]
+			}
+		}
+		return fallback, rules[k]
+	}
+	return fallback, ""
+}
+
+// nearest returns the number of characters between text[start:end] and the
+// closest of the matches, or the maximum int when there are none.
+func nearest(start, end int, matches [][]int) int {
+	best := math.MaxInt
+	for _, m := range matches {
+		d := start - m[1]
+		if m[0] >= end {
+			d = m[0] - end
+		}
+		if d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// EvaluateAdaptation applies a plan's adaptive rules to the learner's recent
+// exercise scores and completion pacing. It returns nil when the upcoming
+// weeks should be kept as they are.
+func EvaluateAdaptation(plan models.CompleteLearningPlan, planCreatedAt time.Time, progress models.ProgressSnapshot, now time.Time) *AdaptationDecision {
+	remedialAt, remedialRule := adaptiveThreshold(plan.AdaptiveRules, remedialKeywords, acceleratedKeywords, defaultRemedialAccuracy)
+	acceleratedAt, acceleratedRule := adaptiveThreshold(plan.AdaptiveRules, acceleratedKeywords, remedialKeywords, defaultAcceleratedAccuracy)
+
+	daysElapsed := int(math.Ceil(now.Sub(planCreatedAt).Hours() / 24))
+	if daysElapsed < 1 {
+		daysElapsed = 1
+	}
+	pace := float64(progress.LessonsCompleted) / float64(daysElapsed)
+
+	withRule := func(reason, rule string) string {
+		if rule == "" {
+			return reason
+		}
+		return reason + " (rule: " + rule + ")"
+	}
+
+	if progress.ExerciseAttempts >= minAttemptsForAdaptation && progress.RecentAccuracy != nil {
+		accuracy := *progress.RecentAccuracy
+		if accuracy < remedialAt {
+			return &AdaptationDecision{
+				Kind: models.AdaptationRemedial,
+				Reason: withRule(fmt.Sprintf("Exercise accuracy in week %d is %.0f%%, below the %.0f%% threshold",
+					progress.RecentWeekNumber, accuracy*100, remedialAt*100), remedialRule),
+			}
+		}
+		if accuracy >= acceleratedAt && pace >= acceleratedPace {
+			return &AdaptationDecision{
+				Kind: models.AdaptationAccelerated,
+				Reason: withRule(fmt.Sprintf("Exercise accuracy in week %d is %.0f%% and %d lessons were completed in %d days",
+					progress.RecentWeekNumber, accuracy*100, progress.LessonsCompleted, daysElapsed), acceleratedRule),
+			}
+		}
+	}
+
+	if daysElapsed >= 7 && progress.LessonsStarted > 0 && pace < remedialPace {
+		return &AdaptationDecision{
+			Kind: models.AdaptationRemedial,
+			Reason: withRule(fmt.Sprintf("Only %d lessons were completed in %d days", progress.LessonsCompleted, daysElapsed),
+				remedialRule),
+		}
+	}
+
+	return nil
+}
+
+// AdaptationGuidance turns an adaptation flag into instructions for the generator.
+func AdaptationGuidance(flag *models.ContentAdaptationFlag) string {
+	if flag == nil {
+		return ""
+	}
+	switch flag.Kind {
+	case models.AdaptationRemedial:
+		return "The learner is struggling (" + flag.Reason + "). Make this a remedial week: revisit the previous concepts, slow the pace, use simpler examples and add more guided practice before introducing new material."
+	case models.AdaptationAccelerated:
+		return "The learner is progressing quickly (" + flag.Reason + "). Make this an accelerated week: skip basic repetition, cover more advanced material and add challenging exercises."
+	case models.AdaptationManual:
+		return "This week is being regenerated on request (" + flag.Reason + "). Produce fresh content for the same theme."
+	default:
+		return flag.Reason
+	}
+}
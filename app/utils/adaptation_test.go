@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+func TestAdaptiveThreshold(t *testing.T) {
+	tests := []struct {
+		name            string
+		rules           map[string]string
+		wantRemedial    float64
+		wantAccelerated float64
+	}{
+		{
+			name:            "no rules",
+			wantRemedial:    defaultRemedialAccuracy,
+			wantAccelerated: defaultAcceleratedAccuracy,
+		},
+		{
+			name:            "one rule per kind",
+			rules:           map[string]string{"low_score": "Repeat the week when the score is under 40%", "high_score": "Skip ahead at 95%"},
+			wantRemedial:    0.40,
+			wantAccelerated: 0.95,
+		},
+		{
+			name:            "camel case keys",
+			rules:           map[string]string{"lowScore": "Repeat at 45%"},
+			wantRemedial:    0.45,
+			wantAccelerated: defaultAcceleratedAccuracy,
+		},
+		{
+			name:            "both thresholds in one rule",
+			rules:           map[string]string{"pacing": "If accuracy is high (above 90%) move faster; if it falls below 55%, revisit the material"},
+			wantRemedial:    0.55,
+			wantAccelerated: 0.90,
+		},
+		{
+			name:            "remedial stated first",
+			rules:           map[string]string{"scores": "Below 60% repeat the week, above 80% add challenges"},
+			wantRemedial:    0.60,
+			wantAccelerated: 0.80,
+		},
+		{
+			name:            "keyword without a percentage",
+			rules:           map[string]string{"struggling": "Add more guided practice"},
+			wantRemedial:    defaultRemedialAccuracy,
+			wantAccelerated: defaultAcceleratedAccuracy,
+		},
+		{
+			name: "keywords inside other words",
+			rules: map[string]string{
+				"review": "Follow up with a review at 70% and allow extra time",
+				"notes":  "Highlight the key concepts in 30% of the lessons",
+			},
+			wantRemedial:    defaultRemedialAccuracy,
+			wantAccelerated: defaultAcceleratedAccuracy,
+		},
+		{
+			name:            "percentage above 100",
+			rules:           map[string]string{"low": "Repeat below 150%"},
+			wantRemedial:    defaultRemedialAccuracy,
+			wantAccelerated: defaultAcceleratedAccuracy,
+		},
+		{
+			name:            "decimal percentage",
+			rules:           map[string]string{"remedial": "Revisit when accuracy is 62.5 % or less"},
+			wantRemedial:    0.625,
+			wantAccelerated: defaultAcceleratedAccuracy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remedial, _ := adaptiveThreshold(tt.rules, remedialKeywords, acceleratedKeywords, defaultRemedialAccuracy)
+			if remedial != tt.wantRemedial {
+				t.Errorf("remedial threshold = %v, want %v", remedial, tt.wantRemedial)
+			}
+			accelerated, _ := adaptiveThreshold(tt.rules, acceleratedKeywords, remedialKeywords, defaultAcceleratedAccuracy)
+			if accelerated != tt.wantAccelerated {
+				t.Errorf("accelerated threshold = %v, want %v", accelerated, tt.wantAccelerated)
+			}
+		})
+	}
+}
+
+func TestAdaptiveThresholdReturnsRule(t *testing.T) {
+	rules := map[string]string{"high": "Skip ahead at 95%", "low": "Repeat below 40%"}
+	_, rule := adaptiveThreshold(rules, remedialKeywords, acceleratedKeywords, defaultRemedialAccuracy)
+	if rule != "Repeat below 40%" {
+		t.Errorf("rule = %q", rule)
+	}
+}
+
+func TestEvaluateAdaptation(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	accuracy := func(v float64) *float64 { return &v }
+	plan := models.CompleteLearningPlan{AdaptiveRules: map[string]string{"pacing": "Repeat below 60%, accelerate above 80%"}}
+
+	tests := []struct {
+		name     string
+		created  time.Time
+		progress models.ProgressSnapshot
+		want     string
+	}{
+		{
+			name:     "too few attempts",
+			created:  now.AddDate(0, 0, -2),
+			progress: models.ProgressSnapshot{ExerciseAttempts: 2, RecentAccuracy: accuracy(0.1), LessonsStarted: 1},
+		},
+		{
+			name:     "low accuracy",
+			created:  now.AddDate(0, 0, -2),
+			progress: models.ProgressSnapshot{ExerciseAttempts: 5, RecentAccuracy: accuracy(0.55), LessonsStarted: 2, LessonsCompleted: 2},
+			want:     models.AdaptationRemedial,
+		},
+		{
+			name:     "on track",
+			created:  now.AddDate(0, 0, -2),
+			progress: models.ProgressSnapshot{ExerciseAttempts: 5, RecentAccuracy: accuracy(0.7), LessonsStarted: 2, LessonsCompleted: 2},
+		},
+		{
+			name:     "accurate and fast",
+			created:  now.AddDate(0, 0, -2),
+			progress: models.ProgressSnapshot{ExerciseAttempts: 5, RecentAccuracy: accuracy(0.85), LessonsStarted: 4, LessonsCompleted: 4},
+			want:     models.AdaptationAccelerated,
+		},
+		{
+			name:     "accurate but slow",
+			created:  now.AddDate(0, 0, -2),
+			progress: models.ProgressSnapshot{ExerciseAttempts: 5, RecentAccuracy: accuracy(0.85), LessonsStarted: 2, LessonsCompleted: 2},
+		},
+		{
+			name:     "falling behind",
+			created:  now.AddDate(0, 0, -10),
+			progress: models.ProgressSnapshot{LessonsStarted: 3, LessonsCompleted: 2},
+			want:     models.AdaptationRemedial,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := EvaluateAdaptation(plan, tt.created, tt.progress, now)
+			got := ""
+			if decision != nil {
+				got = decision.Kind
+			}
+			if got != tt.want {
+				t.Errorf("decision = %+v, want %q", decision, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/surahj/ai-mentor-backend/app/configs"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -19,21 +23,37 @@ func CheckPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
-func GenerateJWT(userID int64) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", errors.New("JWT_SECRET is not set")
-	}
-
-	expiry := os.Getenv("JWT_EXPIRY")
-	if expiry == "" {
-		return "", errors.New("JWT_EXPIRY is not set")
+// GenerateJWT issues a short-lived access token bound to a session.
+func GenerateJWT(config configs.JWTConfig, userID, sessionID int64) (string, time.Time, error) {
+	if config.Secret == "" {
+		return "", time.Time{}, errors.New("JWT secret is not configured")
 	}
+	expiresAt := time.Now().Add(config.AccessTTL)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"sid":     sessionID,
+		"exp":     expiresAt.Unix(),
 	})
 
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	signed, err := token.SignedString([]byte(config.Secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// GenerateRandomToken returns a random opaque token, such as a refresh token.
+func GenerateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 of a token for storage and lookup.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
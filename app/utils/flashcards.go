@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// SM-2 parameters
+const (
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+	// MinRecallQuality is the lowest review quality counted as recalled
+	MinRecallQuality = 3
+	MaxReviewQuality = 5
+)
+
+const clozeBlank = "_____"
+
+// clozeStopwords are never blanked out of a key point
+var clozeStopwords = map[string]bool{
+	"about": true, "after": true, "also": true, "because": true, "before": true, "being": true,
+	"between": true, "both": true, "each": true, "from": true, "have": true, "into": true,
+	"more": true, "most": true, "only": true, "other": true, "should": true, "some": true,
+	"such": true, "than": true, "that": true, "their": true, "them": true, "then": true,
+	"there": true, "these": true, "they": true, "this": true, "those": true, "through": true,
+	"used": true, "using": true, "what": true, "when": true, "where": true, "which": true,
+	"while": true, "will": true, "with": true, "without": true, "your": true,
+}
+
+// FlashcardsForDay derives flashcards from a day's key points and the
+// exercises the learner has attempted, given by index. Key points become
+// cloze cards with their most specific word blanked out; exercises become
+// question and answer cards, since their answers are revealed once
+// submitted. New cards are first due the day after the lesson. Only the
+// source fields and Front/Back are set from the content, so the result can be
+// upserted over existing cards.
+func FlashcardsForDay(daily models.DailyContent, attempted []int, now time.Time) []models.Flashcard {
+	card := func(source string, index int, front, back string) models.Flashcard {
+		return models.Flashcard{
+			DailyContentID: daily.ID,
+			Source:         source,
+			SourceIndex:    index,
+			PlanID:         daily.PlanID,
+			UserID:         daily.UserID,
+			WeekNumber:     daily.WeekNumber,
+			DayNumber:      daily.DayNumber,
+			Front:          front,
+			Back:           back,
+			EaseFactor:     initialEaseFactor,
+			DueAt:          now.AddDate(0, 0, 1),
+		}
+	}
+
+	var cards []models.Flashcard
+
+	var lesson models.LessonContent
+	if len(daily.Content) > 0 && json.Unmarshal(daily.Content, &lesson) == nil {
+		for i, point := range lesson.KeyPoints {
+			point = strings.TrimSpace(point)
+			if point == "" {
+				continue
+			}
+			front := clozeDeletion(point)
+			if front == point {
+				front = fmt.Sprintf("Recall key point %d of this lesson", i+1)
+			}
+			if lesson.Title != "" {
+				front = lesson.Title + ": " + front
+			}
+			cards = append(cards, card(models.FlashcardSourceKeyPoint, i, front, point))
+		}
+	}
+
+	var exercises []models.Exercise
+	if len(daily.Exercises) > 0 && json.Unmarshal(daily.Exercises, &exercises) == nil {
+		for _, i := range attempted {
+			if i < 0 || i >= len(exercises) {
+				continue
+			}
+			e := exercises[i]
+			if strings.TrimSpace(e.Question) == "" || strings.TrimSpace(e.Answer) == "" {
+				continue
+			}
+			front := e.Question
+			for j, option := range e.Options {
+				front += fmt.Sprintf("\n%c) %s", 'a'+rune(j%26), option)
+			}
+			back := e.Answer
+			if e.Explanation != "" {
+				back += "\n\n" + e.Explanation
+			}
+			cards = append(cards, card(models.FlashcardSourceExercise, i, front, back))
+		}
+	}
+
+	return cards
+}
+
+// clozeDeletion blanks out the longest word of text that is not a stopword,
+// or returns text unchanged when no word qualifies.
+func clozeDeletion(text string) string {
+	words := strings.Fields(text)
+	best, bestCore := -1, ""
+	for i, w := range words {
+		core := strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		n := utf8.RuneCountInString(core)
+		if n < 4 || clozeStopwords[strings.ToLower(core)] {
+			continue
+		}
+		if n > utf8.RuneCountInString(bestCore) {
+			best, bestCore = i, core
+		}
+	}
+	if best < 0 {
+		return text
+	}
+	words[best] = strings.Replace(words[best], bestCore, clozeBlank, 1)
+	return strings.Join(words, " ")
+}
+
+// ScheduleReview applies an SM-2 review of the given quality (0-5) to card.
+// A quality below MinRecallQuality restarts the card's repetitions without
+// changing its ease factor and counts as a lapse if the card had been
+// learned; otherwise the interval grows from one day to six and then by the
+// ease factor, which is adjusted by the quality.
+func ScheduleReview(card *models.Flashcard, quality int, now time.Time) {
+	if card.EaseFactor == 0 {
+		card.EaseFactor = initialEaseFactor
+	}
+
+	if quality < MinRecallQuality {
+		if card.Repetitions > 0 {
+			card.Lapses++
+		}
+		card.Repetitions = 0
+		card.IntervalDays = 1
+	} else {
+		switch card.Repetitions {
+		case 0:
+			card.IntervalDays = 1
+		case 1:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+		card.Repetitions++
+
+		q := float64(MaxReviewQuality - quality)
+		card.EaseFactor += 0.1 - q*(0.08+q*0.02)
+		if card.EaseFactor < minEaseFactor {
+			card.EaseFactor = minEaseFactor
+		}
+	}
+
+	card.DueAt = now.AddDate(0, 0, card.IntervalDays)
+	card.LastReviewedAt = &now
+}
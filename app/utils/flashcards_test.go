@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+func TestClozeDeletion(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "longest word", text: "JSX compiles to React.createElement", want: "JSX compiles to _____"},
+		{name: "punctuation kept", text: "Goroutines are cheap.", want: "_____ are cheap."},
+		{name: "first of equal length", text: "Maps and sets", want: "_____ and sets"},
+		{name: "stopwords skipped", text: "Use them with care", want: "Use them with _____"},
+		{name: "no word qualifies", text: "Go is fun", want: "Go is fun"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clozeDeletion(tt.text); got != tt.want {
+				t.Errorf("clozeDeletion(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleReview(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	card := models.Flashcard{EaseFactor: initialEaseFactor}
+
+	steps := []struct {
+		quality      int
+		wantInterval int
+		wantReps     int
+		wantLapses   int
+		wantEase     float64
+	}{
+		{quality: 4, wantInterval: 1, wantReps: 1, wantEase: 2.5},
+		{quality: 5, wantInterval: 6, wantReps: 2, wantEase: 2.6},
+		{quality: 3, wantInterval: 16, wantReps: 3, wantEase: 2.46},
+		// a lapse restarts the repetitions and keeps the ease factor
+		{quality: 1, wantInterval: 1, wantReps: 0, wantLapses: 1, wantEase: 2.46},
+		// forgetting a card that was never learned is not a lapse
+		{quality: 0, wantInterval: 1, wantReps: 0, wantLapses: 1, wantEase: 2.46},
+		{quality: 4, wantInterval: 1, wantReps: 1, wantLapses: 1, wantEase: 2.46},
+	}
+	for i, s := range steps {
+		ScheduleReview(&card, s.quality, now)
+		if card.IntervalDays != s.wantInterval || card.Repetitions != s.wantReps || card.Lapses != s.wantLapses {
+			t.Fatalf("review %d (quality %d): interval %d, repetitions %d, lapses %d; want %d, %d, %d",
+				i+1, s.quality, card.IntervalDays, card.Repetitions, card.Lapses, s.wantInterval, s.wantReps, s.wantLapses)
+		}
+		if math.Abs(card.EaseFactor-s.wantEase) > 1e-9 {
+			t.Fatalf("review %d (quality %d): ease factor %v, want %v", i+1, s.quality, card.EaseFactor, s.wantEase)
+		}
+		if want := now.AddDate(0, 0, s.wantInterval); !card.DueAt.Equal(want) {
+			t.Fatalf("review %d: due %v, want %v", i+1, card.DueAt, want)
+		}
+	}
+	if card.LastReviewedAt == nil || !card.LastReviewedAt.Equal(now) {
+		t.Errorf("last reviewed = %v, want %v", card.LastReviewedAt, now)
+	}
+}
+
+func TestScheduleReviewEaseFactorFloor(t *testing.T) {
+	card := models.Flashcard{EaseFactor: 1.35}
+	ScheduleReview(&card, MinRecallQuality, time.Now())
+	if card.EaseFactor != minEaseFactor {
+		t.Errorf("ease factor = %v, want %v", card.EaseFactor, minEaseFactor)
+	}
+
+	unset := models.Flashcard{}
+	ScheduleReview(&unset, 0, time.Now())
+	if unset.EaseFactor != initialEaseFactor {
+		t.Errorf("ease factor of a new card = %v, want %v", unset.EaseFactor, initialEaseFactor)
+	}
+}
+
+func TestFlashcardsForDayOnlyAttemptedExercises(t *testing.T) {
+	lesson, _ := json.Marshal(models.LessonContent{Title: "Go", KeyPoints: []string{"Goroutines are cheap."}})
+	exercises, _ := json.Marshal([]models.Exercise{
+		{Question: "What starts a goroutine?", Answer: "go"},
+		{Question: "What closes a channel?", Answer: "close"},
+		{Question: "What waits for goroutines?", Answer: "sync.WaitGroup"},
+	})
+	daily := models.DailyContent{Content: lesson, Exercises: exercises}
+
+	cards := FlashcardsForDay(daily, []int{2, 0, 7}, time.Now())
+
+	var got []string
+	for _, card := range cards {
+		got = append(got, card.Source+":"+card.Back)
+	}
+	want := []string{
+		models.FlashcardSourceKeyPoint + ":Goroutines are cheap.",
+		models.FlashcardSourceExercise + ":sync.WaitGroup",
+		models.FlashcardSourceExercise + ":go",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("cards = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("card %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+// Grading modes for free text answers
+const (
+	GradingModeExact      = "exact"
+	GradingModeNormalized = "normalized"
+	GradingModeLLM        = "llm"
+)
+
+// GradeResult is the outcome of grading a single answer
+type GradeResult struct {
+	Correct  bool    `json:"correct"`
+	Score    float64 `json:"score"` // 0..1
+	Feedback string  `json:"feedback"`
+	Mode     string  `json:"mode"`
+}
+
+// NormalizeAnswer lowercases s, drops punctuation and collapses whitespace.
+func NormalizeAnswer(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// GradeAnswer grades a learner's answer to an exercise. Multiple choice items
+// accept the option text or its letter ("b", "B)"); free text items are graded
+// using the given mode.
+func GradeAnswer(llm LLM, exercise models.Exercise, answer string, mode string) (GradeResult, error) {
+	if len(exercise.Options) > 0 {
+		expected := resolveOption(exercise.Options, exercise.Answer)
+		given := resolveOption(exercise.Options, answer)
+		return binaryResult(NormalizeAnswer(given) != "" && NormalizeAnswer(given) == NormalizeAnswer(expected), GradingModeNormalized), nil
+	}
+
+	switch mode {
+	case GradingModeExact:
+		return binaryResult(strings.TrimSpace(answer) == strings.TrimSpace(exercise.Answer), mode), nil
+	case GradingModeLLM:
+		return JudgeFreeTextAnswer(llm, exercise, answer)
+	default:
+		return binaryResult(NormalizeAnswer(answer) != "" && NormalizeAnswer(answer) == NormalizeAnswer(exercise.Answer), GradingModeNormalized), nil
+	}
+}
+
+// GradePrompt is the data rendered into the grade_answer prompt.
+type GradePrompt struct {
+	Question        string
+	ReferenceAnswer string
+	Answer          string
+}
+
+// JudgeFreeTextAnswer asks the LLM whether a free text answer is correct.
+func JudgeFreeTextAnswer(llm LLM, exercise models.Exercise, answer string) (GradeResult, error) {
+	prompt, err := prompts.Default.Render(prompts.GradeAnswer, GradePrompt{
+		Question:        exercise.Question,
+		ReferenceAnswer: exercise.Answer,
+		Answer:          answer,
+	})
+	if err != nil {
+		return GradeResult{}, err
+	}
+
+	var verdict struct {
+		Correct  bool    `json:"correct"`
+		Score    float64 `json:"score"`
+		Feedback string  `json:"feedback"`
+	}
+	if _, err := GenerateJSON(llm, JSONRequest{
+		Purpose:  services.LLMPurposeGrading,
+		Model:    services.LLMModelFast,
+		System:   prompt.System,
+		Prompt:   prompt.User,
+		JSONMode: true,
+		Target:   &verdict,
+	}); err != nil {
+		return GradeResult{}, err
+	}
+
+	score := verdict.Score
+	if score < 0 {
+		score = 0
+	}
+	if score > 1 {
+		score = 1
+	}
+
+	return GradeResult{
+		Correct:  verdict.Correct,
+		Score:    score,
+		Feedback: verdict.Feedback,
+		Mode:     GradingModeLLM,
+	}, nil
+}
+
+// AnswerInOptions reports whether an answer names one of the options, either
+// by its text or by its letter or number.
+func AnswerInOptions(options []string, answer string) bool {
+	return containsNormalized(options, resolveOption(options, answer))
+}
+
+// resolveOption maps an option letter ("a", "B", "c)") or 1-based number to the
+// option text, unless the answer already is one of the options.
+func resolveOption(options []string, answer string) string {
+	if containsNormalized(options, answer) {
+		return answer
+	}
+
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(answer), ").:"))
+	if len(trimmed) == 1 {
+		idx := int(unicode.ToLower(rune(trimmed[0])) - 'a')
+		if idx >= 0 && idx < len(options) {
+			return options[idx]
+		}
+	}
+	if n, err := strconv.Atoi(trimmed); err == nil && n >= 1 && n <= len(options) {
+		return options[n-1]
+	}
+	return answer
+}
+
+// TranslateOption maps a multiple choice answer given as one of the options
+// in from to the option at the same index in to, for answers to the original
+// of a translated exercise. Other answers are returned unchanged.
+func TranslateOption(from, to []string, answer string) string {
+	if len(from) != len(to) || containsNormalized(to, answer) {
+		return answer
+	}
+	for i, option := range from {
+		if NormalizeAnswer(option) == NormalizeAnswer(answer) {
+			return to[i]
+		}
+	}
+	return answer
+}
+
+func containsNormalized(options []string, answer string) bool {
+	for _, o := range options {
+		if NormalizeAnswer(o) == NormalizeAnswer(answer) {
+			return true
+		}
+	}
+	return false
+}
+
+func binaryResult(correct bool, mode string) GradeResult {
+	if correct {
+		return GradeResult{Correct: true, Score: 1, Feedback: "Correct!", Mode: mode}
+	}
+	return GradeResult{Correct: false, Score: 0, Feedback: "Not quite.", Mode: mode}
+}
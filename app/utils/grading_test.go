@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+func TestResolveOption(t *testing.T) {
+	options := []string{"go build", "go run", "go vet", "go fmt"}
+
+	tests := []struct {
+		answer string
+		want   string
+	}{
+		{"go run", "go run"},
+		{"Go Run!", "Go Run!"},
+		{"b", "go run"},
+		{"B", "go run"},
+		{"b)", "go run"},
+		{" c. ", "go vet"},
+		{"D:", "go fmt"},
+		{"2", "go run"},
+		{"4)", "go fmt"},
+		{"e", "e"},
+		{"0", "0"},
+		{"5", "5"},
+		{"go test", "go test"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := resolveOption(options, tt.answer); got != tt.want {
+			t.Errorf("resolveOption(%q) = %q, want %q", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestResolveOptionPrefersOptionText(t *testing.T) {
+	// an option that is itself a single letter is not read as a position
+	options := []string{"b", "a"}
+	if got := resolveOption(options, "a"); got != "a" {
+		t.Errorf("resolveOption = %q, want %q", got, "a")
+	}
+}
+
+func TestTranslateOption(t *testing.T) {
+	original := []string{"True", "False", "It depends"}
+	translated := []string{"Verdadero", "Falso", "Depende"}
+
+	tests := []struct {
+		answer string
+		want   string
+	}{
+		{"False", "Falso"},
+		{" it DEPENDS ", "Depende"},
+		{"Verdadero", "Verdadero"},
+		{"b", "b"},
+		{"Maybe", "Maybe"},
+	}
+	for _, tt := range tests {
+		if got := TranslateOption(original, translated, tt.answer); got != tt.want {
+			t.Errorf("TranslateOption(%q) = %q, want %q", tt.answer, got, tt.want)
+		}
+	}
+
+	if got := TranslateOption(original, translated[:2], "False"); got != "False" {
+		t.Errorf("options of different lengths mapped %q", got)
+	}
+}
+
+func TestGradeAnswer(t *testing.T) {
+	choice := models.Exercise{Question: "Which command runs a program?", Options: []string{"go build", "go run"}, Answer: "go run"}
+	letterAnswer := models.Exercise{Question: "Which command runs a program?", Options: []string{"go build", "go run"}, Answer: "B"}
+	free := models.Exercise{Question: "Where does execution start?", Answer: "The main function."}
+
+	tests := []struct {
+		name     string
+		exercise models.Exercise
+		answer   string
+		mode     string
+		want     bool
+		wantMode string
+	}{
+		{"option text", choice, "go run", GradingModeExact, true, GradingModeNormalized},
+		{"option letter", choice, "b)", GradingModeExact, true, GradingModeNormalized},
+		{"option number", choice, "2", GradingModeNormalized, true, GradingModeNormalized},
+		{"wrong option", choice, "a", GradingModeNormalized, false, GradingModeNormalized},
+		{"empty option", choice, "", GradingModeNormalized, false, GradingModeNormalized},
+		{"reference given as letter", letterAnswer, "go run", GradingModeNormalized, true, GradingModeNormalized},
+		{"normalized", free, "the MAIN function", GradingModeNormalized, true, GradingModeNormalized},
+		{"normalized by default", free, "the main function", "", true, GradingModeNormalized},
+		{"normalized wrong", free, "init", GradingModeNormalized, false, GradingModeNormalized},
+		{"normalized empty", free, " ... ", GradingModeNormalized, false, GradingModeNormalized},
+		{"exact", free, " The main function. ", GradingModeExact, true, GradingModeExact},
+		{"exact case", free, "the main function.", GradingModeExact, false, GradingModeExact},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GradeAnswer(LLM{}, tt.exercise, tt.answer, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Correct != tt.want || got.Mode != tt.wantMode {
+				t.Errorf("GradeAnswer = %+v, want correct %v in mode %s", got, tt.want, tt.wantMode)
+			}
+			if (got.Score == 1) != tt.want {
+				t.Errorf("score = %v for correct = %v", got.Score, got.Correct)
+			}
+		})
+	}
+}
+
+func TestGradeAnswerLLM(t *testing.T) {
+	llm, err := services.NewFakeLLMProvider("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm.SetFixture(services.LLMPurposeGrading, `{"correct": false, "score": 1.7, "feedback": "Close."}`)
+
+	free := models.Exercise{Question: "Where does execution start?", Answer: "The main function."}
+	got, err := GradeAnswer(LLM{LLMProvider: llm}, free, "in func main", GradingModeLLM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Correct || got.Score != 1 || got.Feedback != "Close." || got.Mode != GradingModeLLM {
+		t.Errorf("GradeAnswer = %+v", got)
+	}
+	if len(llm.Requests) != 1 || llm.Requests[0].Purpose != services.LLMPurposeGrading {
+		t.Errorf("LLM requests = %+v", llm.Requests)
+	}
+
+	// options are graded locally even in llm mode
+	choice := models.Exercise{Options: []string{"yes", "no"}, Answer: "yes"}
+	if got, err := GradeAnswer(LLM{LLMProvider: llm}, choice, "a", GradingModeLLM); err != nil || !got.Correct {
+		t.Errorf("GradeAnswer = %+v, %v", got, err)
+	}
+	if len(llm.Requests) != 1 {
+		t.Errorf("multiple choice answer was sent to the LLM")
+	}
+}
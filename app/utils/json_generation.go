@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+// DefaultJSONRepairAttempts is the llm.json_max_retries default, for callers
+// without a configuration.
+const DefaultJSONRepairAttempts = 2
+
+// LLM is the provider the generators call, with the settings of the
+// generation itself.
+type LLM struct {
+	services.LLMProvider
+	// JSONRepairAttempts is the number of times a response that fails
+	// validation is sent back to the model for correction.
+	JSONRepairAttempts int
+}
+
+// ErrInvalidLLMOutput is returned when the model keeps producing output that
+// does not match the requested schema after every repair attempt.
+var ErrInvalidLLMOutput = errors.New("LLM output did not match the expected schema")
+
+// JSONRequest describes a completion whose output must match the schema of Target.
+type JSONRequest struct {
+	Purpose string
+	Model   string
+	System  string
+	Prompt  string
+	// JSONMode asks the provider for a JSON object response; only valid for object targets.
+	JSONMode bool
+	// Target is a pointer to the value the validated JSON is decoded into.
+	Target interface{}
+	// OnDelta, when set, streams the first attempt and receives the raw output as
+	// it is produced. Repair attempts are not streamed.
+	OnDelta services.StreamHandler
+}
+
+// GenerateJSON runs a completion, strips markdown fences and surrounding prose,
+// validates the result against the JSON Schema derived from req.Target and, on
+// failure, feeds the validation errors back to the model until it produces a
+// valid document or the retry limit is reached. It returns the cleaned JSON.
+func GenerateJSON(llm LLM, req JSONRequest) ([]byte, error) {
+	if llm.LLMProvider == nil {
+		return nil, errors.New("LLM provider not configured")
+	}
+
+	schema := SchemaFor(req.Target)
+	messages := []services.ChatMessage{
+		{Role: services.LLMRoleSystem, Content: req.System},
+		{Role: services.LLMRoleUser, Content: req.Prompt +
+			"\n\nRespond with JSON only, without markdown fences or commentary, matching this JSON Schema:\n" + schema.String()},
+	}
+
+	attempts := llm.JSONRepairAttempts
+	var lastErrs []string
+	for attempt := 0; attempt <= attempts; attempt++ {
+		chatReq := services.ChatRequest{
+			Model:    req.Model,
+			Purpose:  req.Purpose,
+			Messages: messages,
+			JSONMode: req.JSONMode,
+		}
+		var resp *services.ChatResponse
+		var err error
+		if attempt == 0 && req.OnDelta != nil {
+			resp, err = llm.StreamChatCompletion(context.Background(), chatReq, req.OnDelta)
+		} else {
+			resp, err = llm.CreateChatCompletion(context.Background(), chatReq)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cleaned, errs := parseAndValidate(resp.Content, schema)
+		if len(errs) == 0 {
+			if err := json.Unmarshal(cleaned, req.Target); err != nil {
+				return nil, fmt.Errorf("failed to parse LLM response: %w", err)
+			}
+			return cleaned, nil
+		}
+
+		lastErrs = errs
+		log.Printf("LLM %s output failed validation (attempt %d/%d): %s", req.Purpose, attempt+1, attempts+1, strings.Join(errs, "; "))
+
+		messages = append(messages,
+			services.ChatMessage{Role: services.LLMRoleAssistant, Content: resp.Content},
+			services.ChatMessage{Role: services.LLMRoleUser, Content: "Your previous response was not valid:\n- " +
+				strings.Join(errs, "\n- ") +
+				"\nReturn the corrected JSON only."},
+		)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrInvalidLLMOutput, strings.Join(lastErrs, "; "))
+}
+
+func parseAndValidate(content string, schema *JSONSchema) ([]byte, []string) {
+	cleaned, err := ExtractJSON(content)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(cleaned, &data); err != nil {
+		return nil, []string{"invalid JSON: " + err.Error()}
+	}
+
+	return cleaned, schema.Validate(data)
+}
+
+// ExtractJSON returns the first complete JSON object or array in s, ignoring
+// markdown code fences and any prose before or after it.
+func ExtractJSON(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		// drop the language hint, e.g. ```json
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+		if i := strings.LastIndex(s, "```"); i >= 0 {
+			s = s[:i]
+		}
+	}
+
+	// Prose before the JSON may itself contain brackets, so try every candidate start.
+	var lastErr error
+	for start := strings.IndexAny(s, "{["); start >= 0; {
+		dec := json.NewDecoder(strings.NewReader(s[start:]))
+		var raw json.RawMessage
+		if lastErr = dec.Decode(&raw); lastErr == nil {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, raw); err != nil {
+				return nil, fmt.Errorf("invalid JSON: %v", err)
+			}
+			return buf.Bytes(), nil
+		}
+
+		next := strings.IndexAny(s[start+1:], "{[")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+
+	if lastErr == nil {
+		return nil, errors.New("no JSON object or array found in response")
+	}
+	return nil, fmt.Errorf("invalid JSON: %v", lastErr)
+}
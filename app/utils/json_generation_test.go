@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+func TestGenerateJSONRepairAttempts(t *testing.T) {
+	for _, attempts := range []int{0, 2} {
+		fake, err := services.NewFakeLLMProvider("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake.SetFixture(services.LLMPurposeValidate, `{"appropriate": "yes"}`)
+
+		var target struct {
+			Appropriate bool `json:"appropriate"`
+		}
+		_, err = GenerateJSON(LLM{LLMProvider: fake, JSONRepairAttempts: attempts}, JSONRequest{
+			Purpose: services.LLMPurposeValidate,
+			Prompt:  "Is this a goal?",
+			Target:  &target,
+		})
+		if !errors.Is(err, ErrInvalidLLMOutput) {
+			t.Fatalf("%d repair attempts: error = %v, want ErrInvalidLLMOutput", attempts, err)
+		}
+		if len(fake.Requests) != attempts+1 {
+			t.Errorf("%d repair attempts: %d requests, want %d", attempts, len(fake.Requests), attempts+1)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+// JSONStringFieldStream extracts the value of one string field from a JSON
+// document that arrives in pieces, decoding escapes as it goes. It lets the
+// text of a field be shown while the rest of the document is still being
+// generated.
+type JSONStringFieldStream struct {
+	key     *regexp.Regexp
+	emit    services.StreamHandler
+	raw     strings.Builder
+	pos     int // offset in raw of the next undecoded byte of the value
+	started bool
+	done    bool
+}
+
+// NewJSONStringFieldStream returns a stream that passes the decoded value of
+// field to emit. Only the first occurrence of the field is streamed.
+func NewJSONStringFieldStream(field string, emit services.StreamHandler) *JSONStringFieldStream {
+	return &JSONStringFieldStream{
+		key:  regexp.MustCompile(`"` + regexp.QuoteMeta(field) + `"\s*:\s*"`),
+		emit: emit,
+	}
+}
+
+// Write consumes the next piece of raw output. It matches services.StreamHandler.
+func (s *JSONStringFieldStream) Write(delta string) error {
+	s.raw.WriteString(delta)
+	if s.done {
+		return nil
+	}
+
+	raw := s.raw.String()
+	if !s.started {
+		loc := s.key.FindStringIndex(raw)
+		if loc == nil {
+			return nil
+		}
+		s.started = true
+		s.pos = loc[1]
+	}
+
+	decoded, pos, closed := decodeJSONStringPrefix(raw, s.pos)
+	s.pos = pos
+	s.done = closed
+	if decoded == "" {
+		return nil
+	}
+	return s.emit(decoded)
+}
+
+// decodeJSONStringPrefix decodes the string body in raw starting at pos up to
+// the closing quote or the last complete escape sequence. It returns the
+// decoded text, the offset to resume from and whether the string ended.
+func decodeJSONStringPrefix(raw string, pos int) (string, int, bool) {
+	var out strings.Builder
+	for pos < len(raw) {
+		c := raw[pos]
+		switch {
+		case c == '"':
+			return out.String(), pos + 1, true
+		case c != '\\':
+			out.WriteByte(c)
+			pos++
+			continue
+		}
+
+		if pos+1 >= len(raw) {
+			break
+		}
+		switch esc := raw[pos+1]; esc {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case 'b':
+			out.WriteByte('\b')
+		case 'f':
+			out.WriteByte('\f')
+		case 'u':
+			r, size, ok := decodeUnicodeEscape(raw[pos:])
+			if !ok {
+				return out.String(), pos, false
+			}
+			out.WriteRune(r)
+			pos += size
+			continue
+		default:
+			// \" \\ \/ and anything unexpected are taken literally
+			out.WriteByte(esc)
+		}
+		pos += 2
+	}
+	return out.String(), pos, false
+}
+
+// decodeUnicodeEscape decodes a \uXXXX escape, combining surrogate pairs. ok is
+// false when more input is needed.
+func decodeUnicodeEscape(s string) (r rune, size int, ok bool) {
+	if len(s) < 6 {
+		return 0, 0, false
+	}
+	v, err := strconv.ParseUint(s[2:6], 16, 16)
+	if err != nil {
+		return utf16.DecodeRune(0, 0), 6, true
+	}
+	r = rune(v)
+	if !utf16.IsSurrogate(r) {
+		return r, 6, true
+	}
+	if (len(s) > 6 && s[6] != '\\') || (len(s) > 7 && s[7] != 'u') {
+		return utf16.DecodeRune(0, 0), 6, true
+	}
+	if len(s) < 12 {
+		return 0, 0, false
+	}
+	if s[6] == '\\' && s[7] == 'u' {
+		if low, err := strconv.ParseUint(s[8:12], 16, 16); err == nil {
+			return utf16.DecodeRune(r, rune(low)), 12, true
+		}
+	}
+	return utf16.DecodeRune(0, 0), 6, true
+}
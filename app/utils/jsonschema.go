@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JSONSchema is the subset of JSON Schema needed to describe and validate the
+// structures we ask the LLM to produce.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFor derives a JSON Schema from the json tags of a Go value. Fields tagged
+// omitempty, tagged `jsonschema:"optional"` or promoted from embedded structs
+// (e.g. BaseModel) are optional; every other field is required.
+func SchemaFor(v interface{}) *JSONSchema {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte, including datatypes.JSON, can hold arbitrary JSON.
+			return &JSONSchema{}
+		}
+		return &JSONSchema{Type: "array", Items: schemaForType(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaForType(t.Elem())}
+	case reflect.Struct:
+		s := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		collectFields(t, s, false)
+		sort.Strings(s.Required)
+		return s
+	default:
+		return &JSONSchema{}
+	}
+}
+
+func collectFields(t reflect.Type, s *JSONSchema, embedded bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, s, true)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = schemaForType(field.Type)
+
+		optional := embedded ||
+			strings.Contains(opts, "omitempty") ||
+			field.Tag.Get("jsonschema") == "optional"
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// String renders the schema as indented JSON for inclusion in prompts.
+func (s *JSONSchema) String() string {
+	b, _ := json.MarshalIndent(s, "", "  ")
+	return string(b)
+}
+
+// Validate checks a decoded JSON value against the schema and returns every
+// violation found, each prefixed with the JSON path it applies to.
+func (s *JSONSchema) Validate(data interface{}) []string {
+	var errs []string
+	s.validate("$", data, &errs)
+	return errs
+}
+
+func (s *JSONSchema) validate(path string, data interface{}, errs *[]string) {
+	if s == nil || s.Type == "" {
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		if _, ok := data.(string); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected string, got %s", path, jsonTypeName(data)))
+		}
+	case "boolean":
+		if _, ok := data.(bool); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected boolean, got %s", path, jsonTypeName(data)))
+		}
+	case "number":
+		if _, ok := data.(float64); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected number, got %s", path, jsonTypeName(data)))
+		}
+	case "integer":
+		n, ok := data.(float64)
+		if !ok || n != math.Trunc(n) {
+			*errs = append(*errs, fmt.Sprintf("%s: expected integer, got %s", path, jsonTypeName(data)))
+		}
+	case "array":
+		items, ok := data.([]interface{})
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected array, got %s", path, jsonTypeName(data)))
+			return
+		}
+		for i, item := range items {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+		}
+	case "object":
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected object, got %s", path, jsonTypeName(data)))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, fmt.Sprintf("%s: missing required property '%s'", path, name))
+			}
+		}
+
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := s.Properties[k]; ok {
+				// null is accepted for optional properties only
+				if obj[k] == nil && !contains(s.Required, k) {
+					continue
+				}
+				prop.validate(path+"."+k, obj[k], errs)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(path+"."+k, obj[k], errs)
+			}
+		}
+	}
+}
+
+func jsonTypeName(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if n == math.Trunc(n) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaBase struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type schemaItem struct {
+	Name string `json:"name"`
+}
+
+type schemaSample struct {
+	schemaBase
+	Title    string            `json:"title"`
+	Subtitle string            `json:"subtitle,omitempty"`
+	Notes    string            `json:"notes" jsonschema:"optional"`
+	Score    float64           `json:"score"`
+	Count    *int              `json:"count"`
+	Items    []schemaItem      `json:"items"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Raw      []byte            `json:"raw,omitempty"`
+	Secret   string            `json:"-"`
+	hidden   string
+}
+
+func TestSchemaFor(t *testing.T) {
+	s := SchemaFor(&schemaSample{})
+
+	if s.Type != "object" {
+		t.Fatalf("type = %q, want object", s.Type)
+	}
+	wantRequired := []string{"count", "items", "score", "title"}
+	if !reflect.DeepEqual(s.Required, wantRequired) {
+		t.Errorf("required = %v, want %v", s.Required, wantRequired)
+	}
+
+	tests := []struct {
+		property string
+		want     JSONSchema
+	}{
+		{"id", JSONSchema{Type: "integer"}},
+		{"created_at", JSONSchema{Type: "string", Format: "date-time"}},
+		{"title", JSONSchema{Type: "string"}},
+		{"subtitle", JSONSchema{Type: "string"}},
+		{"notes", JSONSchema{Type: "string"}},
+		{"score", JSONSchema{Type: "number"}},
+		{"count", JSONSchema{Type: "integer"}},
+		{"raw", JSONSchema{}},
+	}
+	for _, tt := range tests {
+		got, ok := s.Properties[tt.property]
+		if !ok {
+			t.Errorf("property %q is missing", tt.property)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("property %q = %+v, want %+v", tt.property, *got, tt.want)
+		}
+	}
+
+	items := s.Properties["items"]
+	if items.Type != "array" || items.Items.Type != "object" || !reflect.DeepEqual(items.Items.Required, []string{"name"}) {
+		t.Errorf("items = %s", items)
+	}
+	if tags := s.Properties["tags"]; tags.Type != "object" || tags.AdditionalProperties.Type != "string" {
+		t.Errorf("tags = %s", tags)
+	}
+	for _, name := range []string{"-", "Secret", "hidden"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("property %q should be skipped", name)
+		}
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := SchemaFor(&schemaSample{})
+
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []string
+	}{
+		{
+			name: "valid",
+			data: map[string]interface{}{"title": "Go", "score": 0.5, "count": 3.0, "items": []interface{}{}},
+		},
+		{
+			name: "null optional",
+			data: map[string]interface{}{"title": "Go", "score": 1.0, "count": 1.0, "items": []interface{}{}, "subtitle": nil},
+		},
+		{
+			name: "missing required",
+			data: map[string]interface{}{"title": "Go", "score": 1.0, "items": []interface{}{}},
+			want: []string{"$: missing required property 'count'"},
+		},
+		{
+			name: "wrong types",
+			data: map[string]interface{}{"title": 1.0, "score": "high", "count": 1.5, "items": []interface{}{map[string]interface{}{}}},
+			want: []string{
+				"$.count: expected integer, got number",
+				"$.items[0]: missing required property 'name'",
+				"$.score: expected number, got string",
+				"$.title: expected string, got integer",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Validate(tt.data)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "object", input: `{"a": 1}`, want: `{"a":1}`},
+		{name: "array", input: ` [1, 2] `, want: `[1,2]`},
+		{name: "fenced", input: "```json\n{\"a\": [1]}\n```", want: `{"a":[1]}`},
+		{name: "fenced without hint", input: "```\n[\"x\"]\n```", want: `["x"]`},
+		{name: "surrounding prose", input: "Here is the plan:\n{\"a\": true}\nHope it helps!", want: `{"a":true}`},
+		{name: "brackets in prose", input: "Use [brackets] carefully: {\"a\": \"b\"}", want: `{"a":"b"}`},
+		{name: "first of two documents", input: `{"a": 1} {"b": 2}`, want: `{"a":1}`},
+		{name: "no JSON", input: "I cannot help with that.", wantErr: "no JSON object or array found"},
+		{name: "truncated", input: `{"a": [1, 2`, wantErr: "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSON(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ExtractJSON = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import "strings"
+
+// DefaultLanguage is used for learners without a preferred language.
+const DefaultLanguage = "English"
+
+// languageNames maps lowercase ISO 639-1 codes and common native or English
+// names to the English name of the language.
+var languageNames = map[string]string{
+	"en": "English", "english": "English",
+	"es": "Spanish", "spanish": "Spanish", "español": "Spanish", "espanol": "Spanish",
+	"fr": "French", "french": "French", "français": "French", "francais": "French",
+	"de": "German", "german": "German", "deutsch": "German",
+	"pt": "Portuguese", "portuguese": "Portuguese", "português": "Portuguese", "portugues": "Portuguese",
+	"it": "Italian", "italian": "Italian", "italiano": "Italian",
+	"nl": "Dutch", "dutch": "Dutch", "nederlands": "Dutch",
+	"sw": "Swahili", "swahili": "Swahili", "kiswahili": "Swahili",
+	"yo": "Yoruba", "yoruba": "Yoruba",
+	"ha": "Hausa", "hausa": "Hausa",
+	"ar": "Arabic", "arabic": "Arabic", "العربية": "Arabic",
+	"hi": "Hindi", "hindi": "Hindi", "हिन्दी": "Hindi",
+	"zh": "Chinese", "chinese": "Chinese", "中文": "Chinese",
+	"ja": "Japanese", "japanese": "Japanese", "日本語": "Japanese",
+}
+
+// LanguageName returns the English name of a language given as a name or an
+// ISO 639-1 code, optionally with a region ("pt-BR", "Español" and "spanish"
+// all resolve). Language names are written into prompts, so unknown languages
+// and an empty value yield DefaultLanguage.
+func LanguageName(s string) string {
+	if name, ok := KnownLanguage(s); ok {
+		return name
+	}
+	return DefaultLanguage
+}
+
+// KnownLanguage resolves a language like LanguageName and reports whether it
+// is one content can be generated or translated in.
+func KnownLanguage(s string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	if name, ok := languageNames[key]; ok {
+		return name, true
+	}
+	if i := strings.IndexAny(key, "-_"); i > 0 {
+		if name, ok := languageNames[key[:i]]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
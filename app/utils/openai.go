@@ -2,276 +2,315 @@ package utils
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
-	"os"
-	"strconv"
 
-	"github.com/sashabaranov/go-openai"
 	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/services"
 	"gorm.io/datatypes"
 )
 
-var openAIClient *openai.Client
-
-// getOpenAIClient initializes and returns a singleton OpenAI client.
-func getOpenAIClient() (*openai.Client, error) {
-	if openAIClient != nil {
-		return openAIClient, nil
-	}
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return nil, errors.New("OPENAI_API_KEY not set")
-	}
-	openAIClient = openai.NewClient(apiKey)
-	return openAIClient, nil
-}
-
-// GenerateLearningPlanStructure generates a high-level learning plan structure
-func GenerateLearningPlanStructure(goal string, totalWeeks int, dailyCommitment int) (*models.CompleteLearningPlan, error) {
-	client, err := getOpenAIClient()
-	if err != nil {
-		return nil, err
+// chat sends a single system+user exchange to the provider and returns the raw content.
+func chat(llm services.LLMProvider, purpose, model, system, prompt string, jsonMode bool) (string, error) {
+	if llm == nil {
+		return "", errors.New("LLM provider not configured")
 	}
 
-	prompt := `Create a learning plan structure for: ` + goal + `
-	planplanplan
-	Requirements:
-	- Total weeks: ` + strconv.Itoa(totalWeeks) + `
-	- Daily commitment: ` + strconv.Itoa(dailyCommitment) + ` minutes
-	- Return a JSON object with the following structure:
-	{
-		"goal": "string",
-		"total_weeks": number,
-		"daily_commitment_minutes": number,
-		"weekly_themes": [
-			{
-				"week_number": number,
-				"theme": "string",
-				"objectives": ["string"],
-				"key_concepts": ["string"],
-				"prerequisites": ["string"]
-			}
-		],
-		"prerequisites": {"topic": ["prerequisites"]},
-		"adaptive_rules": {"rule": "description"}
-	}
-	
-	Make it comprehensive and well-structured.`
-
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4,
-			Messages: []openai.ChatCompletionMessage{
-				{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach. Always return valid JSON."},
-				{Role: openai.ChatMessageRoleUser, Content: prompt},
-			},
+	resp, err := llm.CreateChatCompletion(context.Background(), services.ChatRequest{
+		Model:   model,
+		Purpose: purpose,
+		Messages: []services.ChatMessage{
+			{Role: services.LLMRoleSystem, Content: system},
+			{Role: services.LLMRoleUser, Content: prompt},
 		},
-	)
+		JSONMode: jsonMode,
+	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return resp.Content, nil
+}
 
-	result := resp.Choices[0].Message.Content
+// PlanPrompt is the data rendered into the plan_structure prompt.
+type PlanPrompt struct {
+	Goal            string
+	TotalWeeks      int
+	DailyCommitment int
+	Language        string
+	Profile         models.LearnerProfile
+}
 
-	log.Printf("Result: %v", result)
+// WeekPrompt is the data rendered into the weekly_content prompt.
+type WeekPrompt struct {
+	Goal       string
+	WeekNumber int
+	Progress   models.ProgressSnapshot
+	Adaptation string
+	Language   string
+	Profile    models.LearnerProfile
+}
 
-	var plan models.CompleteLearningPlan
-	if err := json.Unmarshal([]byte(result), &plan); err != nil {
-		return nil, errors.New("failed to parse OpenAI response as JSON: " + err.Error())
-	}
+// DayPrompt is the data rendered into the daily_lesson and daily_resources prompts.
+type DayPrompt struct {
+	Goal           string
+	DailyStructure string
+	Week           int
+	Day            int
+	Progress       models.ProgressSnapshot
+	Language       string
+	Profile        models.LearnerProfile
+}
 
-	return &plan, nil
+// ExercisePrompt is the data rendered into the exercises prompt.
+type ExercisePrompt struct {
+	LessonContent string
+	Progress      models.ProgressSnapshot
+	Language      string
 }
 
-// GenerateWeeklyContent generates detailed content for a specific week
-func GenerateWeeklyContent(goal string, weekNumber int, userProgress map[string]interface{}) (*models.WeeklyContent, error) {
-	client, err := getOpenAIClient()
+// GoalPrompt is the data rendered into the validate_goal prompt.
+type GoalPrompt struct {
+	Goal string
+}
+
+// GenerateLearningPlanStructure generates a high-level learning plan structure
+// written in language and tailored to the learner profile.
+func GenerateLearningPlanStructure(llm LLM, goal string, totalWeeks int, dailyCommitment int, language string, profile models.LearnerProfile) (*models.CompleteLearningPlan, prompts.Trace, error) {
+	prompt, err := prompts.Default.Render(prompts.PlanStructure, PlanPrompt{
+		Goal:            goal,
+		TotalWeeks:      totalWeeks,
+		DailyCommitment: dailyCommitment,
+		Language:        language,
+		Profile:         profile,
+	})
 	if err != nil {
-		return nil, err
+		return nil, prompts.Trace{}, err
 	}
 
-	prompt := "Generate a detailed weekly learning content for week " + strconv.Itoa(weekNumber) + " of " + goal +
-		". User progress: " + toJSONString(userProgress) +
-		". Return a JSON object with fields: theme (string), objectives (array of strings), key_concepts (array of strings), prerequisites (array of strings), daily_milestones (array of objects with day_number (integer), topic (string), description (string), duration_minutes (integer), difficulty (string)), and adaptive_notes (string)."
-
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4,
-			Messages: []openai.ChatCompletionMessage{
-				{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach. Always return valid JSON."},
-				{Role: openai.ChatMessageRoleUser, Content: prompt},
-			},
-		},
-	)
+	var plan models.CompleteLearningPlan
+	if _, err := GenerateJSON(llm, JSONRequest{
+		Purpose: services.LLMPurposePlan,
+		Model:   services.LLMModelPrimary,
+		System:  prompt.System,
+		Prompt:  prompt.User,
+		Target:  &plan,
+	}); err != nil {
+		return nil, prompts.Trace{}, err
+	}
+
+	var trace prompts.Trace
+	trace.Add(prompt)
+	return &plan, trace, nil
+}
+
+// GenerateWeeklyContent generates detailed content for a specific week
+// written in language and tailored to the learner profile.
+func GenerateWeeklyContent(llm LLM, goal string, weekNumber int, progress models.ProgressSnapshot, adaptation string, language string, profile models.LearnerProfile) (*models.WeeklyContent, prompts.Trace, error) {
+	prompt, err := prompts.Default.Render(prompts.WeeklyContent, WeekPrompt{
+		Goal:       goal,
+		WeekNumber: weekNumber,
+		Progress:   progress,
+		Adaptation: adaptation,
+		Language:   language,
+		Profile:    profile,
+	})
 	if err != nil {
-		return nil, err
+		return nil, prompts.Trace{}, err
 	}
 
 	var content models.WeeklyContent
-	err = json.Unmarshal([]byte(resp.Choices[0].Message.Content), &content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	if _, err := GenerateJSON(llm, JSONRequest{
+		Purpose: services.LLMPurposeWeek,
+		Model:   services.LLMModelPrimary,
+		System:  prompt.System,
+		Prompt:  prompt.User,
+		Target:  &content,
+	}); err != nil {
+		return nil, prompts.Trace{}, err
 	}
 
-	return &content, nil
+	var trace prompts.Trace
+	trace.Add(prompt)
+	return &content, trace, nil
 }
 
 // ValidateLearningGoal validates the user's learning goal
-func ValidateLearningGoal(goal string) (bool, string, error) {
-	client, err := getOpenAIClient()
+func ValidateLearningGoal(llm LLM, goal string) (bool, string, error) {
+	prompt, err := prompts.Default.Render(prompts.ValidateGoal, GoalPrompt{Goal: goal})
 	if err != nil {
 		return false, "", err
 	}
 
-	prompt := fmt.Sprintf(`You are a learning plan validator. A user has provided the following learning goal: "%s".
-Your task is to determine if this is an appropriate and specific enough goal for creating a technical or academic learning plan.
-The goal should not be offensive, irrelevant, or overly broad (e.g., 'learn everything').
-Respond with a JSON object containing two fields: 'appropriate' (boolean) and 'reason' (a brief string explaining your decision).
-For example: {"appropriate": true, "reason": "This is a valid technical learning goal."} or {"appropriate": false, "reason": "The goal is too vague. Please be more specific."}`, goal)
-
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
-			Messages: []openai.ChatCompletionMessage{
-				{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning validator that always returns JSON."},
-				{Role: openai.ChatMessageRoleUser, Content: prompt},
-			},
-			ResponseFormat: &openai.ChatCompletionResponseFormat{
-				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
-			},
-		},
-	)
-
-	if err != nil {
-		return false, "", fmt.Errorf("failed to get response from OpenAI: %w", err)
-	}
-
 	var validationResponse struct {
 		Appropriate bool   `json:"appropriate"`
 		Reason      string `json:"reason"`
 	}
 
-	err = json.Unmarshal([]byte(resp.Choices[0].Message.Content), &validationResponse)
-	if err != nil {
-		return false, "", fmt.Errorf("failed to parse OpenAI response: %w", err)
+	if _, err := GenerateJSON(llm, JSONRequest{
+		Purpose:  services.LLMPurposeValidate,
+		Model:    services.LLMModelFast,
+		System:   prompt.System,
+		Prompt:   prompt.User,
+		JSONMode: true,
+		Target:   &validationResponse,
+	}); err != nil {
+		return false, "", fmt.Errorf("failed to get valid response from LLM: %w", err)
 	}
 
 	return validationResponse.Appropriate, validationResponse.Reason, nil
 }
 
 // Legacy function for backward compatibility
-func GenerateLearningPlan(prompt string) (string, error) {
-	client, err := getOpenAIClient()
+func GenerateLearningPlan(llm services.LLMProvider, prompt string) (string, error) {
+	return chat(llm, services.LLMPurposeGeneric, services.LLMModelPrimary, "You are an expert learning coach.", prompt, false)
+}
+
+func GenerateDailyContent(llm LLM, goal string, dailyStructure string, week int, day int, progress models.ProgressSnapshot, language string, profile models.LearnerProfile) (datatypes.JSON, datatypes.JSON, prompts.Trace, error) {
+	return StreamDailyContent(llm, goal, dailyStructure, week, day, progress, language, profile, nil)
+}
+
+// StreamDailyContent generates a day's lesson and resources like
+// GenerateDailyContent. When onExplanation is set the lesson completion is
+// streamed and the decoded explanation HTML is passed to it as it arrives.
+func StreamDailyContent(llm LLM, goal string, dailyStructure string, week int, day int, progress models.ProgressSnapshot, language string, profile models.LearnerProfile, onExplanation services.StreamHandler) (datatypes.JSON, datatypes.JSON, prompts.Trace, error) {
+	data := DayPrompt{
+		Goal:           goal,
+		DailyStructure: dailyStructure,
+		Week:           week,
+		Day:            day,
+		Progress:       progress,
+		Language:       language,
+		Profile:        profile,
+	}
+	var trace prompts.Trace
+
+	// 1. Lesson Content
+	lessonPrompt, err := prompts.Default.Render(prompts.DailyLesson, data)
 	if err != nil {
-		return "", err
+		return nil, nil, trace, err
 	}
+	trace.Add(lessonPrompt)
 
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4,
-			Messages: []openai.ChatCompletionMessage{
-				{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach."},
-				{Role: openai.ChatMessageRoleUser, Content: prompt},
-			},
-		},
-	)
+	lessonReq := JSONRequest{
+		Purpose: services.LLMPurposeLesson,
+		Model:   services.LLMModelPrimary,
+		System:  lessonPrompt.System,
+		Prompt:  lessonPrompt.User,
+	}
+	if onExplanation != nil {
+		lessonReq.OnDelta = NewJSONStringFieldStream("explanation", onExplanation).Write
+	}
+
+	var lesson models.LessonContent
+	lessonReq.Target = &lesson
+	lessonResult, err := GenerateJSON(llm, lessonReq)
 	if err != nil {
-		return "", err
+		return nil, nil, trace, err
 	}
-	return resp.Choices[0].Message.Content, nil
-}
+	lessonJSON := datatypes.JSON(lessonResult)
 
-func GenerateDailyContent(goal string, dailyStructure string, week int, day int, userProgress map[string]interface{}) (datatypes.JSON, datatypes.JSON, error) {
-	client, err := getOpenAIClient()
+	// 2. Exercises are generated on demand, see GenerateExercisesForLesson
+
+	// 3. Resources
+	resourcePrompt, err := prompts.Default.Render(prompts.DailyResources, data)
 	if err != nil {
-		return nil, nil, err
+		return lessonJSON, nil, trace, err
+	}
+	trace.Add(resourcePrompt)
+
+	var resources []models.Resource
+	resourceResult, err := GenerateJSON(llm, JSONRequest{
+		Purpose: services.LLMPurposeResources,
+		Model:   services.LLMModelPrimary,
+		System:  resourcePrompt.System,
+		Prompt:  resourcePrompt.User,
+		Target:  &resources,
+	})
+	if err != nil {
+		return lessonJSON, nil, trace, err
 	}
+	resourceJSON := datatypes.JSON(resourceResult)
 
-	// 1. Lesson Content
-	lessonPrompt := "using the theme in " + dailyStructure +
-		"Generate a focused lesson contents in details for week " +
-		strconv.Itoa(week) + ", day " + strconv.Itoa(day) + " for goal: " + goal +
-		". User progress: " + toJSONString(userProgress) +
-		". Return a JSON object with fields: title, summary, key_points, explanation." +
-		". The explanation property should be a well-formatted HTML string. Use paragraphs, lists with headings, and bold and italic tags to make the content easy to read and understand. For code snippets, wrap them in <pre><code>...</code></pre> tags. Ensure there is good spacing and line breaks between different sections."
-
-	lessonResp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
-		Model: openai.GPT4,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach. Always return valid JSON."},
-			{Role: openai.ChatMessageRoleUser, Content: lessonPrompt},
-		},
+	return lessonJSON, resourceJSON, trace, nil
+}
+
+func GenerateExercisesForLesson(llm LLM, lessonContent string, progress models.ProgressSnapshot, language string) (datatypes.JSON, prompts.Stamp, error) {
+	prompt, err := prompts.Default.Render(prompts.Exercises, ExercisePrompt{
+		LessonContent: lessonContent,
+		Progress:      progress,
+		Language:      language,
 	})
 	if err != nil {
 		return nil, nil, err
 	}
-	lessonJSON := datatypes.JSON([]byte(lessonResp.Choices[0].Message.Content))
-
-	// 2. Exercises
-	// exercisePrompt := "Generate 2-3 exercises for the above lesson. Return a JSON array of objects with fields: type, question, options, answer, explanation."
-	// exerciseResp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
-	// 	Model: openai.GPT4,
-	// 	Messages: []openai.ChatCompletionMessage{
-	// 		{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach. Always return valid JSON."},
-	// 		{Role: openai.ChatMessageRoleUser, Content: exercisePrompt},
-	// 	},
-	// })
-	// if err != nil {
-	// 	return lessonJSON, nil, nil, err
-	// }
-	// exerciseJSON := datatypes.JSON([]byte(exerciseResp.Choices[0].Message.Content))
 
-	// 3. Resources
-	resourcePrompt := "using the structure" + dailyStructure +
-		"Suggest 3-6 high-quality, up-to-date online resources like articles, videos, books, etc. (links) for week " +
-		strconv.Itoa(week) + ", day " + strconv.Itoa(day) + " for goal: " + goal +
-		". User progress: " + toJSONString(userProgress) +
-		".Return a JSON array of objects with fields: type, title, url, description."
-	resourceResp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
-		Model: openai.GPT4,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach. Always return valid JSON."},
-			{Role: openai.ChatMessageRoleUser, Content: resourcePrompt},
-		},
+	var exercises []models.Exercise
+	result, err := GenerateJSON(llm, JSONRequest{
+		Purpose: services.LLMPurposeExercises,
+		Model:   services.LLMModelPrimary,
+		System:  prompt.System,
+		Prompt:  prompt.User,
+		Target:  &exercises,
 	})
 	if err != nil {
-		return lessonJSON, nil, err
+		return nil, nil, err
 	}
-	resourceJSON := datatypes.JSON([]byte(resourceResp.Choices[0].Message.Content))
 
-	return lessonJSON, resourceJSON, nil
+	exerciseJSON := datatypes.JSON(result)
+	return exerciseJSON, prompts.Stamp{prompt.Name: prompt.Version}, nil
 }
 
-func toJSONString(v interface{}) string {
-	b, _ := json.Marshal(v)
-	return string(b)
+// TranslatePrompt is the data rendered into the translate_daily_content prompt.
+type TranslatePrompt struct {
+	Language  string
+	Lesson    models.LessonContent
+	Exercises []models.Exercise
+	Resources []models.Resource
 }
 
-func GenerateExercisesForLesson(lessonContent string, userProgress map[string]interface{}) (datatypes.JSON, error) {
-	client, err := getOpenAIClient()
-	if err != nil {
-		return nil, err
+// TranslatedDay is a day's lesson, exercises and resources in another language.
+type TranslatedDay struct {
+	Lesson    models.LessonContent `json:"lesson"`
+	Exercises []models.Exercise    `json:"exercises"`
+	Resources []models.Resource    `json:"resources"`
+}
+
+// TranslateDailyContent translates an existing lesson, its exercises and its
+// resources into language, keeping their structure, order and URLs.
+func TranslateDailyContent(llm LLM, language string, lesson models.LessonContent, exercises []models.Exercise, resources []models.Resource) (*TranslatedDay, prompts.Stamp, error) {
+	if exercises == nil {
+		exercises = []models.Exercise{}
+	}
+	if resources == nil {
+		resources = []models.Resource{}
 	}
 
-	prompt := "Based on the lesson content: '" + lessonContent + "' and user progress: " + toJSONString(userProgress) + ", generate 5-13 exercises. Return a JSON array of objects with fields: type, question, options, answer, explanation, difficulty."
-	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
-		Model: openai.GPT4,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: "You are an expert learning coach. Always return valid JSON."},
-			{Role: openai.ChatMessageRoleUser, Content: prompt},
-		},
+	prompt, err := prompts.Default.Render(prompts.TranslateDailyContent, TranslatePrompt{
+		Language:  language,
+		Lesson:    lesson,
+		Exercises: exercises,
+		Resources: resources,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	var translated TranslatedDay
+	if _, err := GenerateJSON(llm, JSONRequest{
+		Purpose:  services.LLMPurposeTranslate,
+		Model:    services.LLMModelPrimary,
+		System:   prompt.System,
+		Prompt:   prompt.User,
+		JSONMode: true,
+		Target:   &translated,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if len(translated.Exercises) != len(exercises) || len(translated.Resources) != len(resources) {
+		return nil, nil, fmt.Errorf("%w: translation changed the number of exercises or resources", ErrInvalidLLMOutput)
 	}
 
-	exerciseJSON := datatypes.JSON([]byte(resp.Choices[0].Message.Content))
-	return exerciseJSON, nil
+	return &translated, prompts.Stamp{prompt.Name: prompt.Version}, nil
 }
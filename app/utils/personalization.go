@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+)
+
+// maxProfileFieldLength caps free text profile fields so a long bio cannot
+// crowd out the rest of a prompt.
+const maxProfileFieldLength = 200
+
+// LearnerProfileFor builds the prompt profile from a user's Age, Level,
+// Background, Interests and Country. Blank fields and implausible ages are
+// left out.
+func LearnerProfileFor(user models.User) models.LearnerProfile {
+	profile := models.LearnerProfile{
+		Level:      profileField(user.Level),
+		Background: profileField(user.Background),
+		Interests:  profileField(user.Interests),
+		Country:    profileField(user.Country),
+	}
+	if user.Age != nil && *user.Age > 0 && *user.Age < 120 {
+		age := *user.Age
+		profile.Age = &age
+	}
+	return profile
+}
+
+func profileField(s *string) string {
+	if s == nil {
+		return ""
+	}
+	v := strings.Join(strings.Fields(*s), " ")
+	if utf8.RuneCountInString(v) <= maxProfileFieldLength {
+		return v
+	}
+	return strings.TrimSpace(string([]rune(v)[:maxProfileFieldLength])) + "…"
+}
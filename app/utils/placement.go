@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+const (
+	// placementQuestionsPerLevel is how many questions are generated for
+	// each difficulty; a learner answers at most two levels of them
+	placementQuestionsPerLevel = 4
+	// placementPassAccuracy is the share of a stage a learner must answer
+	// correctly to pass it
+	placementPassAccuracy = 0.7
+)
+
+// FirstPlacementStage is the difficulty a placement quiz starts at. Passing it
+// moves the learner up to advanced questions, failing it down to beginner ones.
+const FirstPlacementStage = models.PlacementLevelIntermediate
+
+var placementDifficulties = map[string]bool{
+	models.PlacementLevelBeginner:     true,
+	models.PlacementLevelIntermediate: true,
+	models.PlacementLevelAdvanced:     true,
+}
+
+// PlacementPrompt is the data rendered into the placement_quiz prompt.
+type PlacementPrompt struct {
+	Goal     string
+	PerLevel int
+	Total    int
+	Language string
+	Profile  models.LearnerProfile
+}
+
+// NewPlacementPrompt returns the placement_quiz prompt data for goal.
+func NewPlacementPrompt(goal string, language string, profile models.LearnerProfile) PlacementPrompt {
+	return PlacementPrompt{
+		Goal:     goal,
+		PerLevel: placementQuestionsPerLevel,
+		Total:    placementQuestionsPerLevel * len(placementDifficulties),
+		Language: language,
+		Profile:  profile,
+	}
+}
+
+// GeneratePlacementQuiz generates the question pool of a placement quiz for
+// goal. Questions whose answer is not one of their options or whose
+// difficulty is unknown are dropped; every difficulty must keep at least one.
+func GeneratePlacementQuiz(llm LLM, goal string, language string, profile models.LearnerProfile) ([]models.PlacementQuestion, prompts.Stamp, error) {
+	prompt, err := prompts.Default.Render(prompts.PlacementQuiz, NewPlacementPrompt(goal, language, profile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var generated []models.PlacementQuestion
+	if _, err := GenerateJSON(llm, JSONRequest{
+		Purpose: services.LLMPurposePlacement,
+		Model:   services.LLMModelPrimary,
+		System:  prompt.System,
+		Prompt:  prompt.User,
+		Target:  &generated,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	questions := make([]models.PlacementQuestion, 0, len(generated))
+	levels := map[string]int{}
+	for _, q := range generated {
+		q.Difficulty = strings.ToLower(strings.TrimSpace(q.Difficulty))
+		if !placementDifficulties[q.Difficulty] || len(q.Options) < 2 || !AnswerInOptions(q.Options, q.Answer) {
+			continue
+		}
+		q.Answer = resolveOption(q.Options, q.Answer)
+		questions = append(questions, q)
+		levels[q.Difficulty]++
+	}
+	for difficulty := range placementDifficulties {
+		if levels[difficulty] == 0 {
+			return nil, nil, fmt.Errorf("%w: no usable %s placement questions", ErrInvalidLLMOutput, difficulty)
+		}
+	}
+
+	return questions, prompts.Stamp{prompt.Name: prompt.Version}, nil
+}
+
+// PlacementStageQuestions returns the indexes of the questions asked at stage.
+func PlacementStageQuestions(questions []models.PlacementQuestion, stage string) []int {
+	var indexes []int
+	for i, q := range questions {
+		if q.Difficulty == stage {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// GradePlacementStage grades the answers given to the questions of stage,
+// keyed by question index. Unanswered questions count as wrong, so a learner
+// may skip what they do not know.
+func GradePlacementStage(questions []models.PlacementQuestion, stage string, given map[int]string) []models.PlacementAnswer {
+	var answers []models.PlacementAnswer
+	for _, i := range PlacementStageQuestions(questions, stage) {
+		q := questions[i]
+		result, _ := GradeAnswer(LLM{}, models.Exercise{Options: q.Options, Answer: q.Answer}, given[i], GradingModeNormalized)
+		answers = append(answers, models.PlacementAnswer{
+			QuestionIndex: i,
+			Answer:        given[i],
+			Correct:       result.Correct,
+		})
+	}
+	return answers
+}
+
+// placementStagePassed reports whether stage was asked and passed.
+func placementStagePassed(questions []models.PlacementQuestion, answers []models.PlacementAnswer, stage string) bool {
+	asked, correct := 0, 0
+	for _, a := range answers {
+		if a.QuestionIndex < 0 || a.QuestionIndex >= len(questions) || questions[a.QuestionIndex].Difficulty != stage {
+			continue
+		}
+		asked++
+		if a.Correct {
+			correct++
+		}
+	}
+	return asked > 0 && float64(correct)/float64(asked) >= placementPassAccuracy
+}
+
+// NextPlacementStage returns the difficulty to ask after stage, or an empty
+// string when the quiz is over. Only the first stage branches.
+func NextPlacementStage(questions []models.PlacementQuestion, answers []models.PlacementAnswer, stage string) string {
+	if stage != FirstPlacementStage {
+		return ""
+	}
+	if placementStagePassed(questions, answers, stage) {
+		return models.PlacementLevelAdvanced
+	}
+	return models.PlacementLevelBeginner
+}
+
+// InferPlacement derives a learner's level, the concepts they already know
+// and their overall score from a finished quiz. Known concepts are those
+// answered correctly plus, when the learner skipped the beginner stage by
+// passing the first one, every beginner concept.
+func InferPlacement(questions []models.PlacementQuestion, answers []models.PlacementAnswer) (string, []string, float64) {
+	level := models.PlacementLevelNovice
+	switch {
+	case placementStagePassed(questions, answers, models.PlacementLevelAdvanced):
+		level = models.PlacementLevelAdvanced
+	case placementStagePassed(questions, answers, models.PlacementLevelIntermediate):
+		level = models.PlacementLevelIntermediate
+	case placementStagePassed(questions, answers, models.PlacementLevelBeginner):
+		level = models.PlacementLevelBeginner
+	}
+
+	known := []string{}
+	seen := map[string]bool{}
+	addConcept := func(concept string) {
+		key := NormalizeAnswer(concept)
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		known = append(known, strings.TrimSpace(concept))
+	}
+
+	askedBeginner := false
+	correct := 0
+	for _, a := range answers {
+		if a.QuestionIndex < 0 || a.QuestionIndex >= len(questions) {
+			continue
+		}
+		q := questions[a.QuestionIndex]
+		if q.Difficulty == models.PlacementLevelBeginner {
+			askedBeginner = true
+		}
+		if a.Correct {
+			correct++
+			addConcept(q.Concept)
+		}
+	}
+	if !askedBeginner && level != models.PlacementLevelNovice {
+		for _, i := range PlacementStageQuestions(questions, models.PlacementLevelBeginner) {
+			addConcept(questions[i].Concept)
+		}
+	}
+
+	score := 0.0
+	if len(answers) > 0 {
+		score = float64(correct) / float64(len(answers))
+	}
+	return level, known, score
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/services"
+)
+
+// Limits on the conversation sent verbatim to the tutor. Once the turns not
+// yet summarized exceed tutorMaxMessages or tutorMaxHistoryChars, all but the
+// last tutorKeepMessages are folded into the thread summary.
+const (
+	tutorMaxMessages     = 12
+	tutorKeepMessages    = 6
+	tutorMaxHistoryChars = 12000
+)
+
+// TutorExercise is an exercise of the day as shown to the tutor, with the
+// learner's latest attempt. Answers of exercises not attempted are withheld.
+type TutorExercise struct {
+	Number      int
+	Question    string
+	Options     []string
+	Answer      string
+	Explanation string
+	Attempted   bool
+	LastAnswer  string
+	Correct     bool
+}
+
+// TutorPrompt is the data rendered into the tutor_chat prompt.
+type TutorPrompt struct {
+	Goal      string
+	Week      int
+	Day       int
+	Lesson    models.LessonContent
+	Exercises []TutorExercise
+	Summary   string
+	Language  string
+	Profile   models.LearnerProfile
+}
+
+// TutorSummaryPrompt is the data rendered into the tutor_summary prompt.
+type TutorSummaryPrompt struct {
+	LessonTitle     string
+	PreviousSummary string
+	Turns           []models.TutorMessage
+	Language        string
+}
+
+// TutorExercises pairs a day's exercises with the learner's latest attempt at
+// each. attempts must be in chronological order.
+func TutorExercises(exercises []models.Exercise, attempts []models.ExerciseAttempt) []TutorExercise {
+	latest := map[int]models.ExerciseAttempt{}
+	for _, a := range attempts {
+		latest[a.ExerciseIndex] = a
+	}
+
+	items := make([]TutorExercise, 0, len(exercises))
+	for i, e := range exercises {
+		item := TutorExercise{Number: i + 1, Question: e.Question, Options: e.Options}
+		if a, ok := latest[i]; ok {
+			item.Attempted = true
+			item.LastAnswer = a.Answer
+			item.Correct = a.IsCorrect
+			item.Answer = e.Answer
+			item.Explanation = e.Explanation
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// SplitTutorHistory splits the turns not yet summarized into those to fold
+// into the summary and those to keep verbatim. Nothing is folded while the
+// history is within limits; otherwise the recent turns are trimmed further
+// until they fit tutorMaxHistoryChars, keeping at least the last one.
+func SplitTutorHistory(messages []models.TutorMessage) (older, recent []models.TutorMessage) {
+	chars := 0
+	for _, m := range messages {
+		chars += utf8.RuneCountInString(m.Content)
+	}
+	if len(messages) <= tutorMaxMessages && chars <= tutorMaxHistoryChars {
+		return nil, messages
+	}
+
+	keep := tutorKeepMessages
+	if keep > len(messages) {
+		keep = len(messages)
+	}
+	for keep > 1 {
+		chars = 0
+		for _, m := range messages[len(messages)-keep:] {
+			chars += utf8.RuneCountInString(m.Content)
+		}
+		if chars <= tutorMaxHistoryChars {
+			break
+		}
+		keep--
+	}
+	return messages[:len(messages)-keep], messages[len(messages)-keep:]
+}
+
+// SummarizeTutorTurns folds turns into the previous summary of a conversation.
+func SummarizeTutorTurns(llm services.LLMProvider, data TutorSummaryPrompt) (string, prompts.Stamp, error) {
+	prompt, err := prompts.Default.Render(prompts.TutorSummary, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	summary, err := chat(llm, services.LLMPurposeSummary, services.LLMModelFast, prompt.System, prompt.User, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.TrimSpace(summary), prompts.Stamp{prompt.Name: prompt.Version}, nil
+}
+
+// TutorReply answers the learner's question given the lesson context, the
+// recent turns of the conversation and its summary. When onDelta is set the
+// reply is streamed to it as it is generated.
+func TutorReply(llm services.LLMProvider, data TutorPrompt, history []models.TutorMessage, question string, onDelta services.StreamHandler) (string, prompts.Stamp, error) {
+	if llm == nil {
+		return "", nil, errors.New("LLM provider not configured")
+	}
+
+	prompt, err := prompts.Default.Render(prompts.TutorChat, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	messages := []services.ChatMessage{
+		{Role: services.LLMRoleSystem, Content: prompt.System},
+		{Role: services.LLMRoleSystem, Content: prompt.User},
+	}
+	for _, m := range history {
+		messages = append(messages, services.ChatMessage{Role: m.Role, Content: m.Content})
+	}
+	messages = append(messages, services.ChatMessage{Role: services.LLMRoleUser, Content: question})
+
+	req := services.ChatRequest{
+		Model:    services.LLMModelPrimary,
+		Purpose:  services.LLMPurposeTutor,
+		Messages: messages,
+	}
+	var resp *services.ChatResponse
+	if onDelta != nil {
+		resp, err = llm.StreamChatCompletion(context.Background(), req, onDelta)
+	} else {
+		resp, err = llm.CreateChatCompletion(context.Background(), req)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strings.TrimSpace(resp.Content), prompts.Stamp{prompt.Name: prompt.Version}, nil
+}
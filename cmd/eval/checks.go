@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+)
+
+// Check is the outcome of one structural check on generated output.
+type Check struct {
+	Name   string `json:"name"`
+	Scope  string `json:"scope"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (c Check) key() string {
+	return c.Scope + " " + c.Name
+}
+
+func pass(name, scope string) Check {
+	return Check{Name: name, Scope: scope, Passed: true}
+}
+
+func fail(name, scope, format string, args ...interface{}) Check {
+	return Check{Name: name, Scope: scope, Detail: fmt.Sprintf(format, args...)}
+}
+
+func checkIf(ok bool, name, scope, format string, args ...interface{}) Check {
+	if ok {
+		return pass(name, scope)
+	}
+	return fail(name, scope, format, args...)
+}
+
+// checkPlan verifies that a plan structure has one theme per requested week,
+// numbered from 1, each with objectives.
+func checkPlan(g Goal, plan *models.CompleteLearningPlan) []Check {
+	const scope = "plan"
+	checks := []Check{
+		checkIf(plan.TotalWeeks == g.TotalWeeks, "plan.total_weeks", scope,
+			"total_weeks is %d, want %d", plan.TotalWeeks, g.TotalWeeks),
+		checkIf(len(plan.WeeklyThemes) == g.TotalWeeks, "plan.week_count", scope,
+			"%d weekly themes, want %d", len(plan.WeeklyThemes), g.TotalWeeks),
+		checkIf(plan.DailyCommitment == g.DailyCommitment, "plan.daily_commitment", scope,
+			"daily_commitment_minutes is %d, want %d", plan.DailyCommitment, g.DailyCommitment),
+	}
+
+	var misnumbered, empty []string
+	for i, theme := range plan.WeeklyThemes {
+		if theme.WeekNumber != i+1 {
+			misnumbered = append(misnumbered, fmt.Sprintf("#%d is week %d", i+1, theme.WeekNumber))
+		}
+		if strings.TrimSpace(theme.Theme) == "" || len(theme.Objectives) == 0 {
+			empty = append(empty, fmt.Sprintf("week %d", theme.WeekNumber))
+		}
+	}
+	checks = append(checks,
+		checkIf(len(misnumbered) == 0, "plan.week_numbers", scope, "themes out of order: %s", strings.Join(misnumbered, ", ")),
+		checkIf(len(empty) == 0, "plan.themes_complete", scope, "missing theme or objectives: %s", strings.Join(empty, ", ")),
+	)
+	return checks
+}
+
+// checkWeek verifies that every day of a week has a topic and that each day's
+// milestones add up to the daily commitment, within tolerance.
+func checkWeek(g Goal, week int, content *models.WeeklyContent, tolerance float64) []Check {
+	scope := fmt.Sprintf("week %d", week)
+	if len(content.DailyMilestones) == 0 {
+		return []Check{fail("week.has_days", scope, "no daily milestones")}
+	}
+
+	minutes := map[int]int{}
+	var untitled []string
+	for _, m := range content.DailyMilestones {
+		minutes[m.DayNumber] += m.DurationMinutes
+		if strings.TrimSpace(m.Topic) == "" {
+			untitled = append(untitled, fmt.Sprintf("day %d", m.DayNumber))
+		}
+	}
+
+	days := make([]int, 0, len(minutes))
+	for day := range minutes {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	var gaps, offTarget []string
+	for i, day := range days {
+		if day != i+1 {
+			gaps = append(gaps, fmt.Sprintf("expected day %d, got %d", i+1, day))
+		}
+		diff := float64(minutes[day] - g.DailyCommitment)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance*float64(g.DailyCommitment) {
+			offTarget = append(offTarget, fmt.Sprintf("day %d: %d min", day, minutes[day]))
+		}
+	}
+
+	return []Check{
+		pass("week.has_days", scope),
+		checkIf(len(gaps) == 0, "week.day_numbers", scope, "%s", strings.Join(gaps, ", ")),
+		checkIf(len(untitled) == 0, "week.day_topics", scope, "missing topic: %s", strings.Join(untitled, ", ")),
+		checkIf(len(offTarget) == 0, "week.daily_minutes", scope,
+			"want %d min per day: %s", g.DailyCommitment, strings.Join(offTarget, ", ")),
+	}
+}
+
+// checkLesson verifies that a lesson has its required fields.
+func checkLesson(week, day int, lesson *models.LessonContent) []Check {
+	scope := fmt.Sprintf("week %d day %d", week, day)
+	return []Check{
+		checkIf(strings.TrimSpace(lesson.Title) != "", "lesson.title", scope, "empty title"),
+		checkIf(len(lesson.KeyPoints) > 0, "lesson.key_points", scope, "no key points"),
+		checkIf(strings.Contains(lesson.Explanation, "<"), "lesson.html_explanation", scope, "explanation is not HTML"),
+	}
+}
+
+// checkResources verifies that every resource links to an absolute http(s) URL.
+func checkResources(week, day int, resources []models.Resource) []Check {
+	scope := fmt.Sprintf("week %d day %d", week, day)
+	var invalid []string
+	for _, r := range resources {
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid = append(invalid, r.URL)
+		}
+	}
+	return []Check{
+		checkIf(len(resources) >= 3 && len(resources) <= 6, "resources.count", scope, "%d resources, want 3-6", len(resources)),
+		checkIf(len(invalid) == 0, "resources.urls", scope, "invalid URLs: %s", strings.Join(invalid, ", ")),
+	}
+}
+
+// checkExercises verifies that every exercise has an answer and that
+// multiple choice answers are among the options.
+func checkExercises(week, day int, exercises []models.Exercise) []Check {
+	scope := fmt.Sprintf("week %d day %d", week, day)
+	var unanswered, notInOptions []string
+	for i, e := range exercises {
+		if strings.TrimSpace(e.Answer) == "" {
+			unanswered = append(unanswered, fmt.Sprintf("#%d", i+1))
+			continue
+		}
+		if len(e.Options) > 0 && !utils.AnswerInOptions(e.Options, e.Answer) {
+			notInOptions = append(notInOptions, fmt.Sprintf("#%d (%q)", i+1, e.Answer))
+		}
+	}
+	return []Check{
+		checkIf(len(exercises) > 0, "exercises.count", scope, "no exercises"),
+		checkIf(len(unanswered) == 0, "exercises.answers_present", scope, "no answer: %s", strings.Join(unanswered, ", ")),
+		checkIf(len(notInOptions) == 0, "exercises.answers_in_options", scope, "answer not among options: %s", strings.Join(notInOptions, ", ")),
+	}
+}
@@ -0,0 +1,229 @@
+// Command eval runs a fixture set of learning goals through the curriculum
+// generators and scores the output with structural checks, so that prompt and
+// model changes can be compared between runs.
+//
+//	go run ./cmd/eval                                  # recorded responses
+//	go run ./cmd/eval -provider live -weeks 1          # provider from LLM_PROVIDER
+//	go run ./cmd/eval -baseline eval-report/report.json -out eval-report-new
+//
+// The report is written to <out>/report.json and <out>/report.md.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/surahj/ai-mentor-backend/app/configs"
+	"github.com/surahj/ai-mentor-backend/app/models"
+	"github.com/surahj/ai-mentor-backend/app/prompts"
+	"github.com/surahj/ai-mentor-backend/app/services"
+	"github.com/surahj/ai-mentor-backend/app/utils"
+)
+
+// Goal is one entry of the fixture set.
+type Goal struct {
+	ID              string `json:"id"`
+	Goal            string `json:"goal"`
+	TotalWeeks      int    `json:"total_weeks"`
+	DailyCommitment int    `json:"daily_commitment"`
+	// Language the content is generated in; empty leaves it to the model
+	Language string `json:"language,omitempty"`
+	// Profile is the learner the content is personalised for
+	Profile models.LearnerProfile `json:"profile,omitempty"`
+	// Responses is a directory, relative to the fixture file, of recorded
+	// <purpose>.json responses. Without it the built-in fake responses are used.
+	Responses string `json:"responses,omitempty"`
+}
+
+type options struct {
+	weeks     int
+	days      int
+	tolerance float64
+}
+
+func main() {
+	fixtures := flag.String("fixtures", "cmd/eval/fixtures/goals.json", "fixture set of goals")
+	provider := flag.String("provider", "recorded", "recorded: replay recorded responses; live: use the provider configured by LLM_PROVIDER")
+	weeks := flag.Int("weeks", 0, "weeks to generate per goal (0 for all)")
+	days := flag.Int("days", 1, "days per week to generate lessons and exercises for")
+	tolerance := flag.Float64("tolerance", 0.1, "allowed relative difference between a day's minutes and the daily commitment")
+	out := flag.String("out", "eval-report", "directory to write report.json and report.md to")
+	baselinePath := flag.String("baseline", "", "previous report.json to compare against")
+	minScore := flag.Float64("min-score", 0, "exit with status 1 when the overall score is below this (0..1)")
+	flag.Parse()
+
+	if *provider != "recorded" && *provider != "live" {
+		log.Fatalf("unknown provider '%s'", *provider)
+	}
+	if *provider == "live" {
+		if err := godotenv.Load(); err != nil {
+			log.Println("No .env file found or error loading .env file")
+		}
+	}
+
+	goals, err := loadGoals(*fixtures)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	var baseline *Report
+	if *baselinePath != "" {
+		if baseline, err = readReport(*baselinePath); err != nil {
+			log.Fatalf("Failed to load baseline: %v", err)
+		}
+	}
+
+	var liveLLM services.LLMProvider
+	repairAttempts := utils.DefaultJSONRepairAttempts
+	if *provider == "live" {
+		config, err := configs.Load()
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		if liveLLM, err = services.NewLLMProvider(config.LLM); err != nil {
+			log.Fatalf("Failed to initialize LLM provider: %v", err)
+		}
+		repairAttempts = config.LLM.JSONMaxRetries
+	}
+
+	report := &Report{GeneratedAt: time.Now().UTC(), Provider: *provider, Prompts: activePrompts()}
+	opts := options{weeks: *weeks, days: *days, tolerance: *tolerance}
+
+	for _, g := range goals {
+		llm := utils.LLM{LLMProvider: liveLLM, JSONRepairAttempts: repairAttempts}
+		if liveLLM == nil {
+			dir := ""
+			if g.Responses != "" {
+				dir = filepath.Join(filepath.Dir(*fixtures), g.Responses)
+			}
+			if llm.LLMProvider, err = services.NewFakeLLMProvider(dir); err != nil {
+				log.Fatalf("Failed to load recorded responses for %s: %v", g.ID, err)
+			}
+		}
+
+		result := evaluate(llm, g, opts)
+		log.Printf("%s: %d/%d checks passed", g.ID, result.Passed, result.Total)
+		report.add(result)
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatalf("Failed to create %s: %v", *out, err)
+	}
+	if err := report.writeJSON(filepath.Join(*out, "report.json")); err != nil {
+		log.Fatalf("Failed to write JSON report: %v", err)
+	}
+	markdown := report.Markdown(baseline)
+	if err := os.WriteFile(filepath.Join(*out, "report.md"), []byte(markdown), 0o644); err != nil {
+		log.Fatalf("Failed to write Markdown report: %v", err)
+	}
+
+	fmt.Print(markdown)
+
+	if report.Score < *minScore {
+		log.Printf("Score %s is below the minimum %s", percent(report.Score), percent(*minScore))
+		os.Exit(1)
+	}
+}
+
+func loadGoals(path string) ([]Goal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var goals []Goal
+	if err := json.Unmarshal(data, &goals); err != nil {
+		return nil, fmt.Errorf("invalid fixtures %s: %w", path, err)
+	}
+	for i, g := range goals {
+		if g.ID == "" || g.Goal == "" || g.TotalWeeks < 1 || g.DailyCommitment < 1 {
+			return nil, fmt.Errorf("fixture %d needs an id, goal, total_weeks and daily_commitment", i+1)
+		}
+	}
+	return goals, nil
+}
+
+func activePrompts() map[string]int {
+	versions := map[string]int{}
+	for _, s := range prompts.Default.List() {
+		versions[s.Name] = s.ActiveVersion
+	}
+	return versions
+}
+
+// evaluate generates the plan, weeks, lessons and exercises for a goal the
+// way the API does and checks each of them. A failed generation step counts
+// as a failed check and skips the steps that depend on it.
+func evaluate(llm utils.LLM, g Goal, opts options) GoalResult {
+	start := time.Now()
+	result := GoalResult{ID: g.ID, Goal: g.Goal}
+
+	plan, _, err := utils.GenerateLearningPlanStructure(llm, g.Goal, g.TotalWeeks, g.DailyCommitment, g.Language, g.Profile)
+	if err != nil {
+		result.add(fail("generate.plan", "plan", "%v", err))
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result
+	}
+	result.add(pass("generate.plan", "plan"))
+	result.add(checkPlan(g, plan)...)
+
+	weeks := g.TotalWeeks
+	if opts.weeks > 0 && opts.weeks < weeks {
+		weeks = opts.weeks
+	}
+	progress := models.ProgressSnapshot{GeneratedAt: time.Now()}
+
+	for week := 1; week <= weeks; week++ {
+		scope := fmt.Sprintf("week %d", week)
+		content, _, err := utils.GenerateWeeklyContent(llm, g.Goal, week, progress, "", g.Language, g.Profile)
+		if err != nil {
+			result.add(fail("generate.week", scope, "%v", err))
+			continue
+		}
+		result.add(pass("generate.week", scope))
+		result.add(checkWeek(g, week, content, opts.tolerance)...)
+
+		weekJSON, _ := json.Marshal(content)
+		for day := 1; day <= opts.days && day <= len(content.DailyMilestones); day++ {
+			evaluateDay(llm, &result, g, string(weekJSON), week, day, progress)
+		}
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+func evaluateDay(llm utils.LLM, result *GoalResult, g Goal, weekJSON string, week, day int, progress models.ProgressSnapshot) {
+	scope := fmt.Sprintf("week %d day %d", week, day)
+
+	lessonJSON, resourcesJSON, _, err := utils.GenerateDailyContent(llm, g.Goal, weekJSON, week, day, progress, g.Language, g.Profile)
+	if err != nil {
+		result.add(fail("generate.day", scope, "%v", err))
+		return
+	}
+	result.add(pass("generate.day", scope))
+
+	var lesson models.LessonContent
+	_ = json.Unmarshal(lessonJSON, &lesson)
+	result.add(checkLesson(week, day, &lesson)...)
+
+	var resources []models.Resource
+	_ = json.Unmarshal(resourcesJSON, &resources)
+	result.add(checkResources(week, day, resources)...)
+
+	exercisesJSON, _, err := utils.GenerateExercisesForLesson(llm, string(lessonJSON), progress, g.Language)
+	if err != nil {
+		result.add(fail("generate.exercises", scope, "%v", err))
+		return
+	}
+	result.add(pass("generate.exercises", scope))
+
+	var exercises []models.Exercise
+	_ = json.Unmarshal(exercisesJSON, &exercises)
+	result.add(checkExercises(week, day, exercises)...)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GoalResult holds the checks run on everything generated for one goal.
+type GoalResult struct {
+	ID         string  `json:"id"`
+	Goal       string  `json:"goal"`
+	Checks     []Check `json:"checks"`
+	Passed     int     `json:"passed"`
+	Total      int     `json:"total"`
+	Score      float64 `json:"score"`
+	DurationMs int64   `json:"duration_ms"`
+}
+
+func (r *GoalResult) add(checks ...Check) {
+	for _, c := range checks {
+		r.Checks = append(r.Checks, c)
+		r.Total++
+		if c.Passed {
+			r.Passed++
+		}
+	}
+	r.Score = ratio(r.Passed, r.Total)
+}
+
+// Report is the result of an evaluation run. It is written as JSON so that a
+// later run can be compared against it with -baseline.
+type Report struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Provider    string         `json:"provider"`
+	Prompts     map[string]int `json:"prompts"`
+	Goals       []GoalResult   `json:"goals"`
+	Passed      int            `json:"passed"`
+	Total       int            `json:"total"`
+	Score       float64        `json:"score"`
+}
+
+func (r *Report) add(result GoalResult) {
+	r.Goals = append(r.Goals, result)
+	r.Passed += result.Passed
+	r.Total += result.Total
+	r.Score = ratio(r.Passed, r.Total)
+}
+
+func ratio(passed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(passed) / float64(total)
+}
+
+func readReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+func (r *Report) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Markdown renders the report, with score changes and regressed checks
+// relative to baseline when one is given.
+func (r *Report) Markdown(baseline *Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Curriculum evaluation\n\n")
+	fmt.Fprintf(&b, "- Generated: %s\n", r.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Provider: %s\n", r.Provider)
+	fmt.Fprintf(&b, "- Prompts: %s\n", formatPrompts(r.Prompts))
+	fmt.Fprintf(&b, "- Score: %d/%d (%s)%s\n", r.Passed, r.Total, percent(r.Score), delta(r.Score, baseline, func(rep *Report) (float64, bool) {
+		return rep.Score, true
+	}))
+	if baseline != nil {
+		fmt.Fprintf(&b, "- Baseline: %s, provider %s, prompts %s\n", baseline.GeneratedAt.Format(time.RFC3339), baseline.Provider, formatPrompts(baseline.Prompts))
+	}
+
+	fmt.Fprintf(&b, "\n| Goal | Passed | Score |\n|---|---|---|\n")
+	for _, g := range r.Goals {
+		id := g.ID
+		fmt.Fprintf(&b, "| %s | %d/%d | %s%s |\n", escape(g.Goal), g.Passed, g.Total, percent(g.Score), delta(g.Score, baseline, func(rep *Report) (float64, bool) {
+			for _, bg := range rep.Goals {
+				if bg.ID == id {
+					return bg.Score, true
+				}
+			}
+			return 0, false
+		}))
+	}
+
+	if baseline != nil {
+		if regressions := regressedChecks(r, baseline); len(regressions) > 0 {
+			fmt.Fprintf(&b, "\n## Regressions\n\n")
+			for _, line := range regressions {
+				fmt.Fprintf(&b, "- %s\n", line)
+			}
+		}
+	}
+
+	for _, g := range r.Goals {
+		var failed []Check
+		for _, c := range g.Checks {
+			if !c.Passed {
+				failed = append(failed, c)
+			}
+		}
+		if len(failed) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## Failed checks: %s\n\n| Scope | Check | Detail |\n|---|---|---|\n", escape(g.Goal))
+		for _, c := range failed {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", c.Scope, c.Name, escape(c.Detail))
+		}
+	}
+
+	return b.String()
+}
+
+// regressedChecks lists the checks that passed in baseline but fail now.
+func regressedChecks(current, baseline *Report) []string {
+	passedBefore := map[string]bool{}
+	for _, g := range baseline.Goals {
+		for _, c := range g.Checks {
+			if c.Passed {
+				passedBefore[g.ID+"|"+c.key()] = true
+			}
+		}
+	}
+
+	var lines []string
+	for _, g := range current.Goals {
+		for _, c := range g.Checks {
+			if !c.Passed && passedBefore[g.ID+"|"+c.key()] {
+				lines = append(lines, fmt.Sprintf("%s, %s: %s (%s)", g.ID, c.Scope, c.Name, c.Detail))
+			}
+		}
+	}
+	return lines
+}
+
+func delta(score float64, baseline *Report, lookup func(*Report) (float64, bool)) string {
+	if baseline == nil {
+		return ""
+	}
+	before, ok := lookup(baseline)
+	if !ok {
+		return " (new)"
+	}
+	diff := (score - before) * 100
+	if diff > -0.05 && diff < 0.05 {
+		return " (=)"
+	}
+	return fmt.Sprintf(" (%+.1f pts)", diff)
+}
+
+func percent(score float64) string {
+	return fmt.Sprintf("%.1f%%", score*100)
+}
+
+func formatPrompts(versions map[string]int) string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s v%d", name, versions[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
@@ -15,31 +15,51 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/learnings": {
-            "get": {
-                "description": "Retrieve all learning plans for the authenticated user",
+        "/admin/categories": {
+            "post": {
+                "description": "Create a catalog category",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "LearningPlan"
+                    "Admin"
+                ],
+                "summary": "Create Category",
+                "parameters": [
+                    {
+                        "description": "Category",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CategoryRequest"
+                        }
+                    }
                 ],
-                "summary": "Get My Learning Plans",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -47,19 +67,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}": {
-            "get": {
-                "responses": {}
-            }
-        },
-        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}/exercises": {
-            "get": {
-                "responses": {}
-            }
-        },
-        "/learnings/structure": {
-            "post": {
-                "description": "Generate and store a high-level learning plan structure for a user",
+        "/admin/categories/{id}": {
+            "put": {
+                "description": "Update a catalog category",
                 "consumes": [
                     "application/json"
                 ],
@@ -67,17 +77,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "LearningPlan"
+                    "Admin"
                 ],
-                "summary": "Generate Plan Structure",
+                "summary": "Update Category",
                 "parameters": [
                     {
-                        "description": "Learning Plan Structure Request",
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.StructureRequest"
+                            "$ref": "#/definitions/controllers.CategoryRequest"
                         }
                     }
                 ],
@@ -85,7 +102,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LearningPlanStructure"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
@@ -94,29 +111,39 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/learnings/structure/{id}": {
-            "get": {
-                "description": "Retrieve a learning plan structure by ID",
+            },
+            "delete": {
+                "description": "Delete a catalog category; its templates become uncategorised",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "LearningPlan"
+                    "Admin"
                 ],
-                "summary": "Get Plan Structure",
+                "summary": "Delete Category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Plan Structure ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -126,7 +153,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LearningPlanStructure"
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
@@ -134,39 +167,57 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/learnings/weekly-content": {
-            "post": {
-                "description": "Generate and store detailed weekly content for a learning plan",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/plans": {
+            "get": {
+                "description": "List the plans of all users (admin only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "LearningPlan"
+                    "Admin"
                 ],
-                "summary": "Generate Week Content",
+                "summary": "List Plans",
                 "parameters": [
                     {
-                        "description": "Weekly Content Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.ContentRequest"
-                        }
+                        "type": "integer",
+                        "description": "Owner user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search the goal",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GeneratedWeeklyContent"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
@@ -175,8 +226,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -184,28 +235,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/learnings/weekly-content/{week_number}/{plan_id}": {
+        "/admin/plans/{id}": {
             "get": {
-                "description": "Retrieve generated content for a specific week of a learning plan",
+                "description": "Retrieve a plan with its weeks, days, progress and adaptation flags (admin only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "LearningPlan"
+                    "Admin"
                 ],
-                "summary": "Get Week Content",
+                "summary": "Inspect Plan",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Plan ID",
-                        "name": "plan_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Week Number",
-                        "name": "week_number",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -214,7 +258,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GeneratedWeeklyContent"
+                            "$ref": "#/definitions/controllers.AdminPlan"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
@@ -224,41 +274,43 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "This API will attempt to login a user",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Delete any user's plan and all its associated data (admin only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "Login",
+                "summary": "Delete Plan",
                 "parameters": [
                     {
-                        "description": "User Details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
-                        }
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Status 200 will be returned if the login was successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -272,9 +324,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/signup": {
+        "/admin/plans/{id}/regenerate": {
             "post": {
-                "description": "This API will attempt to create a new user",
+                "description": "Force a day, a week or every generated week of a plan to be generated again, without using the owner's quota (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -282,31 +334,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "Sign Up",
+                "summary": "Regenerate Plan Content",
                 "parameters": [
                     {
-                        "description": "User Details",
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "What to regenerate",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
+                            "$ref": "#/definitions/controllers.RegenerateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created, OTP was send, show the verification page",
-                        "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
-                        }
-                    },
                     "202": {
-                        "description": "Status 202 will be returned if the signup was successfully, DONT show verification page",
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResponse"
+                            "$ref": "#/definitions/models.GenerationJob"
                         }
                     },
                     "400": {
@@ -315,180 +367,4596 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.ContentRequest": {
-            "type": "object",
-            "properties": {
-                "plan_id": {
-                    "type": "integer"
-                },
-                "user_progress": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "week_number": {
-                    "type": "integer"
-                }
-            }
         },
-        "controllers.StructureRequest": {
-            "type": "object",
-            "properties": {
-                "daily_commitment": {
-                    "type": "integer"
-                },
-                "goal": {
-                    "type": "string"
-                },
-                "total_weeks": {
-                    "type": "integer"
+        "/admin/prompts": {
+            "get": {
+                "description": "List every prompt template with its versions and the version used for generation (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Prompt Templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/prompts.Summary"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.CreateUserRequest": {
-            "type": "object",
-            "required": [
-                "daily_commitment",
-                "email",
-                "first_name",
-                "last_name",
-                "learning_goal",
-                "password"
-            ],
-            "properties": {
-                "daily_commitment": {
-                    "type": "integer"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string"
+        "/admin/prompts/{name}/preview": {
+            "get": {
+                "description": "Render a prompt template with the data generation would use for a plan, week and day, without calling the LLM (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Preview Prompt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prompt name, e.g. daily_lesson",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template version (default: active version)",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week number",
+                        "name": "week",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Day number",
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Goal, for plan_structure and validate_goal without a plan",
+                        "name": "goal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Total weeks, for plan_structure without a plan",
+                        "name": "total_weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Daily commitment in minutes, for plan_structure without a plan",
+                        "name": "daily_commitment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Exercise index, for grade_answer",
+                        "name": "exercise",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Learner answer, for grade_answer",
+                        "name": "answer",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/prompts.Rendered"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/templates": {
+            "get": {
+                "description": "List published and unpublished plan templates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List All Templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlanTemplate"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an unpublished plan template, optionally seeded from a plan structure",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create Template",
+                "parameters": [
+                    {
+                        "description": "Template",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlanTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlanTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/templates/candidates": {
+            "get": {
+                "description": "Rank generated plan structures by learner ratings to seed templates from",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Template Candidates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Minimum average rating (default 4)",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum number of ratings (default 1)",
+                        "name": "min_ratings",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TemplateCandidate"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/templates/{id}": {
+            "put": {
+                "description": "Update a plan template",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update Template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlanTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlanTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a plan template; plans started from it are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete Template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/templates/{id}/publish": {
+            "put": {
+                "description": "Publish a template to the public catalog or withdraw it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Publish Template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Publish",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PublishTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlanTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/usage/costs": {
+            "get": {
+                "description": "Report LLM token usage and cost by user, day and purpose, priced with the LLM_PRICES table (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "LLM Usage Costs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default 29 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/usage.CostReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "description": "Search users (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search email, first name and last name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "learner, mentor or admin",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active or suspended",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "description": "Retrieve a user and their plans (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "description": "Set the role of a user (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update User Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AdminUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/suspend": {
+            "post": {
+                "description": "Suspend a user and revoke their sessions (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Suspend User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SuspendUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AdminUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/unsuspend": {
+            "post": {
+                "description": "Lift the suspension of a user (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unsuspend User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AdminUser"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/google/login": {
+            "post": {
+                "description": "This API will authenticate a user with a Google ID token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Google Login",
+                "parameters": [
+                    {
+                        "description": "Google ID Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.GoogleLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the login was successful",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Revoke the current session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "description": "Revoke every session of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Logout All Devices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The refresh token is rotated; reusing an old one revokes the session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Refresh Token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthTokens"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "description": "List the active sessions (signed-in devices) of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "List Sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Session"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/categories": {
+            "get": {
+                "description": "List the categories of the plan template catalog",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "List Categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/templates": {
+            "get": {
+                "description": "Browse published plan templates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Browse Plan Templates",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "beginner, intermediate or advanced",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum total weeks",
+                        "name": "min_weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum total weeks",
+                        "name": "max_weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search title, goal and description",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlanTemplate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/templates/{id}": {
+            "get": {
+                "description": "Retrieve a published plan template",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get Plan Template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlanTemplate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/catalog/templates/{id}/start": {
+            "post": {
+                "description": "Start a new private plan from a published template with your own daily commitment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Start Plan Template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Start Template Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StartTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/forgot-password": {
+            "post": {
+                "description": "This API will attempt to send password reset OTP to user's email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Forgot Password",
+                "parameters": [
+                    {
+                        "description": "Email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the password reset OTP was sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Resend cooldown or too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Poll the status of a background generation job; the result is included once it has succeeded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get Generation Job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenerationJob"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings": {
+            "get": {
+                "description": "Retrieve all learning plans for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Get My Learning Plans",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/adaptations/{plan_id}": {
+            "get": {
+                "description": "List the adaptation flags raised for a learning plan from exercise scores and pacing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Get Adaptations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}": {
+            "get": {
+                "responses": {
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}/exercises": {
+            "get": {
+                "responses": {
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}/stream": {
+            "get": {
+                "description": "Generate daily content and stream the lesson explanation as server-sent events. \"delta\" events carry {\"html\"} fragments, a final \"done\" event carries the saved daily content and an \"error\" event reports a failure",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Stream Daily Content",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week Number",
+                        "name": "week_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Day Number",
+                        "name": "day_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}/submissions": {
+            "post": {
+                "description": "Grade answers to the exercises of a day, store the attempts and return per-question feedback. Answers to a translated day are graded against the translation in the learner's preferred language, or in the given language",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Submit Exercise Answers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week Number",
+                        "name": "week_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Day Number",
+                        "name": "day_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SubmitExercisesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SubmitExercisesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An exercise was already submitted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}/translate": {
+            "post": {
+                "description": "Translate a day's lesson, exercises and resources into another language without regenerating the curriculum. Translations are cached until the day's content changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Translate Daily Content",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week Number",
+                        "name": "week_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Day Number",
+                        "name": "day_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target language",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/daily-content/{day_number}/{week_number}/{plan_id}/tutor/threads": {
+            "get": {
+                "description": "List the tutor conversations about a day, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tutor"
+                ],
+                "summary": "List Tutor Threads",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Day Number",
+                        "name": "day_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week Number",
+                        "name": "week_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TutorThread"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Start a tutor conversation about a generated day",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tutor"
+                ],
+                "summary": "Create Tutor Thread",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Day Number",
+                        "name": "day_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week Number",
+                        "name": "week_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Thread",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateTutorThreadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TutorThread"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/flashcards/due": {
+            "get": {
+                "description": "List the flashcards due for review by the end of today (UTC), most overdue first. Cards are derived from the key points and exercises of generated days",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flashcards"
+                ],
+                "summary": "Get Due Flashcards",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only cards of this plan",
+                        "name": "plan_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum cards returned (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.DueFlashcardsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/flashcards/{id}/review": {
+            "post": {
+                "description": "Record a review with an SM-2 quality from 0 (blackout) to 5 (perfect recall) and schedule the next one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flashcards"
+                ],
+                "summary": "Review Flashcard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flashcard ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReviewFlashcardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Flashcard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/placement": {
+            "post": {
+                "description": "Generate an adaptive placement quiz for a goal. The response lists the first (intermediate) stage of questions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Placement"
+                ],
+                "summary": "Start Placement Quiz",
+                "parameters": [
+                    {
+                        "description": "Placement Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlacementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlacementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/placement/{id}": {
+            "get": {
+                "description": "Retrieve a placement diagnostic with its current questions, graded answers and, once completed, the inferred level and known concepts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Placement"
+                ],
+                "summary": "Get Placement Diagnostic",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Placement Diagnostic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlacementResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/placement/{id}/answers": {
+            "post": {
+                "description": "Grade the answers to the current stage. Passing the intermediate stage continues with advanced questions, failing it with beginner ones; the second stage completes the diagnostic. Unanswered questions count as wrong",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Placement"
+                ],
+                "summary": "Submit Placement Answers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Placement Diagnostic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SubmitPlacementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PlacementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/plan/{id}": {
+            "delete": {
+                "description": "Delete a learning plan and all its associated data",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Delete Learning Plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/progress/lesson": {
+            "post": {
+                "description": "Record that a lesson was started or completed, time spent on it and self-rated confidence",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Update Lesson Progress",
+                "parameters": [
+                    {
+                        "description": "Lesson Progress",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LessonProgressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/progress/{plan_id}": {
+            "get": {
+                "description": "Retrieve the aggregated progress of the authenticated user in a learning plan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Progress"
+                ],
+                "summary": "Get Progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/shared": {
+            "get": {
+                "description": "List plan structures other users have shared as templates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "List Shared Plans",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by goal",
+                        "name": "goal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SharedPlanTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/structure": {
+            "post": {
+                "description": "Generate and store a high-level learning plan structure for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Generate Plan Structure",
+                "parameters": [
+                    {
+                        "description": "Learning Plan Structure Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.StructureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LearningPlanStructure"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenerationJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/structure/{id}": {
+            "get": {
+                "description": "Retrieve a learning plan structure by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Get Plan Structure",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan Structure ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LearningPlanStructure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/structure/{id}/clone": {
+            "post": {
+                "description": "Copy a shared plan structure into your account as a new private plan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Clone Plan Structure",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shared Plan Structure ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/structure/{id}/rating": {
+            "post": {
+                "description": "Rate one of your plans from 1 to 5",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Rate Plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan Structure ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RatePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlanRating"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/structure/{id}/share": {
+            "put": {
+                "description": "Publish one of your plan structures as a template other users can clone, or withdraw it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Share Plan Structure",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan Structure ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Share Plan Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.SharePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LearningPlanStructure"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/tutor/threads/{id}": {
+            "get": {
+                "description": "Retrieve a tutor conversation with its messages",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tutor"
+                ],
+                "summary": "Get Tutor Thread",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Thread ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TutorThread"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a tutor conversation and its messages",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tutor"
+                ],
+                "summary": "Delete Tutor Thread",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Thread ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/tutor/threads/{id}/messages": {
+            "post": {
+                "description": "Ask a question in a tutor thread. The tutor is grounded in the day's lesson, exercises and the learner's attempts; older turns are summarized to bound the context",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tutor"
+                ],
+                "summary": "Ask the Tutor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Thread ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Question",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TutorMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TutorTurnResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/tutor/threads/{id}/messages/stream": {
+            "post": {
+                "description": "Like Ask the Tutor, but the reply is sent as server-sent events: \"delta\" events with {\"text\": \"...\"} to append, then a \"done\" event with the persisted turn or an \"error\" event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Tutor"
+                ],
+                "summary": "Ask the Tutor (streaming)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Thread ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Question",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.TutorMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/validate-goal": {
+            "post": {
+                "description": "Validate if a learning goal is appropriate for plan generation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Validate Learning Goal",
+                "parameters": [
+                    {
+                        "description": "Goal Validation Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ValidateGoalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ValidateGoalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/weekly-content": {
+            "post": {
+                "description": "Generate and store detailed weekly content for a learning plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Generate Week Content",
+                "parameters": [
+                    {
+                        "description": "Weekly Content Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ContentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GeneratedWeeklyContent"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenerationJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit or generation quota exceeded; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/learnings/weekly-content/{week_number}/{plan_id}": {
+            "get": {
+                "description": "Retrieve generated content for a specific week of a learning plan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "LearningPlan"
+                ],
+                "summary": "Get Week Content",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Plan ID",
+                        "name": "plan_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Week Number",
+                        "name": "week_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "stale is set while the week is regenerated to adapt to the learner's progress",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.WeekContentResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "This API will attempt to login a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "User Details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the login was successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "description": "This API will retrieve user profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get Profile",
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the profile was retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "This API will update user profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update Profile",
+                "parameters": [
+                    {
+                        "description": "Profile Update Data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the profile was updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quota": {
+            "get": {
+                "description": "Show the AI generations used and remaining for the current day and month",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get Generation Quota",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ratelimit.QuotaStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resend-otp": {
+            "post": {
+                "description": "This API will attempt to resend OTP to user's email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Resend OTP",
+                "parameters": [
+                    {
+                        "description": "Email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResendOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the OTP was resent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Resend cooldown or too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reset-password": {
+            "post": {
+                "description": "This API will attempt to reset user's password with OTP verification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Reset Password",
+                "parameters": [
+                    {
+                        "description": "Email, OTP, and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the password was reset successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Resend cooldown or too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "This API will attempt to create a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Sign Up",
+                "parameters": [
+                    {
+                        "description": "User Details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created, OTP was send, show the verification page",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Status 202 will be returned if the signup was successfully, DONT show verification page",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Resend cooldown or too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/verify-otp": {
+            "post": {
+                "description": "This API will attempt to verify a user's OTP",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Verify OTP",
+                "parameters": [
+                    {
+                        "description": "Email and OTP",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VerifyOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status 200 will be returned if the verification was successful",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Resend cooldown or too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.AdminPlan": {
+            "type": "object",
+            "properties": {
+                "adaptation_flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ContentAdaptationFlag"
+                    }
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyContent"
+                    }
+                },
+                "plan": {
+                    "$ref": "#/definitions/models.LearningPlanStructure"
+                },
+                "progress": {
+                    "$ref": "#/definitions/models.ProgressSnapshot"
+                },
+                "weeks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GeneratedWeeklyContent"
+                    }
+                }
+            }
+        },
+        "controllers.AdminUser": {
+            "type": "object",
+            "properties": {
+                "auth_provider": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "plan_count": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "suspension_reason": {
+                    "type": "string"
+                },
+                "tier": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.CategoryRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ContentRequest": {
+            "type": "object",
+            "properties": {
+                "plan_id": {
+                    "type": "integer"
+                },
+                "week_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.CreateTutorThreadRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string",
+                    "example": "Questions about JSX"
+                }
+            }
+        },
+        "controllers.DueFlashcardsResponse": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Flashcard"
+                    }
+                },
+                "due_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "next_due_at": {
+                    "description": "earliest review after today when nothing is due",
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ExerciseAnswer": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "exercise_index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.ExerciseFeedback": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "correct_answer": {
+                    "type": "string"
+                },
+                "exercise_index": {
+                    "type": "integer"
+                },
+                "explanation": {
+                    "type": "string"
+                },
+                "feedback": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.GoogleLoginRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.LessonProgressRequest": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "1-5",
+                    "type": "integer"
+                },
+                "day_number": {
+                    "type": "integer"
+                },
+                "plan_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "started, completed",
+                    "type": "string"
+                },
+                "time_spent_seconds": {
+                    "description": "added to the time already recorded",
+                    "type": "integer"
+                },
+                "week_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.PlacementAnswerInput": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "example": "defer"
+                },
+                "question_index": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "controllers.PlacementQuestionView": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string",
+                    "example": "defer"
+                },
+                "concept": {
+                    "type": "string",
+                    "example": "Defer"
+                },
+                "correct": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "intermediate"
+                },
+                "explanation": {
+                    "type": "string",
+                    "example": "Deferred calls run on return."
+                },
+                "given": {
+                    "type": "string",
+                    "example": "defer"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "[\"defer\"",
+                        "\"go\"",
+                        "\"finally\"]"
+                    ]
+                },
+                "question": {
+                    "type": "string",
+                    "example": "Which statement runs a function when the surrounding function returns?"
+                }
+            }
+        },
+        "controllers.PlacementRequest": {
+            "type": "object",
+            "properties": {
+                "goal": {
+                    "type": "string",
+                    "example": "Learn Go"
+                }
+            }
+        },
+        "controllers.PlacementResponse": {
+            "type": "object",
+            "properties": {
+                "answered": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PlacementQuestionView"
+                    }
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Learn Go"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "known_concepts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "language": {
+                    "type": "string",
+                    "example": "English"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "intermediate"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PlacementQuestionView"
+                    }
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.75
+                },
+                "stage": {
+                    "type": "string",
+                    "example": "intermediate"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_progress"
+                }
+            }
+        },
+        "controllers.PlanTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "daily_commitment": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "goal": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "plan_id": {
+                    "description": "PlanID seeds the template from an existing plan structure; when set the\ngoal, total weeks and structure default to the plan's.",
+                    "type": "integer"
+                },
+                "structure": {
+                    "type": "object"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_weeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.PublishTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "published": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "controllers.RatePlanRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.RegenerateRequest": {
+            "type": "object",
+            "properties": {
+                "day_number": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Content reported as outdated"
+                },
+                "week_number": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "controllers.ResendOTPRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "otp",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "otp": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "controllers.ReviewFlashcardRequest": {
+            "type": "object",
+            "properties": {
+                "quality": {
+                    "description": "Quality is the SM-2 grade: 0 blackout, 1-2 wrong, 3 hard, 4 good, 5 easy",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "controllers.SharePlanRequest": {
+            "type": "object",
+            "properties": {
+                "shared": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "controllers.StartTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "daily_commitment": {
+                    "description": "DailyCommitment in minutes; defaults to the user's profile setting",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.StructureRequest": {
+            "type": "object",
+            "properties": {
+                "clone_from_template": {
+                    "description": "CloneFromTemplate copies a shared plan with the same goal and length\ninstead of generating a new one, when such a plan exists.",
+                    "type": "boolean"
+                },
+                "daily_commitment": {
+                    "type": "integer"
+                },
+                "goal": {
+                    "type": "string"
+                },
+                "placement_diagnostic_id": {
+                    "description": "PlacementDiagnosticID is a completed placement quiz for the goal. Its\nlevel and known concepts shape the plan; the goal defaults to its goal.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "total_weeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.SubmitExercisesRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ExerciseAnswer"
+                    }
+                },
+                "language": {
+                    "description": "Language of the translation the exercises were answered in, when it is\nnot the learner's preferred language",
+                    "type": "string",
+                    "example": "Spanish"
+                }
+            }
+        },
+        "controllers.SubmitExercisesResponse": {
+            "type": "object",
+            "properties": {
+                "correct_count": {
+                    "type": "integer"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ExerciseFeedback"
+                    }
+                },
+                "total_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.SubmitPlacementRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.PlacementAnswerInput"
+                    }
+                }
+            }
+        },
+        "controllers.SuspendUserRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Abusive content"
+                }
+            }
+        },
+        "controllers.TranslateRequest": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "Language name or ISO 639-1 code, e.g. \"Spanish\" or \"es\"",
+                    "type": "string",
+                    "example": "Spanish"
+                }
+            }
+        },
+        "controllers.TutorMessageRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Why does JSX need a build step?"
+                }
+            }
+        },
+        "controllers.TutorTurnResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/models.TutorMessage"
+                },
+                "reply": {
+                    "$ref": "#/definitions/models.TutorMessage"
+                }
+            }
+        },
+        "controllers.UpdateRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "mentor"
+                }
+            }
+        },
+        "controllers.ValidateGoalRequest": {
+            "type": "object",
+            "properties": {
+                "goal": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ValidateGoalResponse": {
+            "type": "object",
+            "properties": {
+                "appropriate": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.VerifyOTPRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "otp"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "otp": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.WeekContentResponse": {
+            "type": "object",
+            "properties": {
+                "adaptive_notes": {
+                    "type": "string"
+                },
+                "daily_milestones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyMilestone"
+                    }
+                },
+                "key_concepts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "objectives": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prerequisites": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "regeneration_job_id": {
+                    "type": "integer"
+                },
+                "stale": {
+                    "type": "boolean"
+                },
+                "theme": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthTokens": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Frontend and backend web technologies"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Web Development"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "web-development"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ContentAdaptationFlag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "remedial, accelerated, manual",
+                    "type": "string",
+                    "example": "remedial"
+                },
+                "needs_regeneration": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "plan_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "User struggling with concepts"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week_number": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "daily_commitment",
+                "email",
+                "first_name",
+                "last_name",
+                "learning_goal",
+                "password"
+            ],
+            "properties": {
+                "daily_commitment": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "learning_goal": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "models.DailyContent": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "The main lesson/content for the day",
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "day_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "exercises": {
+                    "description": "Exercises for the day",
+                    "type": "object"
+                },
+                "exercises_generated_at": {
+                    "description": "ExercisesGeneratedAt is when the exercises were last generated. Attempts\nmade before it answered other questions.",
+                    "type": "string"
+                },
+                "generated_based_on": {
+                    "description": "GeneratedBasedOn records the progress and learner profile the day was generated from",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "Spanish"
+                },
+                "plan_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "prompts": {
+                    "description": "Prompt template name -\u003e version used for the content and exercises",
+                    "type": "object"
+                },
+                "resources": {
+                    "description": "List of resource links",
+                    "type": "object"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week_number": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.DailyMilestone": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "day_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Learn the basics of Go syntax."
+                },
+                "difficulty": {
+                    "type": "string",
+                    "example": "Easy"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "Introduction to Go"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DayPerformance": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "day_number": {
+                    "type": "integer"
+                },
+                "week_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "required": [
+                "error_code",
+                "error_message"
+            ],
+            "properties": {
+                "error_code": {
+                    "type": "integer"
+                },
+                "error_message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Flashcard": {
+            "type": "object",
+            "properties": {
+                "back": {
+                    "type": "string",
+                    "example": "JSX compiles to React.createElement"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "daily_content_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "day_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "ease_factor": {
+                    "type": "number",
+                    "example": 2.5
+                },
+                "front": {
+                    "type": "string",
+                    "example": "JSX compiles to _____"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval_days": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "lapses": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "last_reviewed_at": {
+                    "type": "string"
+                },
+                "plan_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "repetitions": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "source": {
+                    "description": "key_point, exercise",
+                    "type": "string",
+                    "example": "key_point"
+                },
+                "source_index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week_number": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.GeneratedWeeklyContent": {
+            "type": "object",
+            "properties": {
+                "content_data": {
+                    "description": "JSONB: stores int64",
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "generated_based_on": {
+                    "description": "JSONB: snapshot of user progress",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "type": "string",
+                    "example": "Spanish"
+                },
+                "plan_id": {
+                    "description": "FK to LearningPlanStructure",
+                    "type": "integer",
+                    "example": 1
+                },
+                "prompts": {
+                    "description": "JSONB: prompt template name -\u003e version",
+                    "type": "object"
+                },
+                "superseded_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week_number": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.GenerationJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "weekly_content"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "result": {
+                    "type": "object"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "queued, running, succeeded, failed",
+                    "type": "string",
+                    "example": "queued"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.LearningPlanStructure": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "generated_based_on": {
+                    "description": "GeneratedBasedOn records the learner profile the plan was generated for",
+                    "type": "object"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Learn React and TypeScript"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "description": "Language the plan and its content are written in",
+                    "type": "string",
+                    "example": "Spanish"
+                },
+                "placement_diagnostic_id": {
+                    "description": "PlacementDiagnosticID is the placement quiz the plan was generated from, if any",
+                    "type": "integer",
+                    "example": 4
+                },
+                "prompts": {
+                    "description": "Prompts maps the name of each prompt template used to generate the plan to its version",
+                    "type": "object"
+                },
+                "shared": {
+                    "description": "Shared plans can be cloned into other users' accounts as templates",
+                    "type": "boolean",
+                    "example": false
+                },
+                "source_plan_id": {
+                    "description": "SourcePlanID is the shared plan this one was cloned from, if any",
+                    "type": "integer",
+                    "example": 3
+                },
+                "structure": {
+                    "description": "JSONB: stores the complete structure",
+                    "type": "object"
+                },
+                "template_id": {
+                    "description": "TemplateID is the catalog template this plan was started from, if any",
+                    "type": "integer",
+                    "example": 2
+                },
+                "total_weeks": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlanRating": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Well paced"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "plan_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rating": {
+                    "description": "1-5",
+                    "type": "integer",
+                    "example": 5
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.PlanTemplate": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.Category"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "daily_commitment": {
+                    "description": "recommended minutes per day",
+                    "type": "integer",
+                    "example": 30
+                },
+                "description": {
+                    "type": "string",
+                    "example": "From JSX basics to typed state management"
+                },
+                "goal": {
+                    "type": "string",
+                    "example": "Learn React and TypeScript"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "description": "beginner, intermediate, advanced",
+                    "type": "string",
+                    "example": "beginner"
+                },
+                "published": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "source_plan_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "start_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "structure": {
+                    "type": "object"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "React and TypeScript in 8 weeks"
+                },
+                "total_weeks": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProgressSnapshot": {
+            "type": "object",
+            "properties": {
+                "average_confidence": {
+                    "type": "number"
+                },
+                "exercise_accuracy": {
+                    "type": "number"
+                },
+                "exercise_attempts": {
+                    "type": "integer"
+                },
+                "flashcard_lapses": {
+                    "description": "cards forgotten after having been learned",
+                    "type": "integer"
+                },
+                "flashcard_recall": {
+                    "description": "share of reviews recalled (quality 3 or more)",
+                    "type": "number"
+                },
+                "flashcard_reviews": {
+                    "type": "integer"
+                },
+                "flashcards": {
+                    "type": "integer"
+                },
+                "flashcards_due": {
+                    "type": "integer"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "last_day_number": {
+                    "type": "integer"
+                },
+                "last_week_number": {
+                    "type": "integer"
                 },
-                "last_name": {
-                    "type": "string"
+                "lessons_completed": {
+                    "type": "integer"
                 },
-                "learning_goal": {
-                    "type": "string"
+                "lessons_started": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "plan_id": {
+                    "type": "integer"
+                },
+                "recent_accuracy": {
+                    "description": "accuracy within RecentWeekNumber",
+                    "type": "number"
+                },
+                "recent_week_number": {
+                    "description": "latest week with exercise attempts",
+                    "type": "integer"
+                },
+                "struggling_days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DayPerformance"
+                    }
+                },
+                "total_time_spent_minutes": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.RefreshTokenRequest": {
             "type": "object",
-            "required": [
-                "error_code",
-                "error_message"
-            ],
             "properties": {
-                "error_code": {
-                    "type": "integer"
-                },
-                "error_message": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
         },
-        "models.GeneratedWeeklyContent": {
+        "models.Session": {
             "type": "object",
             "properties": {
-                "content_data": {
-                    "description": "JSONB: stores int64",
-                    "type": "object"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "generated_based_on": {
-                    "description": "JSONB: snapshot of user progress",
-                    "type": "object"
+                "expires_at": {
+                    "type": "string"
                 },
                 "id": {
-                    "type": "integer",
-                    "example": 1
+                    "type": "integer"
                 },
-                "plan_id": {
-                    "description": "FK to LearningPlanStructure",
-                    "type": "integer",
-                    "example": 1
+                "ip_address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
                 },
                 "user_id": {
                     "type": "integer",
                     "example": 1
+                }
+            }
+        },
+        "models.SharedPlanTemplate": {
+            "type": "object",
+            "properties": {
+                "goal": {
+                    "type": "string",
+                    "example": "Learn React and TypeScript"
                 },
-                "week_number": {
+                "id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 3
+                },
+                "structure": {
+                    "type": "object"
+                },
+                "total_weeks": {
+                    "type": "integer",
+                    "example": 8
                 }
             }
         },
-        "models.LearningPlanStructure": {
+        "models.SuccessResponse": {
             "type": "object",
+            "required": [
+                "message",
+                "status"
+            ],
             "properties": {
-                "createdAt": {
+                "data": {},
+                "message": {
                     "type": "string"
                 },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TemplateCandidate": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number",
+                    "example": 4.6
+                },
                 "goal": {
                     "type": "string",
                     "example": "Learn React and TypeScript"
                 },
-                "id": {
-                    "type": "integer"
+                "plan_id": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "structure": {
-                    "description": "JSONB: stores the complete structure",
-                    "type": "object"
+                "rating_count": {
+                    "type": "integer",
+                    "example": 5
                 },
                 "total_weeks": {
                     "type": "integer",
                     "example": 8
+                }
+            }
+        },
+        "models.TutorMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Why does JSX need a build step?"
                 },
-                "updatedAt": {
+                "created_at": {
                     "type": "string"
                 },
-                "user_id": {
+                "id": {
+                    "type": "integer"
+                },
+                "prompts": {
+                    "description": "Prompts maps the prompt templates used to produce an assistant reply to their versions",
+                    "type": "object"
+                },
+                "role": {
+                    "description": "user, assistant",
+                    "type": "string",
+                    "example": "user"
+                },
+                "thread_id": {
                     "type": "integer",
                     "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.LoginRequest": {
+        "models.TutorThread": {
             "type": "object",
             "properties": {
-                "email": {
+                "created_at": {
                     "type": "string"
                 },
-                "password": {
+                "day_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TutorMessage"
+                    }
+                },
+                "plan_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Why does JSX need a build step?"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week_number": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "models.SuccessResponse": {
+        "models.UpdateProfileRequest": {
             "type": "object",
-            "required": [
-                "message",
-                "status"
-            ],
             "properties": {
-                "data": {},
-                "message": {
+                "age": {
+                    "type": "integer"
+                },
+                "background": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
+                "country": {
+                    "type": "string"
+                },
+                "interests": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "preferred_language": {
+                    "type": "string"
                 }
             }
         },
@@ -520,6 +4988,172 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "prompts.Rendered": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "profile_attributes": {
+                    "description": "ProfileAttributes names the learner profile attributes the prompt contains",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "system": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "prompts.Summary": {
+            "type": "object",
+            "properties": {
+                "active_version": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/prompts.Template"
+                    }
+                }
+            }
+        },
+        "prompts.Template": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ratelimit.QuotaStatus": {
+            "type": "object",
+            "properties": {
+                "daily_limit": {
+                    "type": "integer"
+                },
+                "daily_reset_at": {
+                    "type": "string"
+                },
+                "daily_used": {
+                    "type": "integer"
+                },
+                "monthly_limit": {
+                    "type": "integer"
+                },
+                "monthly_reset_at": {
+                    "type": "string"
+                },
+                "monthly_used": {
+                    "type": "integer"
+                },
+                "tier": {
+                    "type": "string"
+                }
+            }
+        },
+        "usage.CostLine": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "failed_calls": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "usage.CostReport": {
+            "type": "object",
+            "properties": {
+                "by_day": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usage.CostLine"
+                    }
+                },
+                "by_purpose": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usage.CostLine"
+                    }
+                },
+                "by_user": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/usage.CostLine"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "prices": {
+                    "$ref": "#/definitions/usage.PriceTable"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/usage.CostLine"
+                },
+                "unpriced_models": {
+                    "description": "UnpricedModels lists models missing from the price table; their\ntokens are counted but cost nothing.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "usage.Price": {
+            "type": "object",
+            "properties": {
+                "completion": {
+                    "type": "number"
+                },
+                "prompt": {
+                    "type": "number"
+                }
+            }
+        },
+        "usage.PriceTable": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/usage.Price"
+            }
         }
     }
 }`
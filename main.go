@@ -25,24 +25,30 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(config, os.Args[2:])
+		return
+	}
+
+	if err := config.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Starting in %s with configuration:\n%s", config.Env, config)
+
 	// programmatically set swagger info
 	docs.SwaggerInfo.Title = "ai-mentor Service API"
 	docs.SwaggerInfo.Description = "This API documents exposes all the available API endpoints for AI Mentor service"
 	docs.SwaggerInfo.Version = "1.0"
-	docs.SwaggerInfo.Host = os.Getenv("BASE_URL")
+	docs.SwaggerInfo.Host = config.Server.BaseURL
 	docs.SwaggerInfo.BasePath = "/"
 	docs.SwaggerInfo.Schemes = []string{"https"}
 
 	ctx := context.Background()
-	environment := os.Getenv("ENV")
-	if environment == "" {
-		environment = "dev"
-	}
 
 	router := &app.App{}
 
 	// Initialize database
-	db, err := database.InitPostgres()
+	db, err := database.InitPostgres(config)
 
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)